@@ -16,4 +16,7 @@ type COSService interface {
 	// contentType: 文件类型，如 "image/jpeg"
 	// 返回: 完整URL
 	UploadFile(ctx context.Context, resourcePath string, fileData []byte, contentType string) (string, error)
+
+	// DeleteFile 删除COS上的文件，文件不存在时不报错
+	DeleteFile(ctx context.Context, resourcePath string) error
 }
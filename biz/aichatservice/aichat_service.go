@@ -28,20 +28,28 @@ var (
 	CONVERSATION_NOT_EXIST      = errors.New("该会话不存在")
 	AI_CHAT_PERMISSION_DENIED   = errors.New("会话权限不足")
 	MIND_MAP_NOT_EXIST          = errors.New("该导图不存在")
-	AI_CHAT_MESSAGE_MAX         = errors.New("会话长度已达上限，请开启新的会话")
+	MAP_PROPOSAL_NOT_EXIST      = errors.New("该修改建议不存在")
+	MAP_PROPOSAL_RESOLVED       = errors.New("该修改建议已处理")
+	MESSAGE_CONTENT_NOT_NULL    = errors.New("消息内容不能为空")
+	MESSAGE_NOT_EXIST           = errors.New("该消息不存在")
+	MESSAGE_NOT_EDITABLE        = errors.New("只能编辑用户消息")
 )
 
 type AiChatService struct {
 	aiChatRepo          repo.AiChatRepo
+	mindMapRepo         repo.IMindMapRepo
 	einoServer          repo.EinoServer
+	mindMapService      types.IMindMapService //采纳修改建议时写入导图
 	tabCompletionClient *eino.TabCompletionClient
 	qualityClient       *eino.QualityAssessmentClient
 }
 
-func NewAiChatService(aiChatRepo repo.AiChatRepo, einoServer repo.EinoServer) *AiChatService {
+func NewAiChatService(aiChatRepo repo.AiChatRepo, mindMapRepo repo.IMindMapRepo, einoServer repo.EinoServer, mindMapService types.IMindMapService) *AiChatService {
 	return &AiChatService{
 		aiChatRepo:          aiChatRepo,
+		mindMapRepo:         mindMapRepo,
 		einoServer:          einoServer,
+		mindMapService:      mindMapService,
 		tabCompletionClient: eino.NewTabCompletionClient(),
 		qualityClient:       eino.NewQualityAssessmentClient(),
 	}
@@ -66,9 +74,9 @@ func (a *AiChatService) ProcessUserMessage(ctx context.Context, req *types.Proce
 		return types.AgentResponse{}, err
 	}
 
-	//长度限制100
-	if len(conversation.Messages) > 100 {
-		return types.AgentResponse{}, AI_CHAT_MESSAGE_MAX
+	//对话会改写导图，需要导图编辑权限
+	if err := a.authorizeMindMap(ctx, user.UserID, conversation.MapID, entity.MindMapRoleEditor); err != nil {
+		return types.AgentResponse{}, err
 	}
 
 	//将数据写入ctx
@@ -79,14 +87,14 @@ func (a *AiChatService) ProcessUserMessage(ctx context.Context, req *types.Proce
 	//更新导图提示词
 	conversation.ProcessSystemPrompt()
 
-	//添加用户聊天记录
-	userMessage, addMsgErr := conversation.AddMessage(req.Message, entity.USER, "", nil)
-	if addMsgErr != nil {
-		zlog.CtxWarnf(ctx, "添加用户消息时出现警告: %v", addMsgErr)
+	//添加用户聊天记录，编辑或重新生成时从对应位置开出新分支
+	userMessage, isNewMessage, err := a.prepareUserTurn(ctx, conversation, req)
+	if err != nil {
+		return types.AgentResponse{}, err
 	}
 
-	//调用ai 返回ai消息
-	aiMsg, err := a.einoServer.SendMessage(ctx, conversation.Messages)
+	//调用ai 返回ai消息，较长的会话会压缩较早的对话
+	aiMsg, err := a.einoServer.SendMessage(ctx, a.buildModelContext(ctx, conversation))
 	if err != nil {
 		return types.AgentResponse{}, err
 	}
@@ -96,8 +104,10 @@ func (a *AiChatService) ProcessUserMessage(ctx context.Context, req *types.Proce
 	if addAiMsgErr != nil {
 		zlog.CtxWarnf(ctx, "添加AI消息时出现警告: %v", addAiMsgErr)
 	}
+	var toolMessage *entity.Message
 	if aiMsg.NewMapJson != "" {
-		_, addToolMsgErr := conversation.AddMessage(aiMsg.NewMapJson, entity.TOOL, aiMsg.ToolCallID, nil)
+		var addToolMsgErr error
+		toolMessage, addToolMsgErr = conversation.AddMessage(aiMsg.NewMapJson, entity.TOOL, aiMsg.ToolCallID, nil)
 		if addToolMsgErr != nil {
 			zlog.CtxWarnf(ctx, "添加工具消息时出现警告: %v", addToolMsgErr)
 		}
@@ -109,9 +119,19 @@ func (a *AiChatService) ProcessUserMessage(ctx context.Context, req *types.Proce
 		return types.AgentResponse{}, err
 	}
 
+	//工具产出的导图作为待处理的修改建议，由用户决定是否采纳
+	if toolMessage != nil {
+		proposal, err := a.createMapProposal(ctx, conversation, toolMessage, aiMsg)
+		if err != nil {
+			zlog.CtxWarnf(ctx, "保存导图修改建议失败: %v, 会话ID: %s", err, conversation.ConversationID)
+		} else if proposal != nil {
+			aiMsg.ProposalID = proposal.ProposalID
+		}
+	}
+
 	// 只对真实用户对话进行质量评估，排除SFT训练数据
 	// 使用随机沉睡+重试的简化方案
-	if conversation.IsRealUserConversation() {
+	if conversation.IsRealUserConversation() && isNewMessage {
 		// 将用户消息加入质量评估队列（异步处理，不影响聊天响应）
 		go func() {
 			// 随机沉睡 50-200ms，减少竞态条件概率
@@ -158,9 +178,9 @@ func (a *AiChatService) ProcessUserMessageStream(
 		return err
 	}
 
-	//长度限制o
-	if len(conversation.Messages) > 100 {
-		return AI_CHAT_MESSAGE_MAX
+	//对话会改写导图，需要导图编辑权限
+	if err := a.authorizeMindMap(ctx, user.UserID, conversation.MapID, entity.MindMapRoleEditor); err != nil {
+		return err
 	}
 
 	//将数据写入ctx
@@ -171,45 +191,34 @@ func (a *AiChatService) ProcessUserMessageStream(
 	//更新导图提示词
 	conversation.ProcessSystemPrompt()
 
-	//添加用户聊天记录
-	userMessage, addMsgErr := conversation.AddMessage(req.Message, entity.USER, "", nil)
-	if addMsgErr != nil {
-		zlog.CtxWarnf(ctx, "添加用户消息时出现警告: %v", addMsgErr)
-	}
-
-	//调用ai 返回ai消息
-	chunkChan, err := a.einoServer.SendMessageStream(ctx, conversation.Messages)
-	var allMsg strings.Builder
+	//添加用户聊天记录，编辑或重新生成时从对应位置开出新分支
+	userMessage, isNewMessage, err := a.prepareUserTurn(ctx, conversation, req)
 	if err != nil {
 		return err
 	}
 
-	for chunk := range chunkChan {
-		if chunk.Error != nil {
-			return chunk.Error
-		}
-
-		if err := onChunk(chunk); err != nil {
-			return err
-		}
-
-		allMsg.WriteString(chunk.Content)
-
-		if chunk.IsLast {
-			break
+	//调用ai 过程事件实时下发，导图类工具的结果在生成修改建议后通过 map_update 下发
+	aiMsg, err := a.einoServer.SendMessageStream(ctx, a.buildModelContext(ctx, conversation), func(chunk types.StreamChunk) error {
+		if chunk.Event == types.StreamEventToolResult {
+			if _, ok := parseToolMapData(chunk.Content); ok {
+				chunk.Content = ""
+			}
 		}
+		return onChunk(chunk)
+	})
+	if err != nil {
+		return err
 	}
 
 	//添加ai消息
-	aiMsg := types.AgentResponse{
-		Content: allMsg.String(),
-	}
-	_, addAiMsgErr := conversation.AddMessage(allMsg.String(), entity.ASSISTANT, "", aiMsg.ToolCalls)
+	_, addAiMsgErr := conversation.AddMessage(aiMsg.Content, entity.ASSISTANT, "", aiMsg.ToolCalls)
 	if addAiMsgErr != nil {
 		zlog.CtxWarnf(ctx, "添加AI消息时出现警告: %v", addAiMsgErr)
 	}
+	var toolMessage *entity.Message
 	if aiMsg.NewMapJson != "" {
-		_, addToolMsgErr := conversation.AddMessage(aiMsg.NewMapJson, entity.TOOL, aiMsg.ToolCallID, nil)
+		var addToolMsgErr error
+		toolMessage, addToolMsgErr = conversation.AddMessage(aiMsg.NewMapJson, entity.TOOL, aiMsg.ToolCallID, nil)
 		if addToolMsgErr != nil {
 			zlog.CtxWarnf(ctx, "添加工具消息时出现警告: %v", addToolMsgErr)
 		}
@@ -221,9 +230,47 @@ func (a *AiChatService) ProcessUserMessageStream(
 		return err
 	}
 
+	//工具产出的导图作为待处理的修改建议，由用户决定是否采纳
+	if toolMessage != nil {
+		proposal, err := a.createMapProposal(ctx, conversation, toolMessage, aiMsg)
+		if err != nil {
+			zlog.CtxWarnf(ctx, "保存导图修改建议失败: %v, 会话ID: %s", err, conversation.ConversationID)
+		} else if proposal != nil {
+			aiMsg.ProposalID = proposal.ProposalID
+		}
+	}
+
+	//导图在 tool_result 中已省略，修改建议保存失败时同样下发导图，此时不带修改建议ID
+	if _, ok := parseToolMapData(aiMsg.NewMapJson); ok {
+		mapUpdate := types.StreamChunk{
+			Event:      types.StreamEventMapUpdate,
+			ToolCallID: aiMsg.ToolCallID,
+			NewMapJson: aiMsg.NewMapJson,
+			ProposalID: aiMsg.ProposalID,
+		}
+		for _, toolCall := range aiMsg.ToolCalls {
+			if toolCall.ID == aiMsg.ToolCallID {
+				mapUpdate.ToolName = toolCall.Function.Name
+				break
+			}
+		}
+		if err := onChunk(mapUpdate); err != nil {
+			return err
+		}
+	}
+
+	err = onChunk(types.StreamChunk{
+		Event:      types.StreamEventDone,
+		Content:    aiMsg.Content,
+		ProposalID: aiMsg.ProposalID,
+	})
+	if err != nil {
+		return err
+	}
+
 	// 只对真实用户对话进行质量评估，排除SFT训练数据
 	// 使用随机沉睡+重试的简化方案
-	if conversation.IsRealUserConversation() {
+	if conversation.IsRealUserConversation() && isNewMessage {
 		// 将用户消息加入质量评估队列（异步处理，不影响聊天响应）
 		go func() {
 			// 随机沉睡 50-200ms，减少竞态条件概率
@@ -260,6 +307,10 @@ func (a *AiChatService) SaveNewConversation(ctx context.Context, req *types.Save
 		return "", AI_CHAT_PERMISSION_DENIED
 	}
 
+	if err := a.authorizeMindMap(ctx, user.UserID, req.MapID, entity.MindMapRoleEditor); err != nil {
+		return "", err
+	}
+
 	conversation, err := entity.NewConversation(user.UserID, req.MapID, req.Title, req.MapData)
 	if err != nil {
 		return "", err
@@ -281,6 +332,10 @@ func (a *AiChatService) GetConversationList(ctx context.Context, req *types.GetC
 		return nil, AI_CHAT_PERMISSION_DENIED
 	}
 
+	if err := a.authorizeMindMap(ctx, user.UserID, req.MapID, entity.MindMapRoleViewer); err != nil {
+		return nil, err
+	}
+
 	conversationList, err := a.aiChatRepo.GetMapAllConversation(ctx, req.MapID, user.UserID)
 	if err != nil {
 		return nil, err
@@ -316,6 +371,11 @@ func (a *AiChatService) GetConversation(ctx context.Context, req *types.GetConve
 		return nil, err
 	}
 
+	//被移出协作后不能再查看该导图下的会话
+	if err := a.authorizeMindMap(ctx, user.UserID, conversation.MapID, entity.MindMapRoleViewer); err != nil {
+		return nil, err
+	}
+
 	return conversation, nil
 }
 
@@ -375,6 +435,30 @@ func (a *AiChatService) GenerateMindMap(ctx context.Context, req *types.Generate
 	}
 }
 
+// authorizeMindMap 通过导图协作权限校验当前用户能否在该导图上使用AI对话
+func (a *AiChatService) authorizeMindMap(ctx context.Context, userID, mapID, requiredRole string) error {
+	if mapID == "" {
+		return MAP_ID_NOT_NULL
+	}
+
+	role, err := a.mindMapRepo.GetMindMapRole(ctx, mapID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrMindMapNotFound) {
+			return MIND_MAP_NOT_EXIST
+		}
+		return err
+	}
+	if role == "" {
+		// 无任何权限时不暴露导图是否存在
+		return MIND_MAP_NOT_EXIST
+	}
+	if !entity.MindMapRoleAllows(role, requiredRole) {
+		zlog.CtxWarnf(ctx, "导图权限不足, mapID: %s, userID: %s, role: %s, required: %s", mapID, userID, role, requiredRole)
+		return AI_CHAT_PERMISSION_DENIED
+	}
+	return nil
+}
+
 // ProcessTabCompletion 处理Tab补全请求
 func (a *AiChatService) ProcessTabCompletion(ctx context.Context, req *types.TabCompletionParams) (result string, err error) {
 	// 服务层链路追踪
@@ -396,13 +480,14 @@ func (a *AiChatService) ProcessTabCompletion(ctx context.Context, req *types.Tab
 		return "", err
 	}
 
-	// 只提取最近的一条用户消息作为历史上下文
+	if err := a.authorizeMindMap(ctx, user.UserID, conversation.MapID, entity.MindMapRoleEditor); err != nil {
+		return "", err
+	}
+
+	// 只提取当前分支最近的一条用户消息作为历史上下文
 	var recentMessages []*entity.Message
-	for i := len(conversation.Messages) - 1; i >= 0; i-- {
-		if conversation.Messages[i].Role == entity.USER {
-			recentMessages = []*entity.Message{conversation.Messages[i]}
-			break
-		}
+	if lastUserMessage := conversation.LastUserMessage(); lastUserMessage != nil {
+		recentMessages = []*entity.Message{lastUserMessage}
 	}
 
 	// 调用Tab补全客户端
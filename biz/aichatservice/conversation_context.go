@@ -0,0 +1,184 @@
+package aichatservice
+
+import (
+	"context"
+	"forge/biz/entity"
+	"forge/infra/configs"
+	"forge/infra/eino"
+	"forge/pkg/log/zlog"
+	"strings"
+	"unicode/utf8"
+)
+
+// 对话上下文参数
+const (
+	defaultContextTokenBudget = 32000 // 未配置时的上下文token预算
+	messageTokenOverhead      = 4     // 每条消息的角色、分隔符等额外开销
+	recentContextDivisor      = 2     // 压缩后最近的对话最多占剩余预算的 1/2，留出余量避免每轮都重新摘要
+	summaryMessagePrefix      = "以下是此前对话的摘要，更早的消息已省略：\n"
+	staleToolPayload          = "（该导图结果已被后续的修改取代，内容已省略，以最新导图为准）"
+)
+
+// contextTokenBudget 获取对话模型（chat 用途）的上下文token预算
+func contextTokenBudget() int {
+	aiConfig := configs.Config().GetAiChatConfig()
+	// 配置文件中的键名会被转为小写
+	if budget, ok := aiConfig.ModelContextTokenBudgets[strings.ToLower(eino.ResolveModelName(eino.ModelRoleChat))]; ok && budget > 0 {
+		return budget
+	}
+	if aiConfig.ContextTokenBudget > 0 {
+		return aiConfig.ContextTokenBudget
+	}
+	return defaultContextTokenBudget
+}
+
+// estimateTokens 粗略估算文本的token数：ASCII字符约4个一个token，其余字符（中文等）按一个token计
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+func estimateMessageTokens(msg *entity.Message) int {
+	tokens := messageTokenOverhead + estimateTokens(msg.Content)
+	for _, toolCall := range msg.ToolCalls {
+		tokens += estimateTokens(toolCall.Function.Name) + estimateTokens(toolCall.Function.Arguments)
+	}
+	return tokens
+}
+
+func estimateMessagesTokens(messages []*entity.Message) int {
+	tokens := 0
+	for _, msg := range messages {
+		tokens += estimateMessageTokens(msg)
+	}
+	return tokens
+}
+
+// buildModelContext 构建发送给模型的消息：系统提示词 + 滚动摘要 + 当前分支最近的对话，
+// 超出预算时将较早的对话合并进摘要（结果写回会话，随聊天记录一起保存），会话中的完整记录不受影响
+func (a *AiChatService) buildModelContext(ctx context.Context, conversation *entity.Conversation) []*entity.Message {
+	path := conversation.ActiveMessages()
+	if len(path) == 0 {
+		return path
+	}
+	systemMessage := path[0]
+	history := path[1:]
+
+	//跳过已被摘要覆盖的消息，摘要对应的消息不在当前分支上时摘要作废
+	summary := conversation.Summary
+	if summary != "" {
+		covered := -1
+		for i, msg := range history {
+			if msg.ID == conversation.SummaryUntilID {
+				covered = i
+				break
+			}
+		}
+		if covered >= 0 {
+			history = history[covered+1:]
+		} else {
+			summary = ""
+		}
+	}
+	history = dropStaleToolPayloads(history)
+
+	budget := contextTokenBudget()
+	fixedTokens := estimateMessageTokens(systemMessage)
+	if summary != "" {
+		fixedTokens += messageTokenOverhead + estimateTokens(summaryMessagePrefix+summary)
+	}
+	if fixedTokens+estimateMessagesTokens(history) > budget {
+		split := recentContextStart(history, (budget-fixedTokens)/recentContextDivisor)
+		if split > 0 {
+			older := history[:split]
+			history = history[split:]
+
+			newSummary, err := a.einoServer.SummarizeMessages(ctx, summary, older)
+			if err != nil {
+				// 摘要失败时直接丢弃较早的对话，保证本轮对话可以继续
+				zlog.CtxWarnf(ctx, "压缩对话上下文失败，丢弃较早的 %d 条消息: %v, 会话ID: %s", len(older), err, conversation.ConversationID)
+			} else {
+				summary = newSummary
+				conversation.UpdateSummary(newSummary, older[len(older)-1].ID)
+			}
+		}
+	}
+
+	messages := make([]*entity.Message, 0, len(history)+2)
+	messages = append(messages, systemMessage)
+	if summary != "" {
+		messages = append(messages, &entity.Message{
+			Role:    entity.SYSTEM,
+			Content: summaryMessagePrefix + summary,
+		})
+	}
+	return append(messages, history...)
+}
+
+// recentContextStart 计算保留原文的最近对话的起始下标：在预算内尽量多保留，
+// 并从用户消息处切分，保证工具调用与其结果不被拆开；最后一轮用户消息始终保留
+func recentContextStart(history []*entity.Message, tokenBudget int) int {
+	lastUser := -1
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == entity.USER {
+			lastUser = i
+			break
+		}
+	}
+	if lastUser <= 0 {
+		return 0
+	}
+
+	start, tokens := len(history), 0
+	for start > 0 {
+		tokens += estimateMessageTokens(history[start-1])
+		if tokens > tokenBudget {
+			break
+		}
+		start--
+	}
+	for start < lastUser && history[start].Role != entity.USER {
+		start++
+	}
+	return min(start, lastUser)
+}
+
+// dropStaleToolPayloads 只保留最新一次工具产出的导图，之前的导图结果已被取代，替换为简短说明
+func dropStaleToolPayloads(history []*entity.Message) []*entity.Message {
+	latest := -1
+	for i := len(history) - 1; i >= 0; i-- {
+		if isToolMapMessage(history[i]) {
+			latest = i
+			break
+		}
+	}
+	if latest < 0 {
+		return history
+	}
+
+	res := make([]*entity.Message, len(history))
+	copy(res, history)
+	for i := 0; i < latest; i++ {
+		if isToolMapMessage(res[i]) {
+			stale := *res[i]
+			stale.Content = staleToolPayload
+			res[i] = &stale
+		}
+	}
+	return res
+}
+
+func isToolMapMessage(msg *entity.Message) bool {
+	if msg.Role != entity.TOOL {
+		return false
+	}
+	_, ok := parseToolMapData(msg.Content)
+	return ok
+}
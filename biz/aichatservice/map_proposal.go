@@ -0,0 +1,211 @@
+package aichatservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"forge/biz/entity"
+	"forge/biz/generationservice"
+	"forge/biz/mindmapservice/differ"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+	"forge/util"
+	"time"
+)
+
+// 修改建议处理结果对应的工具消息质量评分，用于训练数据筛选
+const (
+	proposalAcceptedQuality = 1
+	proposalRejectedQuality = -1
+)
+
+// createMapProposal 将工具产出的导图保存为待处理的修改建议，工具输出不是导图（如搜索结果）时返回nil
+func (a *AiChatService) createMapProposal(ctx context.Context, conversation *entity.Conversation, toolMessage *entity.Message, aiMsg types.AgentResponse) (*entity.MapProposal, error) {
+	data, ok := parseToolMapData(toolMessage.Content)
+	if !ok {
+		return nil, nil
+	}
+	if _, err := data.EnsureUIDs(); err != nil {
+		return nil, fmt.Errorf("生成节点ID失败: %w", err)
+	}
+
+	//以导图当前版本为基线计算差异，权限已在对话入口校验
+	mindMap, err := a.mindMapRepo.GetMindMap(ctx, repo.NewMindMapQueryByMapID(conversation.MapID))
+	if err != nil {
+		return nil, fmt.Errorf("获取导图失败: %w", err)
+	}
+	//对话期间导图被删除（移入回收站）
+	if mindMap == nil {
+		return nil, MIND_MAP_NOT_EXIST
+	}
+
+	proposalID, err := util.GenerateStringID()
+	if err != nil {
+		return nil, err
+	}
+
+	diff := differ.Compare(&mindMap.Data, data)
+	diff.FromVersion = mindMap.Version
+
+	proposal := &entity.MapProposal{
+		ProposalID:     proposalID,
+		ConversationID: conversation.ConversationID,
+		MapID:          conversation.MapID,
+		UserID:         conversation.UserID,
+		MessageID:      toolMessage.ID,
+		ToolCallID:     toolMessage.ToolCallID,
+		Data:           *data,
+		BaseVersion:    mindMap.Version,
+		Diff:           diff,
+		Status:         entity.MapProposalPending,
+	}
+	for _, toolCall := range aiMsg.ToolCalls {
+		if toolCall.ID == toolMessage.ToolCallID {
+			proposal.ToolName = toolCall.Function.Name
+			proposal.Requirement = toolCall.Function.Arguments
+			break
+		}
+	}
+
+	if err := a.aiChatRepo.CreateMapProposal(ctx, proposal); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+// parseToolMapData 从工具输出的导图JSON中解析节点树
+func parseToolMapData(mapJSON string) (*entity.MindMapData, bool) {
+	var toolOutput map[string]interface{}
+	if err := json.Unmarshal([]byte(mapJSON), &toolOutput); err != nil {
+		return nil, false
+	}
+	root, ok := toolOutput["root"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	rootBytes, err := json.Marshal(root)
+	if err != nil {
+		return nil, false
+	}
+	data, err := generationservice.ParseAIMindMapData(rootBytes)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (a *AiChatService) ListMapProposals(ctx context.Context, req *types.ListMapProposalsParams) ([]*entity.MapProposal, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "未能从上下文中获取用户信息")
+		return nil, AI_CHAT_PERMISSION_DENIED
+	}
+
+	conversation, err := a.aiChatRepo.GetConversation(ctx, req.ConversationID, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	//被移出协作后不能再查看该导图下的修改建议
+	if err := a.authorizeMindMap(ctx, user.UserID, conversation.MapID, entity.MindMapRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return a.aiChatRepo.ListMapProposals(ctx, conversation.ConversationID, user.UserID)
+}
+
+// AcceptMapProposal 采纳修改建议：以产生建议时的版本做乐观锁写入导图，导图已被修改时返回版本冲突
+func (a *AiChatService) AcceptMapProposal(ctx context.Context, req *types.ResolveMapProposalParams) (proposal *entity.MapProposal, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.StartCustomSpan(ctx, "service.accept_map_proposal", constant.LoopSpanType_Function.String())
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, req, proposal, err)
+	}()
+
+	proposal, err = a.getPendingMapProposal(ctx, req.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	//导图权限由导图服务校验
+	baseVersion := proposal.BaseVersion
+	newVersion, err := a.mindMapService.UpdateMindMap(ctx, proposal.MapID, &types.UpdateMindMapParams{
+		Data:            &proposal.Data,
+		ExpectedVersion: &baseVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	proposal.Status = entity.MapProposalAccepted
+	proposal.AppliedVersion = newVersion
+	proposal.ResolvedAt = &now
+	if err := a.aiChatRepo.ResolveMapProposal(ctx, proposal); err != nil {
+		return nil, err
+	}
+
+	a.recordMapProposalQuality(ctx, proposal, proposalAcceptedQuality)
+	return proposal, nil
+}
+
+// RejectMapProposal 拒绝修改建议，导图保持不变
+func (a *AiChatService) RejectMapProposal(ctx context.Context, req *types.ResolveMapProposalParams) (proposal *entity.MapProposal, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.StartCustomSpan(ctx, "service.reject_map_proposal", constant.LoopSpanType_Function.String())
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, req, proposal, err)
+	}()
+
+	proposal, err = a.getPendingMapProposal(ctx, req.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	proposal.Status = entity.MapProposalRejected
+	proposal.RejectReason = req.Reason
+	proposal.ResolvedAt = &now
+	if err := a.aiChatRepo.ResolveMapProposal(ctx, proposal); err != nil {
+		return nil, err
+	}
+
+	a.recordMapProposalQuality(ctx, proposal, proposalRejectedQuality)
+	return proposal, nil
+}
+
+// getPendingMapProposal 获取当前用户待处理的修改建议，并校验导图编辑权限
+func (a *AiChatService) getPendingMapProposal(ctx context.Context, proposalID string) (*entity.MapProposal, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "未能从上下文中获取用户信息")
+		return nil, AI_CHAT_PERMISSION_DENIED
+	}
+
+	proposal, err := a.aiChatRepo.GetMapProposal(ctx, proposalID, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !proposal.IsPending() {
+		return nil, MAP_PROPOSAL_RESOLVED
+	}
+
+	if err := a.authorizeMindMap(ctx, user.UserID, proposal.MapID, entity.MindMapRoleEditor); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+// recordMapProposalQuality 将处理结果记为工具消息的质量评分，失败时仅记录日志
+func (a *AiChatService) recordMapProposalQuality(ctx context.Context, proposal *entity.MapProposal, qualityScore int) {
+	if proposal.MessageID == "" {
+		return
+	}
+	if err := a.aiChatRepo.UpdateMessageQuality(ctx, proposal.ConversationID, proposal.MessageID, qualityScore); err != nil {
+		zlog.CtxWarnf(ctx, "记录修改建议处理结果失败: %v, 建议ID: %s", err, proposal.ProposalID)
+	}
+}
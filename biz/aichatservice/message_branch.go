@@ -0,0 +1,81 @@
+package aichatservice
+
+import (
+	"context"
+	"forge/biz/entity"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+	"strings"
+)
+
+// prepareUserTurn 确定本轮对话的用户消息：
+// 普通发送接在当前分支末尾；编辑时在原消息的父消息下开出新分支；
+// 重新生成时复用当前分支最后一条用户消息，新的回复与旧回复互为兄弟分支。
+// isNewMessage 表示是否新增了用户消息
+func (a *AiChatService) prepareUserTurn(ctx context.Context, conversation *entity.Conversation, req *types.ProcessUserMessageParams) (userMessage *entity.Message, isNewMessage bool, err error) {
+	if req.Regenerate {
+		userMessage = conversation.LastUserMessage()
+		if userMessage == nil {
+			return nil, false, MESSAGE_NOT_EXIST
+		}
+		conversation.Checkout(userMessage.ID)
+		return userMessage, false, nil
+	}
+
+	if strings.TrimSpace(req.Message) == "" {
+		return nil, false, MESSAGE_CONTENT_NOT_NULL
+	}
+
+	if req.EditMessageID != "" {
+		editMessage := conversation.FindMessage(req.EditMessageID)
+		if editMessage == nil {
+			return nil, false, MESSAGE_NOT_EXIST
+		}
+		if editMessage.Role != entity.USER {
+			return nil, false, MESSAGE_NOT_EDITABLE
+		}
+		conversation.Checkout(editMessage.ParentID)
+	}
+
+	userMessage, addMsgErr := conversation.AddMessage(req.Message, entity.USER, "", nil)
+	if addMsgErr != nil {
+		zlog.CtxWarnf(ctx, "添加用户消息时出现警告: %v", addMsgErr)
+	}
+	return userMessage, true, nil
+}
+
+// SwitchMessageBranch 切换到指定消息所在的分支，后续对话在该分支上继续
+func (a *AiChatService) SwitchMessageBranch(ctx context.Context, req *types.SwitchMessageBranchParams) (conversation *entity.Conversation, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.StartCustomSpan(ctx, "service.switch_message_branch", constant.LoopSpanType_Function.String())
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, req, conversation, err)
+	}()
+
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "未能从上下文中获取用户信息")
+		return nil, AI_CHAT_PERMISSION_DENIED
+	}
+
+	conversation, err = a.aiChatRepo.GetConversation(ctx, req.ConversationID, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	//被移出协作后不能再查看该导图下的会话
+	if err := a.authorizeMindMap(ctx, user.UserID, conversation.MapID, entity.MindMapRoleViewer); err != nil {
+		return nil, err
+	}
+
+	if !conversation.SwitchBranch(req.MessageID) {
+		return nil, MESSAGE_NOT_EXIST
+	}
+
+	if err := a.aiChatRepo.UpdateConversationMessage(ctx, conversation); err != nil {
+		return nil, err
+	}
+	return conversation, nil
+}
@@ -26,7 +26,8 @@ const (
 type aiChatCtxKey struct{}
 
 type Message struct {
-	ID           string            `json:"id" ` // 消息唯一ID
+	ID           string            `json:"id" `                 // 消息唯一ID
+	ParentID     string            `json:"parent_id,omitempty"` // 上一条消息ID，同一父消息下的多条消息互为分支
 	Content      string            `json:"content"`
 	Role         string            `json:"role" `
 	ToolCallID   string            `json:"tool_call_id,omitempty" `
@@ -36,14 +37,17 @@ type Message struct {
 }
 
 type Conversation struct {
-	ConversationID string
-	UserID         string
-	MapID          string
-	Title          string
-	MapData        string
-	Messages       []*Message
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	ConversationID   string
+	UserID           string
+	MapID            string
+	Title            string
+	MapData          string
+	Messages         []*Message
+	CurrentMessageID string // 当前分支最后一条消息ID
+	Summary          string // 较早对话的滚动摘要，替代这部分消息发送给模型
+	SummaryUntilID   string // 摘要覆盖到的最后一条消息ID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 func NewConversation(userID, mapID, title, mapData string) (*Conversation, error) {
@@ -80,8 +84,11 @@ func (c *Conversation) AddMessage(content, role, ToolCallID string, ToolCalls []
 		err = fmt.Errorf("ID生成失败，使用备选方案: %w", err)
 	}
 
+	// 新消息接在当前分支末尾
+	c.ensureMessageTree()
 	message := &Message{
 		ID:         messageID,
+		ParentID:   c.CurrentMessageID,
 		Content:    content,
 		Role:       role,
 		ToolCallID: ToolCallID,
@@ -90,6 +97,7 @@ func (c *Conversation) AddMessage(content, role, ToolCallID string, ToolCalls []
 	}
 
 	c.Messages = append(c.Messages, message)
+	c.CurrentMessageID = message.ID
 	c.UpdatedAt = now
 	return message, err // 返回消息和可能的警告错误
 }
@@ -110,7 +118,10 @@ func (c *Conversation) ProcessSystemPrompt() {
 	if len(c.Messages) == 0 {
 		c.AddMessage(text, SYSTEM, "", nil)
 	} else {
+		// 系统提示词是消息树的根，需要保留ID
+		c.ensureMessageTree()
 		c.Messages[0] = &Message{
+			ID:        c.Messages[0].ID,
 			Content:   text,
 			Role:      SYSTEM,
 			Timestamp: time.Now(),
@@ -118,6 +129,12 @@ func (c *Conversation) ProcessSystemPrompt() {
 	}
 }
 
+// UpdateSummary 更新滚动摘要及其覆盖到的最后一条消息
+func (c *Conversation) UpdateSummary(summary, untilMessageID string) {
+	c.Summary = summary
+	c.SummaryUntilID = untilMessageID
+}
+
 func WithConversation(ctx context.Context, conversation *Conversation) context.Context {
 	ctx = context.WithValue(ctx, aiChatCtxKey{}, conversation)
 	return ctx
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"fmt"
+	"forge/util"
+	"time"
+)
+
+// ensureMessageTree 兼容分支功能之前的会话：没有父消息的消息按保存顺序串成一条分支，
+// 未记录当前分支时以最后一条消息为当前分支末尾
+func (c *Conversation) ensureMessageTree() {
+	if len(c.Messages) == 0 {
+		return
+	}
+	// 系统提示词曾在更新时丢失ID，补上以便作为根消息被引用
+	if c.Messages[0].ID == "" {
+		rootID, err := util.GenerateStringID()
+		if err != nil {
+			rootID = fmt.Sprintf("%s_%d", c.ConversationID, time.Now().UnixNano())
+		}
+		c.Messages[0].ID = rootID
+	}
+	for i := 1; i < len(c.Messages); i++ {
+		if c.Messages[i].ParentID == "" {
+			c.Messages[i].ParentID = c.Messages[i-1].ID
+		}
+	}
+	if c.FindMessage(c.CurrentMessageID) == nil {
+		c.CurrentMessageID = c.Messages[len(c.Messages)-1].ID
+	}
+}
+
+// FindMessage 按ID查找消息，不存在时返回nil
+func (c *Conversation) FindMessage(messageID string) *Message {
+	if messageID == "" {
+		return nil
+	}
+	for _, msg := range c.Messages {
+		if msg.ID == messageID {
+			return msg
+		}
+	}
+	return nil
+}
+
+// ActiveMessages 当前分支上从系统提示词到最后一条消息的完整消息链
+func (c *Conversation) ActiveMessages() []*Message {
+	c.ensureMessageTree()
+
+	byID := make(map[string]*Message, len(c.Messages))
+	for _, msg := range c.Messages {
+		byID[msg.ID] = msg
+	}
+
+	path := make([]*Message, 0)
+	// 以消息总数为上限，避免脏数据成环时死循环
+	for msg := byID[c.CurrentMessageID]; msg != nil && len(path) < len(c.Messages); msg = byID[msg.ParentID] {
+		path = append(path, msg)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// LastUserMessage 当前分支上最后一条用户消息
+func (c *Conversation) LastUserMessage() *Message {
+	path := c.ActiveMessages()
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].Role == USER {
+			return path[i]
+		}
+	}
+	return nil
+}
+
+// Checkout 将当前分支末尾移动到指定消息，之后添加的消息作为它的新分支
+func (c *Conversation) Checkout(messageID string) bool {
+	c.ensureMessageTree()
+	if c.FindMessage(messageID) == nil {
+		return false
+	}
+	c.CurrentMessageID = messageID
+	return true
+}
+
+// SwitchBranch 切换到指定消息所在的分支，并沿最新的子消息走到该分支末尾
+func (c *Conversation) SwitchBranch(messageID string) bool {
+	if !c.Checkout(messageID) {
+		return false
+	}
+	for step := 0; step < len(c.Messages); step++ {
+		var latestChild *Message
+		for _, msg := range c.Messages {
+			if msg.ParentID == c.CurrentMessageID {
+				latestChild = msg
+			}
+		}
+		if latestChild == nil {
+			return true
+		}
+		c.CurrentMessageID = latestChild.ID
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// 导图修改建议状态
+const (
+	MapProposalPending    = "pending"    // 待用户处理
+	MapProposalAccepted   = "accepted"   // 已采纳并写入导图
+	MapProposalRejected   = "rejected"   // 已拒绝
+	MapProposalSuperseded = "superseded" // 未处理前同一会话产生了新的建议
+)
+
+// MapProposal AI工具产出的导图修改建议，用户采纳后才写入导图
+type MapProposal struct {
+	ProposalID     string
+	ConversationID string
+	MapID          string
+	UserID         string
+	MessageID      string // 对应的工具消息ID，处理结果作为该消息的质量评分
+	ToolCallID     string
+	ToolName       string
+	Requirement    string       // 工具调用参数（修改需求）
+	Data           MindMapData  // 建议的完整节点树
+	BaseVersion    int64        // 产生建议时导图的版本号，采纳时以此做乐观锁校验
+	Diff           *MindMapDiff // 相对 BaseVersion 的结构差异
+	Status         string       // 见 MapProposal* 常量
+	AppliedVersion int64        // 采纳后导图的版本号
+	RejectReason   string
+	ResolvedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// IsPending 是否仍待用户处理
+func (p *MapProposal) IsPending() bool {
+	return p.Status == MapProposalPending
+}
@@ -21,13 +21,28 @@ type MindMap struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
-	// Version   int64 // TODO: 版本字段用于乐观锁，前期注释
+	Version   int64    // 版本号，用于乐观锁，每次写入自增
+	FolderID  string   // 所属文件夹ID，为空表示未归档
+	TagIDs    []string // 标签ID列表，仅列表查询时填充
+
+	ThumbnailURL     string // 缩略图地址，保存后异步生成，可能落后于当前版本
+	ThumbnailVersion int64  // 缩略图对应的导图版本号
 }
 
 // NodeData 节点数据值对象
 type NodeData struct {
+	UID  string // 节点唯一标识，创建后保持不变，用于节点级操作寻址
 	Text string
-	// 可扩展其他节点属性，如颜色、图标等
+
+	// 节点属性，按需扩展
+	Note           string   // 备注
+	Hyperlink      string   // 超链接地址
+	HyperlinkTitle string   // 超链接标题
+	Color          string   // 文字颜色
+	Icon           []string // 图标列表
+	Tags           []string // 标签列表
+	Collapsed      bool     // 是否折叠子节点
+	IsActive       bool     // 是否处于激活（选中）状态
 }
 
 // MindMapData 思维导图数据值对象 - 递归树结构
@@ -36,6 +51,35 @@ type MindMapData struct {
 	Children []MindMapData // 子节点（递归结构）
 }
 
+// MindMapRevision 思维导图历史版本 - 每次写入生成一条不可变的快照
+type MindMapRevision struct {
+	MapID     string
+	Version   int64  // 该快照对应的导图版本号
+	UserID    string // 产生该版本的用户ID
+	Title     string
+	Desc      string
+	Layout    string
+	Data      MindMapData
+	Source    string // 版本来源，见 RevisionSource* 常量
+	CreatedAt time.Time
+}
+
+// 历史版本来源
+const (
+	RevisionSourceCreate  = "create"  // 创建导图
+	RevisionSourceUpdate  = "update"  // 普通更新
+	RevisionSourceRestore = "restore" // 从历史版本恢复
+	RevisionSourceCollab  = "collab"  // 实时协同编辑
+)
+
+// MindMapLinkScheme 节点超链接指向站内导图时使用的协议前缀，前端据此跳转到对应导图
+const MindMapLinkScheme = "mindmap://"
+
+// MindMapLink 生成指向站内导图的超链接
+func MindMapLink(mapID string) string {
+	return MindMapLinkScheme + mapID
+}
+
 // 上下文助手
 type mindMapCtxKey struct{}
 
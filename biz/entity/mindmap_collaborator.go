@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// 导图协作角色，权限由低到高
+const (
+	MindMapRoleViewer    = "viewer"    // 只读
+	MindMapRoleCommenter = "commenter" // 只读，可评论
+	MindMapRoleEditor    = "editor"    // 可编辑内容
+	MindMapRoleOwner     = "owner"     // 所有者，可管理分享与协作者；由导图的 UserID 决定，不落协作者表
+)
+
+var mindMapRoleLevels = map[string]int{
+	MindMapRoleViewer:    1,
+	MindMapRoleCommenter: 2,
+	MindMapRoleEditor:    3,
+	MindMapRoleOwner:     4,
+}
+
+// MindMapCollaborator 思维导图协作者
+type MindMapCollaborator struct {
+	MapID     string
+	UserID    string
+	Role      string // 见 MindMapRole* 常量，不包含 owner
+	InvitedBy string // 邀请人用户ID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// 展示信息，由服务层按需填充，不持久化
+	UserName string
+	Avatar   string
+}
+
+// MindMapRoleAllows 判断角色是否满足所需的最低权限
+func MindMapRoleAllows(role, required string) bool {
+	level, ok := mindMapRoleLevels[role]
+	return ok && level >= mindMapRoleLevels[required]
+}
+
+// IsValidCollaboratorRole 是否为可授予协作者的角色（owner 不可授予）
+func IsValidCollaboratorRole(role string) bool {
+	return role == MindMapRoleViewer || role == MindMapRoleCommenter || role == MindMapRoleEditor
+}
@@ -0,0 +1,55 @@
+package entity
+
+// 节点变更类型
+const (
+	NodeChangeAdded     = "added"     // 新增节点
+	NodeChangeRemoved   = "removed"   // 删除节点
+	NodeChangeMoved     = "moved"     // 移动到其他父节点下
+	NodeChangeRenamed   = "renamed"   // 文本变化
+	NodeChangeReordered = "reordered" // 同一父节点下的顺序变化
+)
+
+// 节点匹配方式
+const (
+	NodeMatchByUID  = "uid"  // UID相同
+	NodeMatchByText = "text" // UID缺失或不一致时按文本相似度匹配
+)
+
+// MindMapNodeChange 单个节点的结构变更，同一节点同时改名与移动时分别记录
+type MindMapNodeChange struct {
+	Type         string // 见 NodeChange* 常量
+	OldUID       string // 新增节点为空
+	NewUID       string // 删除节点为空
+	OldText      string
+	NewText      string
+	OldParentUID string
+	NewParentUID string
+	OldIndex     int      // 在原父节点中的下标，新增节点为 -1
+	NewIndex     int      // 在新父节点中的下标，删除节点为 -1
+	Path         []string // 根节点到该节点的文本路径，删除节点取原树路径，其余取新树路径
+	MatchedBy    string   // 新旧节点的匹配方式，新增/删除节点为空
+	Similarity   float64  // 文本相似度（0~1），按UID匹配时为文本本身的相似度
+}
+
+// MindMapDiffStats 变更统计
+type MindMapDiffStats struct {
+	Added     int
+	Removed   int
+	Moved     int
+	Renamed   int
+	Reordered int
+	Unchanged int // 匹配成功且无任何变化的节点数
+}
+
+// MindMapDiff 两棵节点树之间的结构差异
+type MindMapDiff struct {
+	FromVersion int64 // 为0表示非历史版本（如任意传入的数据）
+	ToVersion   int64
+	Changes     []MindMapNodeChange // 先列出删除节点（原树先序），再按新树先序列出其余变更
+	Stats       MindMapDiffStats
+}
+
+// HasChanges 是否存在结构变更
+func (d *MindMapDiff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// MindMapFolderMaxDepth 文件夹最大嵌套层数（顶层文件夹为第1层）
+const MindMapFolderMaxDepth = 8
+
+// MindMapFolder 思维导图文件夹，支持嵌套，仅对创建者可见
+type MindMapFolder struct {
+	FolderID  string
+	UserID    string
+	ParentID  string // 父文件夹ID，为空表示顶层文件夹
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MindMapTag 思维导图标签，仅对创建者可见，同一用户下名称唯一
+type MindMapTag struct {
+	TagID     string
+	UserID    string
+	Name      string
+	Color     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
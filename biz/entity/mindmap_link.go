@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// 节点链接类型
+const (
+	NodeLinkTypeReference   = "reference"    // 引用
+	NodeLinkTypeRelated     = "related"      // 相关
+	NodeLinkTypeDependsOn   = "depends_on"   // 依赖于
+	NodeLinkTypeDerivedFrom = "derived_from" // 来源于
+)
+
+// 节点链接失效原因
+const (
+	NodeLinkBrokenMapDeleted  = "map_deleted"  // 目标导图已删除
+	NodeLinkBrokenNodeDeleted = "node_deleted" // 目标节点已从导图中删除
+)
+
+// MindMapNodeLink 跨导图节点链接：从源导图的节点指向另一导图或其中的节点
+type MindMapNodeLink struct {
+	LinkID        string
+	SourceMapID   string
+	SourceNodeUID string
+	TargetMapID   string
+	TargetNodeUID string // 为空表示指向整个导图
+	Type          string // 见 NodeLinkType* 常量
+	Label         string // 链接说明
+	UserID        string // 创建者
+	BrokenReason  string // 失效原因，为空表示链接有效，见 NodeLinkBroken* 常量
+	BrokenAt      *time.Time
+	CreatedAt     time.Time
+
+	// 展示信息，仅查询时填充，不持久化；对当前用户不可见的导图为空
+	SourceMapTitle string
+	SourceNodeText string
+	TargetMapTitle string
+	TargetNodeText string
+}
+
+// IsBroken 链接是否已失效
+func (l *MindMapNodeLink) IsBroken() bool {
+	return l.BrokenReason != ""
+}
+
+// IsValidNodeLinkType 是否为支持的链接类型
+func IsValidNodeLinkType(linkType string) bool {
+	switch linkType {
+	case NodeLinkTypeReference, NodeLinkTypeRelated, NodeLinkTypeDependsOn, NodeLinkTypeDerivedFrom:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package entity
+
+// 检查问题级别
+const (
+	LintSeverityError   = "error"   // 结构错误，应当修复
+	LintSeverityWarning = "warning" // 影响可读性，建议修复
+	LintSeverityInfo    = "info"    // 结构建议
+)
+
+// 检查规则
+const (
+	LintRuleEmptyText        = "empty_text"        // 空文本节点
+	LintRulePlaceholderText  = "placeholder_text"  // 占位符文本
+	LintRuleDuplicateSibling = "duplicate_sibling" // 同级节点文本重复
+	LintRuleLongText         = "long_text"         // 节点文本过长
+	LintRuleTooDeep          = "too_deep"          // 层级过深
+	LintRuleTooShallow       = "too_shallow"       // 层级过浅
+	LintRuleUnbalanced       = "unbalanced_branch" // 分支规模失衡
+)
+
+// MindMapLintIssue 导图检查发现的单个问题
+type MindMapLintIssue struct {
+	Rule     string   // 见 LintRule* 常量
+	Severity string   // 见 LintSeverity* 常量
+	Message  string   // 问题说明
+	NodeUID  string   // 问题所在节点
+	Path     []string // 根节点到问题节点的文本路径
+}
+
+// MindMapLintReport 导图检查报告
+type MindMapLintReport struct {
+	MapID   string
+	Version int64
+
+	// 与训练数据质量评估一致的评分
+	FormatScore   float64
+	ContentScore  float64
+	OverallScore  float64
+	QualityIssues []string // 整体质量问题（不定位到具体节点）
+
+	NodeCount     int
+	MaxDepth      int // 含根节点
+	AvgTextLength float64
+
+	Issues []MindMapLintIssue // 定位到节点的问题，按节点先序排列
+}
@@ -0,0 +1,126 @@
+package entity
+
+import "forge/util"
+
+// Clone 深拷贝节点树，修改副本不会影响原树
+func (d MindMapData) Clone() MindMapData {
+	clone := MindMapData{Data: d.Data}
+	if d.Data.Icon != nil {
+		clone.Data.Icon = append([]string{}, d.Data.Icon...)
+	}
+	if d.Data.Tags != nil {
+		clone.Data.Tags = append([]string{}, d.Data.Tags...)
+	}
+	if d.Children != nil {
+		clone.Children = make([]MindMapData, len(d.Children))
+		for i := range d.Children {
+			clone.Children[i] = d.Children[i].Clone()
+		}
+	}
+	return clone
+}
+
+// Walk 深度优先遍历节点树，parent 为空表示根节点，fn 返回 false 时停止遍历
+func (d *MindMapData) Walk(fn func(node, parent *MindMapData, depth int) bool) {
+	d.walk(nil, 0, fn)
+}
+
+func (d *MindMapData) walk(parent *MindMapData, depth int, fn func(node, parent *MindMapData, depth int) bool) bool {
+	if !fn(d, parent, depth) {
+		return false
+	}
+	for i := range d.Children {
+		if !d.Children[i].walk(d, depth+1, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindNode 按UID查找节点，返回节点、父节点及其在父节点中的下标
+// 注意：返回的指针指向 Children 切片内部，切片增删后需要重新查找
+func (d *MindMapData) FindNode(uid string) (node, parent *MindMapData, index int) {
+	if uid == "" {
+		return nil, nil, -1
+	}
+	index = -1
+	d.Walk(func(n, p *MindMapData, _ int) bool {
+		if n.Data.UID != uid {
+			return true
+		}
+		node, parent = n, p
+		if p != nil {
+			for i := range p.Children {
+				if &p.Children[i] == n {
+					index = i
+					break
+				}
+			}
+		}
+		return false
+	})
+	return node, parent, index
+}
+
+// CountNodes 统计节点总数（包含根节点）
+func (d *MindMapData) CountNodes() int {
+	count := 0
+	d.Walk(func(_, _ *MindMapData, _ int) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// EnsureUIDs 为缺失UID或UID重复的节点生成新UID，返回是否有节点被修改
+func (d *MindMapData) EnsureUIDs() (changed bool, err error) {
+	seen := make(map[string]struct{})
+	d.Walk(func(n, _ *MindMapData, _ int) bool {
+		if _, dup := seen[n.Data.UID]; n.Data.UID != "" && !dup {
+			seen[n.Data.UID] = struct{}{}
+			return true
+		}
+		uid, genErr := util.GenerateStringID()
+		if genErr != nil {
+			err = genErr
+			return false
+		}
+		n.Data.UID = uid
+		seen[uid] = struct{}{}
+		changed = true
+		return true
+	})
+	return changed, err
+}
+
+// ClearUIDs 清除全部节点的UID及选中状态，用于将节点树作为模板或副本重新使用
+func (d *MindMapData) ClearUIDs() {
+	d.Walk(func(n, _ *MindMapData, _ int) bool {
+		n.Data.UID = ""
+		n.Data.IsActive = false
+		return true
+	})
+}
+
+// RegenerateUIDs 为全部节点重新生成UID，返回旧UID到新UID的映射（原本没有UID的节点不记录）
+func (d *MindMapData) RegenerateUIDs() (map[string]string, error) {
+	mapping := make(map[string]string)
+	var err error
+	d.Walk(func(n, _ *MindMapData, _ int) bool {
+		uid, genErr := util.GenerateStringID()
+		if genErr != nil {
+			err = genErr
+			return false
+		}
+		if n.Data.UID != "" {
+			mapping[n.Data.UID] = uid
+		}
+		n.Data.UID = uid
+		n.Data.IsActive = false
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
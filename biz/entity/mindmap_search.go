@@ -0,0 +1,39 @@
+package entity
+
+// MindMapNodeDoc 全文检索文档 - 导图标题或单个节点在检索索引中的一条记录
+type MindMapNodeDoc struct {
+	MapID   string
+	NodeUID string   // 为空表示导图标题文档
+	Text    string   // 节点文本（标题文档为导图标题）
+	Note    string   // 节点备注
+	Path    []string // 从根节点到该节点（含）的节点文本，标题文档为空
+	Depth   int      // 节点深度，根节点为0，标题文档为-1
+}
+
+// IsTitle 是否为导图标题文档
+func (d *MindMapNodeDoc) IsTitle() bool {
+	return d.NodeUID == ""
+}
+
+// BuildSearchDocs 将导图标题及全部节点展开为检索文档
+func (m *MindMap) BuildSearchDocs() []*MindMapNodeDoc {
+	docs := []*MindMapNodeDoc{{MapID: m.MapID, Text: m.Title, Depth: -1}}
+
+	path := make([]string, 0)
+	m.Data.Walk(func(node, _ *MindMapData, depth int) bool {
+		path = append(path[:depth], node.Data.Text)
+		if node.Data.UID == "" || (node.Data.Text == "" && node.Data.Note == "") {
+			return true
+		}
+		docs = append(docs, &MindMapNodeDoc{
+			MapID:   m.MapID,
+			NodeUID: node.Data.UID,
+			Text:    node.Data.Text,
+			Note:    node.Data.Note,
+			Path:    append([]string{}, path...),
+			Depth:   depth,
+		})
+		return true
+	})
+	return docs
+}
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// MindMapShare 思维导图只读分享链接
+type MindMapShare struct {
+	ShareID      string
+	Token        string // 分享令牌，出现在公开链接中
+	MapID        string
+	UserID       string     // 创建分享的导图所有者
+	PasswordHash string     // 访问密码哈希，为空表示无需密码
+	ExpiresAt    *time.Time // 过期时间，为空表示永久有效
+	RevokedAt    *time.Time // 撤销时间，为空表示未撤销
+	HitCount     int64      // 访问次数
+	LastHitAt    *time.Time // 最近一次访问时间
+	CreatedAt    time.Time
+}
+
+// HasPassword 是否设置了访问密码
+func (s *MindMapShare) HasPassword() bool {
+	return s.PasswordHash != ""
+}
+
+// IsRevoked 是否已撤销
+func (s *MindMapShare) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// IsExpired 在指定时间是否已过期
+func (s *MindMapShare) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// MindMapTemplate 思维导图模板，内置模板对所有用户可见，用户模板仅对创建者可见
+type MindMapTemplate struct {
+	TemplateID string
+	UserID     string // 创建者，内置模板为空
+	Name       string
+	Desc       string
+	Category   string // 模板分类，见 TemplateCategory* 常量
+	Layout     string
+	Data       MindMapData // 节点不含UID，创建导图时重新生成
+	BuiltIn    bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// 模板分类
+const (
+	TemplateCategoryAnalysis = "analysis" // 分析决策
+	TemplateCategoryReading  = "reading"  // 读书笔记
+	TemplateCategoryProject  = "project"  // 项目管理
+	TemplateCategoryStudy    = "study"    // 学习教学
+	TemplateCategoryCustom   = "custom"   // 用户保存的模板
+)
+
+// 占位节点文本形如 {{提示语}}，创建导图时由AI按用户文本填充，未填充的替换为提示语本身
+const (
+	placeholderOpen  = "{{"
+	placeholderClose = "}}"
+)
+
+// PlaceholderHint 节点为占位节点时返回其提示语
+func (n NodeData) PlaceholderHint() (string, bool) {
+	text := strings.TrimSpace(n.Text)
+	if !strings.HasPrefix(text, placeholderOpen) || !strings.HasSuffix(text, placeholderClose) ||
+		len(text) < len(placeholderOpen)+len(placeholderClose) {
+		return "", false
+	}
+	return strings.TrimSpace(text[len(placeholderOpen) : len(text)-len(placeholderClose)]), true
+}
+
+// HasPlaceholders 节点树中是否包含占位节点
+func (d *MindMapData) HasPlaceholders() bool {
+	found := false
+	d.Walk(func(n, _ *MindMapData, _ int) bool {
+		_, found = n.Data.PlaceholderHint()
+		return !found
+	})
+	return found
+}
+
+// ResolvePlaceholders 将全部占位节点的文本替换为其提示语
+func (d *MindMapData) ResolvePlaceholders() {
+	d.Walk(func(n, _ *MindMapData, _ int) bool {
+		if hint, ok := n.Data.PlaceholderHint(); ok {
+			n.Data.Text = hint
+		}
+		return true
+	})
+}
@@ -0,0 +1,58 @@
+package generationservice
+
+import (
+	"encoding/json"
+
+	"forge/biz/entity"
+)
+
+// aiNodeData AI输出的节点数据，字段与导图JSON Schema及前端保持一致
+type aiNodeData struct {
+	UID            string   `json:"uid"`
+	Text           string   `json:"text"`
+	Note           string   `json:"note"`
+	Hyperlink      string   `json:"hyperlink"`
+	HyperlinkTitle string   `json:"hyperlinkTitle"`
+	Color          string   `json:"color"`
+	Icon           []string `json:"icon"`
+	Tag            []string `json:"tag"`
+	Expand         *bool    `json:"expand"` // 不输出时默认展开
+}
+
+// aiMindMapData AI输出的导图节点树
+type aiMindMapData struct {
+	Data     aiNodeData      `json:"data"`
+	Children []aiMindMapData `json:"children"`
+}
+
+// ParseAIMindMapData 将AI输出的导图根节点JSON（root字段）转换为节点树实体：
+// tag 对应节点标签，expand 为 false 时节点折叠
+func ParseAIMindMapData(rootJSON []byte) (*entity.MindMapData, error) {
+	var root aiMindMapData
+	if err := json.Unmarshal(rootJSON, &root); err != nil {
+		return nil, err
+	}
+	data := root.toEntity()
+	return &data, nil
+}
+
+func (d aiMindMapData) toEntity() entity.MindMapData {
+	children := make([]entity.MindMapData, 0, len(d.Children))
+	for _, child := range d.Children {
+		children = append(children, child.toEntity())
+	}
+	return entity.MindMapData{
+		Data: entity.NodeData{
+			UID:            d.Data.UID,
+			Text:           d.Data.Text,
+			Note:           d.Data.Note,
+			Hyperlink:      d.Data.Hyperlink,
+			HyperlinkTitle: d.Data.HyperlinkTitle,
+			Color:          d.Data.Color,
+			Icon:           d.Data.Icon,
+			Tags:           d.Data.Tag,
+			Collapsed:      d.Data.Expand != nil && !*d.Data.Expand,
+		},
+		Children: children,
+	}
+}
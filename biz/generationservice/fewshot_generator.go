@@ -9,6 +9,9 @@ import (
 	"forge/biz/entity"
 	"forge/biz/repo"
 	"forge/pkg/log/zlog"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
 )
 
 // TODO: 阶段2 - Few-Shot自动扩充功能
@@ -19,15 +22,15 @@ import (
 type FewShotGenerator struct {
 	aiChatRepo  repo.AiChatRepo
 	seedManager *SeedManager
-	// TODO: 对接 infra/eino 的 AI 客户端
-	// einoClient *eino.AiChatClient
+	chatModel   model.BaseChatModel // 生成用模型，由 infra/eino 按 generate 用途的配置构建
 }
 
 // NewFewShotGenerator 创建Few-Shot生成器
-func NewFewShotGenerator(aiChatRepo repo.AiChatRepo, seedManager *SeedManager) *FewShotGenerator {
+func NewFewShotGenerator(aiChatRepo repo.AiChatRepo, seedManager *SeedManager, chatModel model.BaseChatModel) *FewShotGenerator {
 	return &FewShotGenerator{
 		aiChatRepo:  aiChatRepo,
 		seedManager: seedManager,
+		chatModel:   chatModel,
 	}
 }
 
@@ -64,7 +67,7 @@ func (f *FewShotGenerator) GenerateFewShotSamples(ctx context.Context,
 	for _, inputText := range inputTexts {
 		fullPrompt := fewShotPrompt + fmt.Sprintf("\n\n现在请为以下文本生成思维导图JSON：\n%s", inputText)
 
-		// TODO: 调用AI模型生成
+		// 调用AI模型生成
 		generatedJSON, err := f.callAIModel(ctx, fullPrompt)
 		if err != nil {
 			zlog.CtxWarnf(ctx, "AI生成失败: %v", err)
@@ -117,14 +120,22 @@ func (f *FewShotGenerator) buildFewShotPrompt(ctx context.Context, seeds []*enti
 	return prompt, nil
 }
 
-// callAIModel 调用AI模型生成
-// TODO: 对接 infra/eino 的 AI 客户端
+// callAIModel 调用AI模型生成导图JSON
 func (f *FewShotGenerator) callAIModel(ctx context.Context, prompt string) (string, error) {
-	// TODO: 实现AI调用逻辑
-	// 1. 构建messages
-	// 2. 调用 einoClient.ToolAiClient.Generate
-	// 3. 返回生成的JSON
-	return "", fmt.Errorf("未实现：需要对接eino AI客户端")
+	if f.chatModel == nil {
+		return "", fmt.Errorf("Few-Shot生成模型未初始化")
+	}
+
+	resp, err := f.chatModel.Generate(ctx, []*schema.Message{
+		{
+			Content: prompt,
+			Role:    schema.User,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("Few-Shot生成调用失败: %w", err)
+	}
+	return resp.Content, nil
 }
 
 // BuildFewShotSFTRecord 构建Few-Shot生成的SFT记录
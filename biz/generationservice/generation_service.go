@@ -944,10 +944,13 @@ func (g *GenerationService) SaveSelectedMindMap(ctx context.Context, resultID st
 	if err != nil {
 		return nil, fmt.Errorf("序列化root数据失败: %w", err)
 	}
-	var mindMapData entity.MindMapData
-	if err := json.Unmarshal(rootBytes, &mindMapData); err != nil {
+	mindMapData, err := ParseAIMindMapData(rootBytes)
+	if err != nil {
 		return nil, fmt.Errorf("解析root数据失败: %w", err)
 	}
+	if _, err := mindMapData.EnsureUIDs(); err != nil {
+		return nil, fmt.Errorf("生成节点ID失败: %w", err)
+	}
 
 	// 创建MindMap实体（模仿原有CreateMindMap实现）
 	mindMap := &entity.MindMap{
@@ -956,7 +959,7 @@ func (g *GenerationService) SaveSelectedMindMap(ctx context.Context, resultID st
 		Title:  title,
 		Desc:   desc,
 		Layout: layout,
-		Data:   mindMapData, // 直接使用解析好的结构化数据
+		Data:   *mindMapData, // 直接使用解析好的结构化数据
 	}
 
 	// 保存到正式导图系统
@@ -19,9 +19,21 @@ type QualityMetrics struct {
 	Issues       []string // 问题列表
 }
 
+// 树深度的合理范围（含根节点）
+const (
+	QualityMinTreeDepth = 2
+	QualityMaxTreeDepth = 6
+)
+
 // ValidateMindMapQuality 全面质量校验
 // 格式校验为一票否决，内容评分为加分项
 func ValidateMindMapQuality(jsonStr string) (*QualityMetrics, error) {
+	return ValidateMindMapQualityWith(jsonStr, isSFTPlaceholderText)
+}
+
+// ValidateMindMapQualityWith 使用指定的占位文本判断进行质量校验，
+// 训练数据筛选使用较严格的子串匹配，用户导图检查使用自己的规则避免误报
+func ValidateMindMapQualityWith(jsonStr string, isPlaceholder func(text string) bool) (*QualityMetrics, error) {
 	metrics := &QualityMetrics{Issues: []string{}}
 
 	// Step 1: JSON解析（格式校验）
@@ -67,9 +79,9 @@ func ValidateMindMapQuality(jsonStr string) (*QualityMetrics, error) {
 
 	// 5.1 树深度合理性（0.3分）
 	depth := calculateTreeDepth(mindMapData)
-	if depth >= 2 && depth <= 6 {
+	if depth >= QualityMinTreeDepth && depth <= QualityMaxTreeDepth {
 		contentScore += 0.3
-	} else if depth < 2 {
+	} else if depth < QualityMinTreeDepth {
 		contentScore += 0.1
 		metrics.Issues = append(metrics.Issues, "树深度过浅")
 	} else {
@@ -87,7 +99,7 @@ func ValidateMindMapQuality(jsonStr string) (*QualityMetrics, error) {
 	}
 
 	// 5.3 无占位符检查（0.3分）
-	if !hasPlaceholderText(mindMapData) {
+	if !hasPlaceholderText(mindMapData, isPlaceholder) {
 		contentScore += 0.3
 	} else {
 		metrics.Issues = append(metrics.Issues, "包含占位符文本")
@@ -147,15 +159,11 @@ func calculateAvgTextLength(node entity.MindMapData) float64 {
 }
 
 // hasPlaceholderText 检查是否有占位符文本
-func hasPlaceholderText(node entity.MindMapData) bool {
-	placeholders := []string{"xxx", "待填充", "TODO", "test", "测试"}
+func hasPlaceholderText(node entity.MindMapData, isPlaceholder func(text string) bool) bool {
 	var check func(entity.MindMapData) bool
 	check = func(n entity.MindMapData) bool {
-		lowerText := strings.ToLower(n.Data.Text)
-		for _, ph := range placeholders {
-			if strings.Contains(lowerText, strings.ToLower(ph)) {
-				return true
-			}
+		if isPlaceholder(n.Data.Text) {
+			return true
 		}
 		for _, child := range n.Children {
 			if check(child) {
@@ -167,6 +175,18 @@ func hasPlaceholderText(node entity.MindMapData) bool {
 	return check(node)
 }
 
+// isSFTPlaceholderText 训练数据的占位符检查，按子串匹配
+func isSFTPlaceholderText(text string) bool {
+	placeholders := []string{"xxx", "待填充", "TODO", "test", "测试"}
+	lowerText := strings.ToLower(text)
+	for _, ph := range placeholders {
+		if strings.Contains(lowerText, strings.ToLower(ph)) {
+			return true
+		}
+	}
+	return false
+}
+
 // hashString MD5哈希（用于数据去重）
 func hashString(s string) string {
 	hash := md5.Sum([]byte(s))
@@ -0,0 +1,366 @@
+package differ
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"forge/biz/entity"
+)
+
+// 文本匹配参数
+const (
+	similarityThreshold = 0.5  // 同一父节点下按文本匹配的最低相似度
+	maxCompareRunes     = 200  // 计算相似度时每段文本最多比较的字符数
+	maxSimilarityPairs  = 2000 // 整棵树参与相似度比较的新旧节点对上限，超出的父节点下仅按文本完全一致匹配
+)
+
+// nodeInfo 先序展开后的节点信息
+type nodeInfo struct {
+	node      *entity.MindMapData
+	parent    int // 父节点在展开列表中的下标，根节点为 -1
+	index     int // 在父节点中的下标，根节点为 0
+	path      []string
+	children  []int
+	match     int // 另一棵树中匹配节点的下标，未匹配为 -1
+	matchedBy string
+}
+
+// Compare 比较两棵节点树的结构差异：优先按UID匹配节点，UID缺失或不一致时退化为文本匹配
+// （同一父节点下取相似度最高者，其余按文本完全一致在全树范围内匹配）
+func Compare(oldData, newData *entity.MindMapData) *entity.MindMapDiff {
+	oldNodes := flatten(oldData)
+	newNodes := flatten(newData)
+
+	matchByUID(oldNodes, newNodes)
+	matchBySimilarity(oldNodes, newNodes)
+	matchByExactText(oldNodes, newNodes)
+
+	diff := &entity.MindMapDiff{Changes: make([]entity.MindMapNodeChange, 0)}
+
+	for _, old := range oldNodes {
+		if old.match >= 0 {
+			continue
+		}
+		diff.Changes = append(diff.Changes, entity.MindMapNodeChange{
+			Type:         entity.NodeChangeRemoved,
+			OldUID:       old.node.Data.UID,
+			OldText:      old.node.Data.Text,
+			OldParentUID: nodeUID(oldNodes, old.parent),
+			OldIndex:     old.index,
+			NewIndex:     -1,
+			Path:         old.path,
+		})
+		diff.Stats.Removed++
+	}
+
+	reordered := findReordered(oldNodes, newNodes)
+	for i, cur := range newNodes {
+		if cur.match < 0 {
+			diff.Changes = append(diff.Changes, entity.MindMapNodeChange{
+				Type:         entity.NodeChangeAdded,
+				NewUID:       cur.node.Data.UID,
+				NewText:      cur.node.Data.Text,
+				NewParentUID: nodeUID(newNodes, cur.parent),
+				OldIndex:     -1,
+				NewIndex:     cur.index,
+				Path:         cur.path,
+			})
+			diff.Stats.Added++
+			continue
+		}
+
+		old := oldNodes[cur.match]
+		base := entity.MindMapNodeChange{
+			OldUID:       old.node.Data.UID,
+			NewUID:       cur.node.Data.UID,
+			OldText:      old.node.Data.Text,
+			NewText:      cur.node.Data.Text,
+			OldParentUID: nodeUID(oldNodes, old.parent),
+			NewParentUID: nodeUID(newNodes, cur.parent),
+			OldIndex:     old.index,
+			NewIndex:     cur.index,
+			Path:         cur.path,
+			MatchedBy:    cur.matchedBy,
+			Similarity:   similarity(old.node.Data.Text, cur.node.Data.Text),
+		}
+		changed := false
+		if isMoved(oldNodes, newNodes, i) {
+			change := base
+			change.Type = entity.NodeChangeMoved
+			diff.Changes = append(diff.Changes, change)
+			diff.Stats.Moved++
+			changed = true
+		}
+		if strings.TrimSpace(old.node.Data.Text) != strings.TrimSpace(cur.node.Data.Text) {
+			change := base
+			change.Type = entity.NodeChangeRenamed
+			diff.Changes = append(diff.Changes, change)
+			diff.Stats.Renamed++
+			changed = true
+		}
+		if reordered[i] {
+			change := base
+			change.Type = entity.NodeChangeReordered
+			diff.Changes = append(diff.Changes, change)
+			diff.Stats.Reordered++
+			changed = true
+		}
+		if !changed {
+			diff.Stats.Unchanged++
+		}
+	}
+
+	return diff
+}
+
+// flatten 先序展开节点树
+func flatten(root *entity.MindMapData) []*nodeInfo {
+	nodes := make([]*nodeInfo, 0)
+	if root == nil {
+		return nodes
+	}
+	var visit func(node *entity.MindMapData, parent, index int, path []string)
+	visit = func(node *entity.MindMapData, parent, index int, path []string) {
+		path = append(path[:len(path):len(path)], node.Data.Text)
+		self := len(nodes)
+		nodes = append(nodes, &nodeInfo{node: node, parent: parent, index: index, path: path, match: -1})
+		if parent >= 0 {
+			nodes[parent].children = append(nodes[parent].children, self)
+		}
+		for i := range node.Children {
+			visit(&node.Children[i], self, i, path)
+		}
+	}
+	visit(root, -1, 0, nil)
+	return nodes
+}
+
+// matchByUID 按UID匹配节点，根节点无论UID是否一致都视为相互对应
+func matchByUID(oldNodes, newNodes []*nodeInfo) {
+	oldByUID := make(map[string]int, len(oldNodes))
+	for i, old := range oldNodes {
+		if uid := old.node.Data.UID; uid != "" {
+			if _, dup := oldByUID[uid]; !dup {
+				oldByUID[uid] = i
+			}
+		}
+	}
+	for i, cur := range newNodes {
+		j, ok := oldByUID[cur.node.Data.UID]
+		if !ok || oldNodes[j].match >= 0 {
+			continue
+		}
+		link(oldNodes, newNodes, j, i, entity.NodeMatchByUID)
+	}
+
+	if len(oldNodes) > 0 && len(newNodes) > 0 && oldNodes[0].match < 0 && newNodes[0].match < 0 {
+		link(oldNodes, newNodes, 0, 0, entity.NodeMatchByText)
+	}
+}
+
+// matchBySimilarity 父节点已匹配时，在原父节点未匹配的子节点中选取文本最相似的节点
+// 新树按先序处理，保证父节点先于子节点完成匹配
+func matchBySimilarity(oldNodes, newNodes []*nodeInfo) {
+	// 各父节点是否参与相似度匹配，首次遇到时按未匹配子节点数量从剩余额度中扣减
+	allowed := make(map[int]bool)
+	budget := maxSimilarityPairs
+	for i, cur := range newNodes {
+		if cur.match >= 0 || cur.parent < 0 || newNodes[cur.parent].match < 0 {
+			continue
+		}
+		oldParent := oldNodes[newNodes[cur.parent].match]
+
+		ok, seen := allowed[cur.parent]
+		if !seen {
+			pairs := countUnmatched(oldNodes, oldParent.children) * countUnmatched(newNodes, newNodes[cur.parent].children)
+			if ok = pairs <= budget; ok {
+				budget -= pairs
+			}
+			allowed[cur.parent] = ok
+		}
+		if !ok {
+			continue
+		}
+
+		best, bestScore := -1, 0.0
+		for _, j := range oldParent.children {
+			if oldNodes[j].match >= 0 {
+				continue
+			}
+			score := similarity(oldNodes[j].node.Data.Text, cur.node.Data.Text)
+			// 相似度相同时优先位置一致的节点
+			if score > bestScore || (score == bestScore && best >= 0 && oldNodes[j].index == cur.index) {
+				best, bestScore = j, score
+			}
+		}
+		if best >= 0 && bestScore >= similarityThreshold {
+			link(oldNodes, newNodes, best, i, entity.NodeMatchByText)
+		}
+	}
+}
+
+// countUnmatched 统计下标列表中尚未匹配的节点数
+func countUnmatched(nodes []*nodeInfo, indexes []int) int {
+	count := 0
+	for _, i := range indexes {
+		if nodes[i].match < 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// matchByExactText 剩余节点按文本完全一致匹配，用于识别没有UID的节点跨父节点移动
+func matchByExactText(oldNodes, newNodes []*nodeInfo) {
+	oldByText := make(map[string][]int)
+	for i, old := range oldNodes {
+		if old.match >= 0 {
+			continue
+		}
+		if text := normalizeText(old.node.Data.Text); text != "" {
+			oldByText[text] = append(oldByText[text], i)
+		}
+	}
+	if len(oldByText) == 0 {
+		return
+	}
+	for i, cur := range newNodes {
+		if cur.match >= 0 {
+			continue
+		}
+		text := normalizeText(cur.node.Data.Text)
+		candidates := oldByText[text]
+		if len(candidates) == 0 {
+			continue
+		}
+		link(oldNodes, newNodes, candidates[0], i, entity.NodeMatchByText)
+		oldByText[text] = candidates[1:]
+	}
+}
+
+func link(oldNodes, newNodes []*nodeInfo, oldIdx, newIdx int, matchedBy string) {
+	oldNodes[oldIdx].match = newIdx
+	oldNodes[oldIdx].matchedBy = matchedBy
+	newNodes[newIdx].match = oldIdx
+	newNodes[newIdx].matchedBy = matchedBy
+}
+
+// isMoved 节点的父节点是否变化（新旧父节点互不对应）
+func isMoved(oldNodes, newNodes []*nodeInfo, newIdx int) bool {
+	cur := newNodes[newIdx]
+	old := oldNodes[cur.match]
+	if cur.parent < 0 && old.parent < 0 {
+		return false
+	}
+	if cur.parent < 0 || old.parent < 0 {
+		return true
+	}
+	return oldNodes[old.parent].match != cur.parent
+}
+
+// findReordered 找出同一父节点下相对顺序发生变化的节点：
+// 对未移动的子节点按原下标求最长递增子序列，不在其中的节点视为调整了顺序（仅因增删导致的下标变化不计入）
+func findReordered(oldNodes, newNodes []*nodeInfo) map[int]bool {
+	reordered := make(map[int]bool)
+	for _, parent := range newNodes {
+		if parent.match < 0 || len(parent.children) < 2 {
+			continue
+		}
+		stayed := make([]int, 0, len(parent.children))
+		oldIndexes := make([]int, 0, len(parent.children))
+		for _, i := range parent.children {
+			if newNodes[i].match < 0 || isMoved(oldNodes, newNodes, i) {
+				continue
+			}
+			stayed = append(stayed, i)
+			oldIndexes = append(oldIndexes, oldNodes[newNodes[i].match].index)
+		}
+		kept := longestIncreasing(oldIndexes)
+		for k, i := range stayed {
+			if !kept[k] {
+				reordered[i] = true
+			}
+		}
+	}
+	return reordered
+}
+
+// longestIncreasing 返回最长递增子序列中各元素的标记
+func longestIncreasing(values []int) []bool {
+	kept := make([]bool, len(values))
+	if len(values) == 0 {
+		return kept
+	}
+	tails := make([]int, 0, len(values)) // tails[k] 为长度 k+1 的递增子序列末尾元素的下标
+	prev := make([]int, len(values))
+	for i, v := range values {
+		k := sort.Search(len(tails), func(k int) bool { return values[tails[k]] >= v })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+		kept[i] = true
+	}
+	return kept
+}
+
+func nodeUID(nodes []*nodeInfo, idx int) string {
+	if idx < 0 {
+		return ""
+	}
+	return nodes[idx].node.Data.UID
+}
+
+func normalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// similarity 基于编辑距离的文本相似度（0~1）
+func similarity(a, b string) float64 {
+	a, b = normalizeText(a), normalizeText(b)
+	if a == b {
+		return 1
+	}
+	ra, rb := truncateRunes(a), truncateRunes(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func truncateRunes(s string) []rune {
+	if utf8.RuneCountInString(s) <= maxCompareRunes {
+		return []rune(s)
+	}
+	return []rune(s)[:maxCompareRunes]
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,120 @@
+package exporter
+
+import (
+	"bytes"
+
+	"forge/biz/entity"
+
+	"github.com/unidoc/unioffice/v2/color"
+	"github.com/unidoc/unioffice/v2/document"
+	"github.com/unidoc/unioffice/v2/measurement"
+)
+
+// 前几层节点渲染为标题，更深的节点渲染为嵌套列表
+const (
+	docxHeadingDepth = 3
+	docxMaxListLevel = 8 // Word列表最多9级（0-8）
+)
+
+// Word导出器：根节点作为文档标题，每一层级对应一级标题或嵌套列表，备注作为正文段落
+type DOCXExporter struct{}
+
+func (e *DOCXExporter) Format() string {
+	return "docx"
+}
+
+func (e *DOCXExporter) FileExt() string {
+	return ".docx"
+}
+
+func (e *DOCXExporter) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+}
+
+func (e *DOCXExporter) Export(mindMap *entity.MindMap) ([]byte, error) {
+	doc := document.New()
+	defer doc.Close()
+
+	// document.New 默认带有一个项目符号列表定义
+	var bullets document.NumberingDefinition
+	if defs := doc.Numbering.Definitions(); len(defs) > 0 {
+		bullets = defs[0]
+	} else {
+		bullets = doc.Numbering.AddDefinition()
+	}
+
+	root := mindMap.Data
+	title := root.Data.Text
+	if title == "" {
+		title = mindMap.Title
+	}
+	para := doc.AddParagraph()
+	para.SetStyle("Title")
+	para.AddRun().AddText(markdownInline(title))
+	if root.Data.Note != "" {
+		writeDOCXNote(doc, root.Data.Note)
+	}
+
+	for i := range root.Children {
+		writeDOCXNode(doc, bullets, &root.Children[i], 1)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Save(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeDOCXNode(doc *document.Document, bullets document.NumberingDefinition, node *entity.MindMapData, depth int) {
+	para := doc.AddParagraph()
+	if depth <= docxHeadingDepth {
+		para.SetStyle("Heading" + string(rune('0'+depth)))
+	} else {
+		para.SetNumberingDefinition(bullets)
+		para.SetNumberingLevel(min(depth-docxHeadingDepth-1, docxMaxListLevel))
+	}
+
+	text := markdownInline(node.Data.Text)
+	if node.Data.Hyperlink != "" {
+		link := para.AddHyperLink()
+		link.SetTarget(node.Data.Hyperlink)
+		run := link.AddRun()
+		run.Properties().SetStyle("Hyperlink")
+		run.Properties().SetColor(color.FromHex("#0563C1"))
+		run.AddText(text)
+	} else {
+		run := para.AddRun()
+		if node.Data.Color != "" {
+			run.Properties().SetColor(color.FromHex(node.Data.Color))
+		}
+		run.AddText(text)
+	}
+
+	if node.Data.Note != "" {
+		note := writeDOCXNote(doc, node.Data.Note)
+		if depth > docxHeadingDepth {
+			// 列表项备注与列表文本对齐
+			note.SetLeftIndent(measurement.Distance(depth-docxHeadingDepth) * 0.25 * measurement.Inch)
+		}
+	}
+
+	for i := range node.Children {
+		writeDOCXNode(doc, bullets, &node.Children[i], depth+1)
+	}
+}
+
+// writeDOCXNote 备注渲染为斜体正文段落，多行备注使用换行分隔
+func writeDOCXNote(doc *document.Document, note string) document.Paragraph {
+	para := doc.AddParagraph()
+	run := para.AddRun()
+	run.Properties().SetItalic(true)
+	run.Properties().SetColor(color.FromHex("#595959"))
+	for i, line := range splitLines(note) {
+		if i > 0 {
+			run.AddBreak()
+		}
+		run.AddText(line)
+	}
+	return para
+}
@@ -0,0 +1,91 @@
+package exporter
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"forge/biz/entity"
+)
+
+var ErrUnsupportedFormat = errors.New("不支持的导出格式")
+
+// MindMapExporter 思维导图导出器接口
+type MindMapExporter interface {
+	// Format 返回导出格式标识，如 markdown
+	Format() string
+	// FileExt 返回导出文件扩展名（含点号）
+	FileExt() string
+	// ContentType 返回导出文件的MIME类型
+	ContentType() string
+	// Export 将思维导图渲染为文件内容
+	Export(mindMap *entity.MindMap) ([]byte, error)
+}
+
+// 导出器注册表
+type ExporterRegistry struct {
+	exporters map[string]MindMapExporter
+	mu        sync.RWMutex
+}
+
+var (
+	globalRegistry = &ExporterRegistry{exporters: make(map[string]MindMapExporter)}
+	once           sync.Once
+)
+
+// 初始化并注册所有导出器
+func initRegistry() {
+	globalRegistry.Register(&MarkdownExporter{})
+	globalRegistry.Register(&OPMLExporter{})
+	globalRegistry.Register(&FreeMindExporter{})
+	globalRegistry.Register(&SVGExporter{})
+	globalRegistry.Register(&PDFExporter{})
+	globalRegistry.Register(&DOCXExporter{})
+	globalRegistry.Register(&PPTXExporter{})
+}
+
+// GetRegistry 获取全局导出器注册表
+func GetRegistry() *ExporterRegistry {
+	once.Do(initRegistry)
+	return globalRegistry
+}
+
+// Register 注册导出器，同名格式后注册的覆盖先注册的
+func (r *ExporterRegistry) Register(exporter MindMapExporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exporters[strings.ToLower(exporter.Format())] = exporter
+}
+
+// GetExporter 根据格式标识获取导出器
+func (r *ExporterRegistry) GetExporter(format string) (MindMapExporter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	exporter, ok := r.exporters[strings.ToLower(format)]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	return exporter, nil
+}
+
+// Formats 返回已注册的导出格式
+func (r *ExporterRegistry) Formats() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	formats := make([]string, 0, len(r.exporters))
+	for format := range r.exporters {
+		formats = append(formats, format)
+	}
+	return formats
+}
+
+// splitLines 按行拆分文本，忽略首尾空白
+func splitLines(text string) []string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+	return lines
+}
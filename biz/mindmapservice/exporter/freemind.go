@@ -0,0 +1,103 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"forge/biz/entity"
+)
+
+// FreeMind导出器：输出 FreeMind .mm XML
+type FreeMindExporter struct{}
+
+type freeMindMap struct {
+	XMLName xml.Name     `xml:"map"`
+	Version string       `xml:"version,attr"`
+	Node    freeMindNode `xml:"node"`
+}
+
+type freeMindNode struct {
+	ID       string               `xml:"ID,attr,omitempty"`
+	Text     string               `xml:"TEXT,attr"`
+	Folded   string               `xml:"FOLDED,attr,omitempty"`
+	Link     string               `xml:"LINK,attr,omitempty"`
+	Color    string               `xml:"COLOR,attr,omitempty"`
+	Icons    []freeMindIcon       `xml:"icon"`
+	Note     *freeMindRichContent `xml:"richcontent,omitempty"`
+	Children []freeMindNode       `xml:"node"`
+}
+
+type freeMindIcon struct {
+	Builtin string `xml:"BUILTIN,attr"`
+}
+
+type freeMindRichContent struct {
+	Type string       `xml:"TYPE,attr"`
+	HTML freeMindHTML `xml:"html"`
+}
+
+type freeMindHTML struct {
+	Body struct {
+		Paragraphs []string `xml:"p"`
+	} `xml:"body"`
+}
+
+func (e *FreeMindExporter) Format() string {
+	return "freemind"
+}
+
+func (e *FreeMindExporter) FileExt() string {
+	return ".mm"
+}
+
+func (e *FreeMindExporter) ContentType() string {
+	return "application/x-freemind; charset=utf-8"
+}
+
+func (e *FreeMindExporter) Export(mindMap *entity.MindMap) ([]byte, error) {
+	doc := freeMindMap{
+		Version: "1.0.1",
+		Node:    buildFreeMindNode(&mindMap.Data),
+	}
+	return marshalXML(doc)
+}
+
+func buildFreeMindNode(node *entity.MindMapData) freeMindNode {
+	fmNode := freeMindNode{
+		Text:  node.Data.Text,
+		Link:  node.Data.Hyperlink,
+		Color: node.Data.Color,
+	}
+	// FreeMind 要求节点ID以字母开头
+	if node.Data.UID != "" {
+		fmNode.ID = "ID_" + node.Data.UID
+	}
+	if node.Data.Collapsed && len(node.Children) > 0 {
+		fmNode.Folded = "true"
+	}
+	for _, icon := range node.Data.Icon {
+		fmNode.Icons = append(fmNode.Icons, freeMindIcon{Builtin: icon})
+	}
+	if node.Data.Note != "" {
+		note := &freeMindRichContent{Type: "NOTE"}
+		note.HTML.Body.Paragraphs = splitLines(node.Data.Note)
+		fmNode.Note = note
+	}
+	for i := range node.Children {
+		fmNode.Children = append(fmNode.Children, buildFreeMindNode(&node.Children[i]))
+	}
+	return fmNode
+}
+
+// marshalXML 带XML声明的缩进序列化
+func marshalXML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,79 @@
+package exporter
+
+import (
+	"strings"
+
+	"forge/biz/entity"
+)
+
+// Markdown导出器：根节点作为一级标题，其余节点渲染为嵌套无序列表
+type MarkdownExporter struct{}
+
+func (e *MarkdownExporter) Format() string {
+	return "markdown"
+}
+
+func (e *MarkdownExporter) FileExt() string {
+	return ".md"
+}
+
+func (e *MarkdownExporter) ContentType() string {
+	return "text/markdown; charset=utf-8"
+}
+
+func (e *MarkdownExporter) Export(mindMap *entity.MindMap) ([]byte, error) {
+	var sb strings.Builder
+
+	root := mindMap.Data
+	title := root.Data.Text
+	if title == "" {
+		title = mindMap.Title
+	}
+	sb.WriteString("# ")
+	sb.WriteString(markdownInline(title))
+	sb.WriteString("\n\n")
+	if root.Data.Note != "" {
+		writeMarkdownNote(&sb, root.Data.Note, "")
+		sb.WriteString("\n")
+	}
+
+	for i := range root.Children {
+		writeMarkdownNode(&sb, &root.Children[i], 0)
+	}
+	return []byte(sb.String()), nil
+}
+
+func writeMarkdownNode(sb *strings.Builder, node *entity.MindMapData, depth int) {
+	indent := strings.Repeat("  ", depth)
+	text := markdownInline(node.Data.Text)
+	if node.Data.Hyperlink != "" {
+		text = "[" + text + "](" + node.Data.Hyperlink + ")"
+	}
+
+	sb.WriteString(indent)
+	sb.WriteString("- ")
+	sb.WriteString(text)
+	sb.WriteString("\n")
+	if node.Data.Note != "" {
+		writeMarkdownNote(sb, node.Data.Note, indent+"  ")
+	}
+
+	for i := range node.Children {
+		writeMarkdownNode(sb, &node.Children[i], depth+1)
+	}
+}
+
+// writeMarkdownNote 备注以引用块形式跟随在节点下方
+func writeMarkdownNote(sb *strings.Builder, note, indent string) {
+	for _, line := range splitLines(note) {
+		sb.WriteString(indent)
+		sb.WriteString("> ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+}
+
+// markdownInline 节点文本中的换行会破坏列表结构，统一替换为空格
+func markdownInline(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,101 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"time"
+
+	"forge/biz/entity"
+)
+
+// OPML导出器：输出 OPML 2.0 大纲
+type OPMLExporter struct{}
+
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title        string `xml:"title"`
+	DateCreated  string `xml:"dateCreated,omitempty"`
+	DateModified string `xml:"dateModified,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Note     string        `xml:"_note,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	URL      string        `xml:"url,attr,omitempty"`
+	Category string        `xml:"category,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+func (e *OPMLExporter) Format() string {
+	return "opml"
+}
+
+func (e *OPMLExporter) FileExt() string {
+	return ".opml"
+}
+
+func (e *OPMLExporter) ContentType() string {
+	return "text/x-opml; charset=utf-8"
+}
+
+func (e *OPMLExporter) Export(mindMap *entity.MindMap) ([]byte, error) {
+	doc := opmlDocument{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:        mindMap.Title,
+			DateCreated:  formatRFC822(mindMap.CreatedAt),
+			DateModified: formatRFC822(mindMap.UpdatedAt),
+		},
+		Body: opmlBody{
+			Outlines: []opmlOutline{buildOPMLOutline(&mindMap.Data)},
+		},
+	}
+	return marshalXML(doc)
+}
+
+func buildOPMLOutline(node *entity.MindMapData) opmlOutline {
+	outline := opmlOutline{
+		Text: node.Data.Text,
+		Note: node.Data.Note,
+	}
+	if node.Data.Hyperlink != "" {
+		outline.Type = "link"
+		outline.URL = node.Data.Hyperlink
+	}
+	if len(node.Data.Tags) > 0 {
+		outline.Category = joinCategory(node.Data.Tags)
+	}
+	for i := range node.Children {
+		outline.Outlines = append(outline.Outlines, buildOPMLOutline(&node.Children[i]))
+	}
+	return outline
+}
+
+// joinCategory OPML 的 category 属性为逗号分隔的斜杠路径
+func joinCategory(tags []string) string {
+	category := ""
+	for i, tag := range tags {
+		if i > 0 {
+			category += ","
+		}
+		category += "/" + tag
+	}
+	return category
+}
+
+func formatRFC822(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC1123Z)
+}
@@ -0,0 +1,165 @@
+package exporter
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"forge/biz/entity"
+	"forge/biz/mindmapservice/layout"
+	"forge/infra/configs"
+	"forge/pkg/log/zlog"
+
+	"github.com/unidoc/unipdf/v4/contentstream/draw"
+	"github.com/unidoc/unipdf/v4/creator"
+	"github.com/unidoc/unipdf/v4/model"
+)
+
+// PDF 单页最大边长（pt），超出时整体等比缩放
+const maxPDFPageSize = 14400.0
+
+// ErrPDFFontNotConfigured 导图包含内置字体无法显示的字符（如中文），但未配置可用的TTF字体
+var ErrPDFFontNotConfigured = errors.New("未配置支持中文的PDF字体，请设置 font_path")
+
+var (
+	pdfFont         *model.PdfFont
+	pdfFontEmbedded bool // 是否成功加载了配置的TTF字体
+	pdfFontOnce     sync.Once
+)
+
+// getPDFFont 优先加载配置的TTF字体（支持中文），未配置或加载失败时回退到内置字体（仅支持Latin-1字符）
+func getPDFFont() (*model.PdfFont, bool) {
+	pdfFontOnce.Do(func() {
+		if fontPath := configs.Config().GetUniOfficeConfig().FontPath; fontPath != "" {
+			font, err := model.NewCompositePdfFontFromTTFFile(fontPath)
+			if err == nil {
+				pdfFont, pdfFontEmbedded = font, true
+				return
+			}
+			zlog.Warnf("load pdf font %s failed, fallback to helvetica: %v", fontPath, err)
+		}
+		pdfFont = model.NewStandard14FontMustCompile(model.HelveticaName)
+	})
+	return pdfFont, pdfFontEmbedded
+}
+
+// needsEmbeddedFont 节点文本是否包含内置字体无法显示的字符
+func needsEmbeddedFont(result *layout.Result) bool {
+	for _, box := range result.Nodes {
+		for _, line := range box.Lines {
+			for _, r := range line {
+				if r > unicode.MaxLatin1 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// PDF导出器：与SVG使用同一套排版结果，整张导图输出为单页PDF便于打印
+type PDFExporter struct{}
+
+func (e *PDFExporter) Format() string {
+	return "pdf"
+}
+
+func (e *PDFExporter) FileExt() string {
+	return ".pdf"
+}
+
+func (e *PDFExporter) ContentType() string {
+	return "application/pdf"
+}
+
+func (e *PDFExporter) Export(mindMap *entity.MindMap) ([]byte, error) {
+	return RenderPDF(layout.Compute(&mindMap.Data, mindMap.Layout))
+}
+
+// RenderPDF 将排版结果渲染为单页PDF
+func RenderPDF(result *layout.Result) ([]byte, error) {
+	font, embedded := getPDFFont()
+	if !embedded && needsEmbeddedFont(result) {
+		return nil, ErrPDFFontNotConfigured
+	}
+	scale := math.Min(1, maxPDFPageSize/math.Max(result.Bounds.W, result.Bounds.H))
+
+	c := creator.New()
+	c.SetPageMargins(0, 0, 0, 0)
+	c.SetPageSize(creator.PageSize{result.Bounds.W * scale, result.Bounds.H * scale})
+	c.NewPage()
+
+	edgeColor := creator.ColorRGBFromHex(renderEdgeColor)
+	for _, edge := range result.Edges {
+		if edge.Curved && len(edge.Points) == 4 {
+			p := edge.Points
+			curve := c.NewPolyBezierCurve([]draw.CubicBezierCurve{draw.NewCubicBezierCurve(
+				p[0].X*scale, p[0].Y*scale, p[1].X*scale, p[1].Y*scale,
+				p[2].X*scale, p[2].Y*scale, p[3].X*scale, p[3].Y*scale,
+			)})
+			curve.SetBorderColor(edgeColor)
+			curve.SetBorderWidth(1.5 * scale)
+			if err := c.Draw(curve); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		points := make([]draw.Point, 0, len(edge.Points))
+		for _, p := range edge.Points {
+			points = append(points, draw.NewPoint(p.X*scale, p.Y*scale))
+		}
+		line := c.NewPolyline(points)
+		line.SetLineColor(edgeColor)
+		line.SetLineWidth(1.5 * scale)
+		if err := c.Draw(line); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, box := range result.Nodes {
+		fill, stroke, textColor := nodeTheme(box)
+		radius := cornerRadius(box) * scale
+
+		rect := c.NewRectangle(box.X*scale, box.Y*scale, box.W*scale, box.H*scale)
+		rect.SetFillColor(creator.ColorRGBFromHex(fill))
+		rect.SetBorderColor(creator.ColorRGBFromHex(stroke))
+		rect.SetBorderWidth(scale)
+		rect.SetBorderRadius(radius, radius, radius, radius)
+		if err := c.Draw(rect); err != nil {
+			return nil, err
+		}
+
+		lineHeight := box.FontSize * layout.LineHeightRatio
+		para := c.NewStyledParagraph()
+		para.SetEnableWrap(false)
+		para.SetWidth(box.W * scale)
+		para.SetTextAlignment(creator.TextAlignmentCenter)
+		para.SetLineHeight(layout.LineHeightRatio)
+		chunk := para.Append(strings.Join(box.Lines, "\n"))
+		chunk.Style.Font = font
+		chunk.Style.FontSize = box.FontSize * scale
+		chunk.Style.Color = pdfColor(textColor)
+		para.SetPos(box.X*scale, (box.Y+(box.H-lineHeight*float64(len(box.Lines)))/2)*scale)
+		if err := c.Draw(para); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pdfColor 解析十六进制颜色，非法值使用默认文字颜色
+func pdfColor(hex string) creator.Color {
+	if strings.HasPrefix(hex, "#") && (len(hex) == 4 || len(hex) == 7) {
+		return creator.ColorRGBFromHex(hex)
+	}
+	return creator.ColorRGBFromHex(renderDefaultText)
+}
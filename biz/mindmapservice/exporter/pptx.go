@@ -0,0 +1,168 @@
+package exporter
+
+import (
+	"bytes"
+
+	"forge/biz/entity"
+
+	"github.com/unidoc/unioffice/v2/color"
+	"github.com/unidoc/unioffice/v2/measurement"
+	"github.com/unidoc/unioffice/v2/presentation"
+	"github.com/unidoc/unioffice/v2/schema/soo/dml"
+	"github.com/unidoc/unioffice/v2/schema/soo/pml"
+)
+
+// 幻灯片版式参数（16:9，单位英寸）
+const (
+	pptxSlideWidth     = 13.333
+	pptxSlideHeight    = 7.5
+	pptxMargin         = 0.6
+	pptxTitleHeight    = 1.0
+	pptxBulletsPerPage = 12 // 单页最多要点数，超出时拆分为续页
+	pptxMaxBulletLevel = 8
+)
+
+// PPT导出器：根节点作为封面，每个一级分支生成一页幻灯片，其子树渲染为多级要点
+type PPTXExporter struct{}
+
+func (e *PPTXExporter) Format() string {
+	return "pptx"
+}
+
+func (e *PPTXExporter) FileExt() string {
+	return ".pptx"
+}
+
+func (e *PPTXExporter) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+}
+
+// pptxBullet 幻灯片要点
+type pptxBullet struct {
+	text  string
+	level int
+}
+
+func (e *PPTXExporter) Export(mindMap *entity.MindMap) ([]byte, error) {
+	ppt := presentation.New()
+	defer ppt.Close()
+
+	size := pml.NewCT_SlideSize()
+	size.CxAttr = pptxEMU(pptxSlideWidth)
+	size.CyAttr = pptxEMU(pptxSlideHeight)
+	size.TypeAttr = pml.ST_SlideSizeTypeScreen16x9
+	ppt.X().SldSz = size
+
+	root := mindMap.Data
+	title := root.Data.Text
+	if title == "" {
+		title = mindMap.Title
+	}
+	addPPTXCoverSlide(ppt, markdownInline(title), mindMap.Desc)
+
+	for i := range root.Children {
+		branch := &root.Children[i]
+		var bullets []pptxBullet
+		for j := range branch.Children {
+			collectPPTXBullets(&branch.Children[j], 0, &bullets)
+		}
+
+		// 要点过多时拆分为多页，保证文字可读
+		branchTitle := markdownInline(branch.Data.Text)
+		for page := 0; page == 0 || page*pptxBulletsPerPage < len(bullets); page++ {
+			end := min((page+1)*pptxBulletsPerPage, len(bullets))
+			slideTitle := branchTitle
+			if page > 0 {
+				slideTitle += "（续）"
+			}
+			addPPTXContentSlide(ppt, slideTitle, bullets[page*pptxBulletsPerPage:end])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ppt.Save(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func collectPPTXBullets(node *entity.MindMapData, level int, bullets *[]pptxBullet) {
+	*bullets = append(*bullets, pptxBullet{text: markdownInline(node.Data.Text), level: min(level, pptxMaxBulletLevel)})
+	for i := range node.Children {
+		collectPPTXBullets(&node.Children[i], level+1, bullets)
+	}
+}
+
+func addPPTXCoverSlide(ppt *presentation.Presentation, title, subtitle string) {
+	slide := ppt.AddSlide()
+
+	tb := addPPTXTextBox(slide, pptxMargin, 2.4, pptxSlideWidth-2*pptxMargin, 1.4)
+	tb.SetTextAnchor(dml.ST_TextAnchoringTypeCtr)
+	para := tb.AddParagraph()
+	para.Properties().SetAlign(dml.ST_TextAlignTypeCtr)
+	run := para.AddRun()
+	run.SetText(title)
+	run.Properties().SetBold(true)
+	run.Properties().SetSize(40 * measurement.Point)
+	run.Properties().SetSolidFill(color.FromHex(renderRootFill))
+
+	if subtitle == "" {
+		return
+	}
+	sub := addPPTXTextBox(slide, pptxMargin, 4.0, pptxSlideWidth-2*pptxMargin, 1.0)
+	para = sub.AddParagraph()
+	para.Properties().SetAlign(dml.ST_TextAlignTypeCtr)
+	run = para.AddRun()
+	run.SetText(markdownInline(subtitle))
+	run.Properties().SetSize(20 * measurement.Point)
+	run.Properties().SetSolidFill(color.FromHex("#595959"))
+}
+
+func addPPTXContentSlide(ppt *presentation.Presentation, title string, bullets []pptxBullet) {
+	slide := ppt.AddSlide()
+
+	tb := addPPTXTextBox(slide, pptxMargin, 0.4, pptxSlideWidth-2*pptxMargin, pptxTitleHeight)
+	run := tb.AddParagraph().AddRun()
+	run.SetText(title)
+	run.Properties().SetBold(true)
+	run.Properties().SetSize(32 * measurement.Point)
+	run.Properties().SetSolidFill(color.FromHex(renderRootFill))
+
+	if len(bullets) == 0 {
+		return
+	}
+	top := 0.4 + pptxTitleHeight + 0.2
+	body := addPPTXTextBox(slide, pptxMargin, top, pptxSlideWidth-2*pptxMargin, pptxSlideHeight-top-pptxMargin)
+	body.SetTextAnchor(dml.ST_TextAnchoringTypeT)
+	for _, bullet := range bullets {
+		para := body.AddParagraph()
+		props := para.Properties()
+		props.SetLevel(int32(bullet.level))
+		props.SetBulletChar("•")
+		// 悬挂缩进：要点符号位于缩进左侧
+		marL := pptxEMU(0.35 + 0.4*float64(bullet.level))
+		indent := pptxEMU(-0.3)
+		props.X().MarLAttr = &marL
+		props.X().IndentAttr = &indent
+
+		run := para.AddRun()
+		run.SetText(bullet.text)
+		run.Properties().SetSize(measurement.Distance(max(24-4*bullet.level, 14)) * measurement.Point)
+	}
+}
+
+// addPPTXTextBox 在指定位置添加自动换行的文本框（单位英寸）
+func addPPTXTextBox(slide presentation.Slide, x, y, w, h float64) presentation.TextBox {
+	tb := slide.AddTextBox()
+	tb.Properties().SetPosition(measurement.Distance(x)*measurement.Inch, measurement.Distance(y)*measurement.Inch)
+	tb.Properties().SetSize(measurement.Distance(w)*measurement.Inch, measurement.Distance(h)*measurement.Inch)
+	if txBody := tb.X().TxBody; txBody != nil && txBody.BodyPr != nil {
+		txBody.BodyPr.WrapAttr = dml.ST_TextWrappingTypeSquare
+	}
+	return tb
+}
+
+// pptxEMU 英寸转换为EMU
+func pptxEMU(inch float64) int32 {
+	return int32(measurement.Distance(inch) * measurement.Inch / measurement.EMU)
+}
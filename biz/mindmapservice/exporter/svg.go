@@ -0,0 +1,165 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"forge/biz/entity"
+	"forge/biz/mindmapservice/layout"
+)
+
+// 渲染配色
+const (
+	renderBackground  = "#ffffff"
+	renderEdgeColor   = "#9aa6c8"
+	renderRootFill    = "#4e6ef2"
+	renderRootText    = "#ffffff"
+	renderBranchFill  = "#eef1fe"
+	renderBranchLine  = "#4e6ef2"
+	renderLeafFill    = "#ffffff"
+	renderLeafLine    = "#c9d1ea"
+	renderDefaultText = "#222222"
+	renderFontFamily  = "PingFang SC, Microsoft YaHei, Noto Sans CJK SC, sans-serif"
+)
+
+// nodeTheme 节点配色：返回填充色、边框色与文字颜色
+func nodeTheme(box *layout.NodeBox) (fill, stroke, text string) {
+	switch box.Depth {
+	case 0:
+		fill, stroke, text = renderRootFill, renderRootFill, renderRootText
+	case 1:
+		fill, stroke, text = renderBranchFill, renderBranchLine, renderDefaultText
+	default:
+		fill, stroke, text = renderLeafFill, renderLeafLine, renderDefaultText
+	}
+	if box.Node.Data.Color != "" {
+		text = box.Node.Data.Color
+	}
+	return fill, stroke, text
+}
+
+// textBaseline 返回节点第 i 行文本的基线纵坐标，文本块在节点内垂直居中
+func textBaseline(box *layout.NodeBox, i int) float64 {
+	lineHeight := box.FontSize * layout.LineHeightRatio
+	top := box.Y + (box.H-lineHeight*float64(len(box.Lines)))/2
+	return top + lineHeight*float64(i) + box.FontSize*1.05
+}
+
+// SVG导出器：按导图布局在服务端排版并输出矢量图
+type SVGExporter struct{}
+
+func (e *SVGExporter) Format() string {
+	return "svg"
+}
+
+func (e *SVGExporter) FileExt() string {
+	return ".svg"
+}
+
+func (e *SVGExporter) ContentType() string {
+	return "image/svg+xml"
+}
+
+func (e *SVGExporter) Export(mindMap *entity.MindMap) ([]byte, error) {
+	return RenderSVG(layout.Compute(&mindMap.Data, mindMap.Layout)), nil
+}
+
+// RenderSVG 将排版结果渲染为SVG
+func RenderSVG(result *layout.Result) []byte {
+	return renderSVG(result, result.Bounds.W, result.Bounds.H)
+}
+
+// RenderThumbnailSVG 将排版结果渲染为不超过 maxW*maxH 的SVG缩略图（按比例缩小，不放大）
+func RenderThumbnailSVG(result *layout.Result, maxW, maxH float64) []byte {
+	w, h := result.Bounds.W, result.Bounds.H
+	scale := 1.0
+	if w > 0 && h > 0 {
+		scale = min(scale, maxW/w, maxH/h)
+	}
+	return renderSVG(result, w*scale, h*scale)
+}
+
+// renderSVG 按 width*height 的显示尺寸渲染，viewBox 始终为完整画布
+func renderSVG(result *layout.Result, width, height float64) []byte {
+	var buf bytes.Buffer
+	w, h := result.Bounds.W, result.Bounds.H
+
+	buf.WriteString(xml.Header)
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.1f %.1f" font-family="%s">`+"\n",
+		width, height, w, h, renderFontFamily)
+	fmt.Fprintf(&buf, `  <rect width="100%%" height="100%%" fill="%s"/>`+"\n", renderBackground)
+
+	// 先画连线，避免压住节点
+	fmt.Fprintf(&buf, `  <g fill="none" stroke="%s" stroke-width="1.5">`+"\n", renderEdgeColor)
+	for _, edge := range result.Edges {
+		fmt.Fprintf(&buf, `    <path d="%s"/>`+"\n", svgPath(edge))
+	}
+	buf.WriteString("  </g>\n")
+
+	for _, box := range result.Nodes {
+		writeSVGNode(&buf, box)
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+func svgPath(edge layout.Edge) string {
+	var sb strings.Builder
+	for i, p := range edge.Points {
+		switch {
+		case i == 0:
+			sb.WriteString("M")
+		case edge.Curved && i == 1:
+			sb.WriteString(" C")
+		case edge.Curved:
+			sb.WriteString(" ")
+		default:
+			sb.WriteString(" L")
+		}
+		fmt.Fprintf(&sb, "%.1f %.1f", p.X, p.Y)
+	}
+	return sb.String()
+}
+
+func writeSVGNode(buf *bytes.Buffer, box *layout.NodeBox) {
+	fill, stroke, textColor := nodeTheme(box)
+	if box.Node.Data.Hyperlink != "" {
+		fmt.Fprintf(buf, `  <a href="%s">`+"\n", xmlEscape(box.Node.Data.Hyperlink))
+	}
+
+	fmt.Fprintf(buf, `  <rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="%.1f" fill="%s" stroke="%s"/>`+"\n",
+		box.X, box.Y, box.W, box.H, cornerRadius(box), fill, stroke)
+	fmt.Fprintf(buf, `  <text x="%.1f" font-size="%.0f" fill="%s" text-anchor="middle">`,
+		box.X+box.W/2, box.FontSize, xmlEscape(textColor))
+	for i, line := range box.Lines {
+		fmt.Fprintf(buf, `<tspan x="%.1f" y="%.1f">%s</tspan>`, box.X+box.W/2, textBaseline(box, i), xmlEscape(line))
+	}
+	buf.WriteString("</text>\n")
+
+	if box.Node.Data.Hyperlink != "" {
+		buf.WriteString("  </a>\n")
+	}
+
+	// 折叠节点显示隐藏的子孙节点数
+	if box.Folded {
+		cx, cy := box.X+box.W+10, box.Y+box.H/2
+		fmt.Fprintf(buf, `  <circle cx="%.1f" cy="%.1f" r="8" fill="%s" stroke="%s"/>`+"\n", cx, cy, renderLeafFill, renderBranchLine)
+		fmt.Fprintf(buf, `  <text x="%.1f" y="%.1f" font-size="9" fill="%s" text-anchor="middle">%d</text>`+"\n",
+			cx, cy+3, renderBranchLine, box.Hidden)
+	}
+}
+
+func cornerRadius(box *layout.NodeBox) float64 {
+	if box.Depth == 0 {
+		return 8
+	}
+	return 4
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
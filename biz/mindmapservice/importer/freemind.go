@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"encoding/xml"
+	"mime/multipart"
+	"strings"
+
+	"forge/biz/entity"
+)
+
+// FreeMind导入器：解析 .mm XML
+type FreeMindImporter struct{}
+
+type freeMindMap struct {
+	Nodes []freeMindNode `xml:"node"`
+}
+
+type freeMindNode struct {
+	Text         string                `xml:"TEXT,attr"`
+	Folded       string                `xml:"FOLDED,attr"`
+	Link         string                `xml:"LINK,attr"`
+	Color        string                `xml:"COLOR,attr"`
+	Icons        []freeMindIcon        `xml:"icon"`
+	RichContents []freeMindRichContent `xml:"richcontent"`
+	Children     []freeMindNode        `xml:"node"`
+}
+
+type freeMindIcon struct {
+	Builtin string `xml:"BUILTIN,attr"`
+}
+
+type freeMindRichContent struct {
+	Type  string `xml:"TYPE,attr"`
+	Inner []byte `xml:",innerxml"`
+}
+
+func (p *FreeMindImporter) Supports(mimeType, ext string) bool {
+	return mimeType == "application/x-freemind" || ext == ".mm"
+}
+
+func (p *FreeMindImporter) Parse(fh *multipart.FileHeader) (*ImportedMindMap, error) {
+	content, err := readAll(fh)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc freeMindMap
+	if err := newXMLDecoder(content).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	root := &outlineNode{}
+	for i := range doc.Nodes {
+		appendFreeMindNode(root, &doc.Nodes[i])
+	}
+	return buildResult("", root.children)
+}
+
+func (p *FreeMindImporter) Name() string {
+	return "FreeMindImporter"
+}
+
+func appendFreeMindNode(parent *outlineNode, fmNode *freeMindNode) {
+	data := entity.NodeData{
+		Text:      normalizeText(fmNode.Text),
+		Hyperlink: fmNode.Link,
+		Color:     fmNode.Color,
+		Collapsed: fmNode.Folded == "true",
+	}
+	for _, icon := range fmNode.Icons {
+		if icon.Builtin != "" {
+			data.Icon = append(data.Icon, icon.Builtin)
+		}
+	}
+	for _, rich := range fmNode.RichContents {
+		switch rich.Type {
+		case "NODE":
+			// 富文本节点没有 TEXT 属性，从HTML中提取纯文本
+			if data.Text == "" {
+				data.Text = normalizeText(htmlText(rich.Inner))
+			}
+		case "NOTE":
+			data.Note = htmlText(rich.Inner)
+		}
+	}
+
+	node := parent.addChild(data)
+	for i := range fmNode.Children {
+		appendFreeMindNode(node, &fmNode.Children[i])
+	}
+}
+
+// htmlText 提取HTML片段中的纯文本，块级元素之间换行
+func htmlText(inner []byte) string {
+	decoder := newXMLDecoder(inner)
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var sb strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			sb.Write(t)
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "p", "div", "li", "br", "h1", "h2", "h3", "h4", "h5", "h6":
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(sb.String(), "\n") {
+		if line = normalizeText(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
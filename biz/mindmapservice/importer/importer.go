@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+
+	"forge/biz/entity"
+)
+
+// 导入文件大小上限
+const MaxImportFileSize = 10 << 20
+
+var ErrEmptyMindMap = errors.New("文件中没有可导入的节点")
+
+// ImportedMindMap 导入解析结果
+type ImportedMindMap struct {
+	Title string
+	Data  entity.MindMapData
+}
+
+// 思维导图导入器接口
+type MindMapImporter interface {
+	// Supports 检查是否支持导入该文件类型
+	Supports(mimeType, ext string) bool
+	// Parse 解析文件为思维导图
+	Parse(fh *multipart.FileHeader) (*ImportedMindMap, error)
+	// Name 返回导入器名称
+	Name() string
+}
+
+// 导入器注册表
+type ImporterRegistry struct {
+	importers []MindMapImporter
+	mu        sync.RWMutex
+}
+
+var (
+	globalRegistry = &ImporterRegistry{}
+	once           sync.Once
+)
+
+// 初始化并注册所有导入器
+func initRegistry() {
+	globalRegistry.Register(&MarkdownImporter{})
+	globalRegistry.Register(&OPMLImporter{})
+	globalRegistry.Register(&FreeMindImporter{})
+	globalRegistry.Register(&XMindImporter{})
+}
+
+// GetRegistry 获取全局导入器注册表
+func GetRegistry() *ImporterRegistry {
+	once.Do(initRegistry)
+	return globalRegistry
+}
+
+// Register 注册导入器
+func (r *ImporterRegistry) Register(importer MindMapImporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.importers = append(r.importers, importer)
+}
+
+// GetImporter 根据MIME类型和扩展名获取合适的导入器
+func (r *ImporterRegistry) GetImporter(mimeType, ext string) MindMapImporter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, importer := range r.importers {
+		if importer.Supports(mimeType, ext) {
+			return importer
+		}
+	}
+	return nil
+}
+
+// DetectMime 读取文件头检测MIME类型
+func DetectMime(fh *multipart.FileHeader) (string, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	buf := make([]byte, 512)
+	n, err := file.Read(buf)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read file header: %w", err)
+	}
+	if n == 0 {
+		return "", errors.New("file is empty")
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// readAll 读取上传文件全部内容
+func readAll(fh *multipart.FileHeader) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, MaxImportFileSize))
+}
+
+// outlineNode 解析过程中使用的可变树节点，解析完成后转换为实体
+type outlineNode struct {
+	data     entity.NodeData
+	children []*outlineNode
+}
+
+func (n *outlineNode) addChild(data entity.NodeData) *outlineNode {
+	child := &outlineNode{data: data}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *outlineNode) toEntity() entity.MindMapData {
+	result := entity.MindMapData{Data: n.data, Children: make([]entity.MindMapData, 0, len(n.children))}
+	for _, child := range n.children {
+		result.Children = append(result.Children, child.toEntity())
+	}
+	return result
+}
+
+// buildResult 多个顶层节点时以标题作为根节点包裹，单个顶层节点直接作为根节点
+func buildResult(title string, tops []*outlineNode) (*ImportedMindMap, error) {
+	if len(tops) == 0 {
+		return nil, ErrEmptyMindMap
+	}
+	if len(tops) == 1 {
+		root := tops[0]
+		if title == "" {
+			title = root.data.Text
+		}
+		return &ImportedMindMap{Title: title, Data: root.toEntity()}, nil
+	}
+	root := &outlineNode{data: entity.NodeData{Text: title}, children: tops}
+	return &ImportedMindMap{Title: title, Data: root.toEntity()}, nil
+}
+
+// normalizeText 折叠节点文本中的空白字符
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"mime/multipart"
+	"regexp"
+	"strings"
+
+	"forge/biz/entity"
+)
+
+var (
+	markdownHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownBulletRe  = regexp.MustCompile(`^([ \t]*)(?:[-*+]|\d+[.)])\s+(.*)$`)
+	markdownLinkRe    = regexp.MustCompile(`^\[(.+)\]\((\S+)\)$`)
+)
+
+// Markdown导入器：标题层级与无序/有序列表缩进共同决定节点层级
+type MarkdownImporter struct{}
+
+func (p *MarkdownImporter) Supports(mimeType, ext string) bool {
+	return mimeType == "text/markdown" || ext == ".md" || ext == ".markdown"
+}
+
+func (p *MarkdownImporter) Parse(fh *multipart.FileHeader) (*ImportedMindMap, error) {
+	content, err := readAll(fh)
+	if err != nil {
+		return nil, err
+	}
+
+	virtualRoot := &outlineNode{}
+	// headingStack[i] 为第 i+1 级标题当前对应的节点
+	var headingStack []*outlineNode
+	// bulletStack 记录当前列表各层级的缩进宽度与节点
+	type bulletLevel struct {
+		indent int
+		node   *outlineNode
+	}
+	var bulletStack []bulletLevel
+	var last *outlineNode
+	inCodeBlock := false
+
+	headingParent := func() *outlineNode {
+		for i := len(headingStack) - 1; i >= 0; i-- {
+			if headingStack[i] != nil {
+				return headingStack[i]
+			}
+		}
+		return virtualRoot
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxImportFileSize)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock || trimmed == "" {
+			continue
+		}
+
+		if m := markdownHeadingRe.FindStringSubmatch(trimmed); m != nil {
+			level := len(m[1])
+			if len(headingStack) >= level {
+				headingStack = headingStack[:level-1]
+			}
+			for len(headingStack) < level-1 {
+				headingStack = append(headingStack, nil)
+			}
+			last = headingParent().addChild(parseMarkdownText(m[2]))
+			headingStack = append(headingStack, last)
+			bulletStack = nil
+			continue
+		}
+
+		if m := markdownBulletRe.FindStringSubmatch(line); m != nil {
+			indent := indentWidth(m[1])
+			for len(bulletStack) > 0 && bulletStack[len(bulletStack)-1].indent >= indent {
+				bulletStack = bulletStack[:len(bulletStack)-1]
+			}
+			parent := headingParent()
+			if len(bulletStack) > 0 {
+				parent = bulletStack[len(bulletStack)-1].node
+			}
+			last = parent.addChild(parseMarkdownText(m[2]))
+			bulletStack = append(bulletStack, bulletLevel{indent: indent, node: last})
+			continue
+		}
+
+		// 引用块与普通段落作为上一个节点的备注
+		note := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+		if last == nil {
+			last = virtualRoot.addChild(entity.NodeData{Text: normalizeText(note)})
+			continue
+		}
+		if last.data.Note != "" {
+			last.data.Note += "\n"
+		}
+		last.data.Note += note
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildResult("", virtualRoot.children)
+}
+
+func (p *MarkdownImporter) Name() string {
+	return "MarkdownImporter"
+}
+
+// parseMarkdownText 整个条目为链接时拆分为文本与超链接
+func parseMarkdownText(text string) entity.NodeData {
+	text = normalizeText(text)
+	if m := markdownLinkRe.FindStringSubmatch(text); m != nil {
+		return entity.NodeData{Text: m[1], Hyperlink: m[2]}
+	}
+	return entity.NodeData{Text: text}
+}
+
+// indentWidth 计算缩进宽度，制表符按4个空格计算
+func indentWidth(prefix string) int {
+	width := 0
+	for _, r := range prefix {
+		if r == '\t' {
+			width += 4
+		} else {
+			width++
+		}
+	}
+	return width
+}
@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"mime/multipart"
+	"strings"
+
+	"forge/biz/entity"
+)
+
+// OPML导入器
+type OPMLImporter struct{}
+
+type opmlDocument struct {
+	Head struct {
+		Title string `xml:"title"`
+	} `xml:"head"`
+	Body struct {
+		Outlines []opmlOutline `xml:"outline"`
+	} `xml:"body"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr"`
+	Note     string        `xml:"_note,attr"`
+	URL      string        `xml:"url,attr"`
+	HTMLURL  string        `xml:"htmlUrl,attr"`
+	Category string        `xml:"category,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+func (p *OPMLImporter) Supports(mimeType, ext string) bool {
+	return mimeType == "text/x-opml" || ext == ".opml"
+}
+
+func (p *OPMLImporter) Parse(fh *multipart.FileHeader) (*ImportedMindMap, error) {
+	content, err := readAll(fh)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc opmlDocument
+	if err := newXMLDecoder(content).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	root := &outlineNode{}
+	for i := range doc.Body.Outlines {
+		appendOPMLOutline(root, &doc.Body.Outlines[i])
+	}
+	return buildResult(normalizeText(doc.Head.Title), root.children)
+}
+
+func (p *OPMLImporter) Name() string {
+	return "OPMLImporter"
+}
+
+func appendOPMLOutline(parent *outlineNode, outline *opmlOutline) {
+	text := outline.Text
+	if text == "" {
+		text = outline.Title
+	}
+	data := entity.NodeData{
+		Text:      normalizeText(text),
+		Note:      outline.Note,
+		Hyperlink: outline.URL,
+	}
+	if data.Hyperlink == "" {
+		data.Hyperlink = outline.HTMLURL
+	}
+	for _, category := range strings.Split(outline.Category, ",") {
+		if tag := strings.Trim(strings.TrimSpace(category), "/"); tag != "" {
+			data.Tags = append(data.Tags, tag)
+		}
+	}
+
+	node := parent.addChild(data)
+	for i := range outline.Outlines {
+		appendOPMLOutline(node, &outline.Outlines[i])
+	}
+}
+
+// newXMLDecoder 创建XML解码器，非UTF-8编码声明按UTF-8读取
+func newXMLDecoder(content []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return decoder
+}
@@ -0,0 +1,195 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+
+	"forge/biz/entity"
+)
+
+const mimeTypeZip = "application/zip"
+
+var ErrInvalidXMind = errors.New("无效的XMind文件")
+
+// XMind导入器：新版为 zip+content.json，XMind 8 及更早版本为 zip+content.xml
+type XMindImporter struct{}
+
+// 新版 content.json 结构
+type xmindJSONSheet struct {
+	Title     string         `json:"title"`
+	RootTopic xmindJSONTopic `json:"rootTopic"`
+}
+
+type xmindJSONTopic struct {
+	Title    string `json:"title"`
+	Href     string `json:"href"`
+	Branch   string `json:"branch"`
+	Children struct {
+		Attached []xmindJSONTopic `json:"attached"`
+	} `json:"children"`
+	Notes struct {
+		Plain struct {
+			Content string `json:"content"`
+		} `json:"plain"`
+	} `json:"notes"`
+	Labels  []string `json:"labels"`
+	Markers []struct {
+		MarkerID string `json:"markerId"`
+	} `json:"markers"`
+}
+
+// 旧版 content.xml 结构
+type xmindXMLContent struct {
+	Sheets []struct {
+		Title string        `xml:"title"`
+		Topic xmindXMLTopic `xml:"topic"`
+	} `xml:"sheet"`
+}
+
+type xmindXMLTopic struct {
+	Title    string `xml:"title"`
+	Href     string `xml:"href,attr"`
+	Branch   string `xml:"branch,attr"`
+	Children struct {
+		Topics []struct {
+			Type   string          `xml:"type,attr"`
+			Topics []xmindXMLTopic `xml:"topic"`
+		} `xml:"topics"`
+	} `xml:"children"`
+	Notes struct {
+		Plain string `xml:"plain"`
+	} `xml:"notes"`
+	Labels     []string `xml:"labels>label"`
+	MarkerRefs []struct {
+		MarkerID string `xml:"marker-id,attr"`
+	} `xml:"marker-refs>marker-ref"`
+}
+
+func (p *XMindImporter) Supports(mimeType, ext string) bool {
+	return ext == ".xmind" && (mimeType == "" || mimeType == mimeTypeZip)
+}
+
+func (p *XMindImporter) Parse(fh *multipart.FileHeader) (*ImportedMindMap, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader, err := zip.NewReader(f, fh.Size)
+	if err != nil {
+		return nil, ErrInvalidXMind
+	}
+
+	// 优先解析新版格式
+	if content, err := readZipEntry(reader, "content.json"); err == nil {
+		return parseXMindJSON(content)
+	}
+	if content, err := readZipEntry(reader, "content.xml"); err == nil {
+		return parseXMindXML(content)
+	}
+	return nil, ErrInvalidXMind
+}
+
+func (p *XMindImporter) Name() string {
+	return "XMindImporter"
+}
+
+func parseXMindJSON(content []byte) (*ImportedMindMap, error) {
+	var sheets []xmindJSONSheet
+	if err := json.Unmarshal(content, &sheets); err != nil {
+		return nil, err
+	}
+	if len(sheets) == 0 {
+		return nil, ErrEmptyMindMap
+	}
+
+	// 只导入第一个画布
+	sheet := &sheets[0]
+	root := &outlineNode{}
+	appendXMindJSONTopic(root, &sheet.RootTopic)
+	return buildResult(rootTitle(root, sheet.Title), root.children)
+}
+
+func appendXMindJSONTopic(parent *outlineNode, topic *xmindJSONTopic) {
+	data := entity.NodeData{
+		Text:      normalizeText(topic.Title),
+		Note:      topic.Notes.Plain.Content,
+		Hyperlink: topic.Href,
+		Tags:      topic.Labels,
+		Collapsed: topic.Branch == "folded",
+	}
+	for _, marker := range topic.Markers {
+		data.Icon = append(data.Icon, marker.MarkerID)
+	}
+
+	node := parent.addChild(data)
+	for i := range topic.Children.Attached {
+		appendXMindJSONTopic(node, &topic.Children.Attached[i])
+	}
+}
+
+func parseXMindXML(content []byte) (*ImportedMindMap, error) {
+	var doc xmindXMLContent
+	if err := newXMLDecoder(content).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Sheets) == 0 {
+		return nil, ErrEmptyMindMap
+	}
+
+	sheet := &doc.Sheets[0]
+	root := &outlineNode{}
+	appendXMindXMLTopic(root, &sheet.Topic)
+	return buildResult(rootTitle(root, sheet.Title), root.children)
+}
+
+func appendXMindXMLTopic(parent *outlineNode, topic *xmindXMLTopic) {
+	data := entity.NodeData{
+		Text:      normalizeText(topic.Title),
+		Note:      topic.Notes.Plain,
+		Hyperlink: topic.Href,
+		Tags:      topic.Labels,
+		Collapsed: topic.Branch == "folded",
+	}
+	for _, marker := range topic.MarkerRefs {
+		data.Icon = append(data.Icon, marker.MarkerID)
+	}
+
+	node := parent.addChild(data)
+	for _, group := range topic.Children.Topics {
+		// 只导入附着在主干上的子主题，忽略自由主题
+		if group.Type != "" && group.Type != "attached" {
+			continue
+		}
+		for i := range group.Topics {
+			appendXMindXMLTopic(node, &group.Topics[i])
+		}
+	}
+}
+
+// rootTitle 导图标题优先使用中心主题，画布默认标题仅作兜底
+func rootTitle(root *outlineNode, sheetTitle string) string {
+	if len(root.children) == 1 && root.children[0].data.Text != "" {
+		return root.children[0].data.Text
+	}
+	return normalizeText(sheetTitle)
+}
+
+func readZipEntry(reader *zip.Reader, name string) ([]byte, error) {
+	for _, file := range reader.File {
+		if file.Name != name {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(io.LimitReader(rc, MaxImportFileSize))
+	}
+	return nil, ErrInvalidXMind
+}
@@ -0,0 +1,460 @@
+package layout
+
+import (
+	"math"
+	"unicode"
+
+	"forge/biz/entity"
+)
+
+// 布局类型，与前端 simple-mind-map 的布局标识保持一致
+const (
+	LayoutMindMap               = "mindMap"
+	LayoutLogicalStructure      = "logicalStructure"
+	LayoutOrganizationStructure = "organizationStructure"
+	LayoutTimeline              = "timeline"
+	LayoutFishbone              = "fishbone"
+)
+
+// Layouts 服务端支持排版的全部布局类型
+var Layouts = []string{
+	LayoutMindMap,
+	LayoutLogicalStructure,
+	LayoutOrganizationStructure,
+	LayoutTimeline,
+	LayoutFishbone,
+}
+
+// IsValid 是否为支持的布局类型
+func IsValid(layoutType string) bool {
+	for _, l := range Layouts {
+		if l == layoutType {
+			return true
+		}
+	}
+	return false
+}
+
+// OrDefault 不支持的布局（早期导图可能保存了服务端不支持的布局）按逻辑结构图处理
+func OrDefault(layoutType string) string {
+	if IsValid(layoutType) {
+		return layoutType
+	}
+	return LayoutLogicalStructure
+}
+
+// LineHeightRatio 行高与字号的比例
+const LineHeightRatio = 1.4
+
+// 排版参数（单位：px）
+const (
+	maxTextWidth   = 220.0 // 单行最大文本宽度，超出自动换行
+	horizontalGap  = 48.0  // 水平方向父子节点间距
+	verticalGap    = 14.0  // 垂直方向兄弟节点间距
+	orgSiblingGap  = 24.0  // 组织结构图兄弟节点间距
+	orgLevelGap    = 48.0  // 组织结构图层级间距
+	catalogIndent  = 28.0  // 目录结构缩进
+	fishboneOffset = 36.0  // 鱼骨图分支与主干的距离
+	canvasMargin   = 24.0  // 画布边距
+)
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+type Rect struct {
+	X float64
+	Y float64
+	W float64
+	H float64
+}
+
+// NodeBox 节点排版结果
+type NodeBox struct {
+	Node     *entity.MindMapData
+	Depth    int
+	Rect                // 节点外框（左上角坐标与宽高）
+	Lines    []string   // 按宽度换行后的文本
+	FontSize float64    // 字号
+	Folded   bool       // 是否折叠（折叠节点的子节点不参与排版）
+	Hidden   int        // 折叠隐藏的子孙节点数
+	children []*NodeBox // 参与排版的子节点
+	subW     float64    // 子树宽度
+	subH     float64    // 子树高度
+}
+
+// Edge 连线，Curved 为 true 时 Points 为三次贝塞尔曲线的4个控制点，否则为折线
+type Edge struct {
+	FromUID string
+	ToUID   string
+	Points  []Point
+	Curved  bool
+}
+
+// Result 排版结果，坐标已平移到以画布左上角为原点
+type Result struct {
+	Layout string
+	Nodes  []*NodeBox // 先序遍历顺序
+	Edges  []Edge
+	Bounds Rect
+}
+
+// Compute 按布局类型计算节点坐标与连线，未知布局按逻辑结构图处理
+func Compute(data *entity.MindMapData, layoutType string) *Result {
+	root := buildTree(data, 0)
+	result := &Result{Layout: layoutType}
+
+	switch layoutType {
+	case LayoutMindMap:
+		layoutMindMap(root, result)
+	case LayoutOrganizationStructure:
+		layoutOrganization(root, result)
+	case LayoutTimeline:
+		layoutTimeline(root, result)
+	case LayoutFishbone:
+		layoutFishbone(root, result)
+	default:
+		result.Layout = LayoutLogicalStructure
+		measureHorizontal(root)
+		placeHorizontal(root, 0, 0, 1, result)
+	}
+
+	collectNodes(root, result)
+	normalize(result)
+	return result
+}
+
+// buildTree 测量节点尺寸并构建排版树
+func buildTree(data *entity.MindMapData, depth int) *NodeBox {
+	fontSize, padX, padY := nodeStyle(depth)
+	lines, textW := wrapText(data.Data.Text, fontSize)
+	lineHeight := fontSize * LineHeightRatio
+
+	box := &NodeBox{
+		Node:     data,
+		Depth:    depth,
+		Lines:    lines,
+		FontSize: fontSize,
+	}
+	box.W = textW + padX*2
+	box.H = float64(len(lines))*lineHeight + padY*2
+
+	if data.Data.Collapsed && len(data.Children) > 0 {
+		box.Folded = true
+		box.Hidden = data.CountNodes() - 1
+		return box
+	}
+	for i := range data.Children {
+		box.children = append(box.children, buildTree(&data.Children[i], depth+1))
+	}
+	return box
+}
+
+// nodeStyle 按层级返回字号与内边距
+func nodeStyle(depth int) (fontSize, padX, padY float64) {
+	switch depth {
+	case 0:
+		return 18, 16, 10
+	case 1:
+		return 15, 12, 7
+	default:
+		return 13, 8, 4
+	}
+}
+
+// wrapText 按估算字宽换行，中日韩字符按一个字号宽度计算，其余按0.6倍
+func wrapText(text string, fontSize float64) ([]string, float64) {
+	if text == "" {
+		return []string{""}, fontSize
+	}
+
+	var lines []string
+	var line []rune
+	lineW, maxW := 0.0, 0.0
+	for _, r := range text {
+		if r == '\n' {
+			lines = append(lines, string(line))
+			maxW = math.Max(maxW, lineW)
+			line, lineW = nil, 0
+			continue
+		}
+		w := runeWidth(r, fontSize)
+		if lineW+w > maxTextWidth && len(line) > 0 {
+			lines = append(lines, string(line))
+			maxW = math.Max(maxW, lineW)
+			line, lineW = nil, 0
+		}
+		line = append(line, r)
+		lineW += w
+	}
+	lines = append(lines, string(line))
+	maxW = math.Max(maxW, lineW)
+	return lines, maxW
+}
+
+func runeWidth(r rune, fontSize float64) float64 {
+	if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r) || (r >= 0xFF00 && r <= 0xFFEF) || (r >= 0x3000 && r <= 0x303F) {
+		return fontSize
+	}
+	return fontSize * 0.6
+}
+
+// measureHorizontal 计算水平展开（子节点在左右两侧）时的子树高度
+func measureHorizontal(n *NodeBox) float64 {
+	n.subH = n.H
+	if len(n.children) == 0 {
+		return n.subH
+	}
+	childrenH := 0.0
+	for i, child := range n.children {
+		if i > 0 {
+			childrenH += verticalGap
+		}
+		childrenH += measureHorizontal(child)
+	}
+	n.subH = math.Max(n.H, childrenH)
+	return n.subH
+}
+
+// placeHorizontal 水平展开排版，dir 为 1 向右展开，-1 向左展开（此时 x 为节点右边界）
+func placeHorizontal(n *NodeBox, x, top float64, dir int, result *Result) {
+	if dir > 0 {
+		n.X = x
+	} else {
+		n.X = x - n.W
+	}
+	n.Y = top + (n.subH-n.H)/2
+	placeHorizontalChildren(n, n.children, top+(n.subH-childrenHeight(n.children))/2, dir, result)
+}
+
+func placeHorizontalChildren(parent *NodeBox, children []*NodeBox, top float64, dir int, result *Result) {
+	nextX := parent.X + parent.W + horizontalGap
+	if dir < 0 {
+		nextX = parent.X - horizontalGap
+	}
+	for _, child := range children {
+		placeHorizontal(child, nextX, top, dir, result)
+		top += child.subH + verticalGap
+		result.Edges = append(result.Edges, curveEdge(parent, child, dir))
+	}
+}
+
+func childrenHeight(children []*NodeBox) float64 {
+	h := 0.0
+	for i, child := range children {
+		if i > 0 {
+			h += verticalGap
+		}
+		h += child.subH
+	}
+	return h
+}
+
+// curveEdge 水平方向父子节点之间的贝塞尔曲线
+func curveEdge(parent, child *NodeBox, dir int) Edge {
+	from := Point{X: parent.X + parent.W, Y: parent.Y + parent.H/2}
+	to := Point{X: child.X, Y: child.Y + child.H/2}
+	if dir < 0 {
+		from.X = parent.X
+		to.X = child.X + child.W
+	}
+	midX := (from.X + to.X) / 2
+	return Edge{
+		FromUID: parent.Node.Data.UID,
+		ToUID:   child.Node.Data.UID,
+		Points:  []Point{from, {X: midX, Y: from.Y}, {X: midX, Y: to.Y}, to},
+		Curved:  true,
+	}
+}
+
+// layoutMindMap 思维导图：根节点居中，一级分支前半在右、后半在左
+func layoutMindMap(root *NodeBox, result *Result) {
+	split := (len(root.children) + 1) / 2
+	right, left := root.children[:split], root.children[split:]
+	for _, child := range root.children {
+		measureHorizontal(child)
+	}
+
+	root.X, root.Y = 0, -root.H/2
+	placeHorizontalChildren(root, right, -childrenHeight(right)/2, 1, result)
+	placeHorizontalChildren(root, left, -childrenHeight(left)/2, -1, result)
+}
+
+// measureVertical 计算自上而下展开时的子树宽度
+func measureVertical(n *NodeBox) float64 {
+	n.subW = n.W
+	if len(n.children) == 0 {
+		return n.subW
+	}
+	n.subW = math.Max(n.W, childrenWidth(n.children, measureVertical))
+	return n.subW
+}
+
+func childrenWidth(children []*NodeBox, measure func(*NodeBox) float64) float64 {
+	w := 0.0
+	for i, child := range children {
+		if i > 0 {
+			w += orgSiblingGap
+		}
+		w += measure(child)
+	}
+	return w
+}
+
+// layoutOrganization 组织结构图：自上而下展开，父子节点以直角折线连接
+func layoutOrganization(root *NodeBox, result *Result) {
+	measureVertical(root)
+	placeVertical(root, 0, 0, result)
+}
+
+func placeVertical(n *NodeBox, left, y float64, result *Result) {
+	n.X = left + (n.subW-n.W)/2
+	n.Y = y
+	childrenW := childrenWidth(n.children, func(c *NodeBox) float64 { return c.subW })
+	x := left + (n.subW-childrenW)/2
+	for _, child := range n.children {
+		placeVertical(child, x, y+n.H+orgLevelGap, result)
+		x += child.subW + orgSiblingGap
+		result.Edges = append(result.Edges, elbowEdge(n, child))
+	}
+}
+
+// elbowEdge 自上而下的直角折线
+func elbowEdge(parent, child *NodeBox) Edge {
+	from := Point{X: parent.X + parent.W/2, Y: parent.Y + parent.H}
+	to := Point{X: child.X + child.W/2, Y: child.Y}
+	midY := (from.Y + to.Y) / 2
+	return Edge{
+		FromUID: parent.Node.Data.UID,
+		ToUID:   child.Node.Data.UID,
+		Points:  []Point{from, {X: from.X, Y: midY}, {X: to.X, Y: midY}, to},
+	}
+}
+
+// measureCatalog 计算目录（缩进列表）形式的子树尺寸
+func measureCatalog(n *NodeBox) float64 {
+	n.subW, n.subH = n.W, n.H
+	for _, child := range n.children {
+		measureCatalog(child)
+		n.subW = math.Max(n.subW, catalogIndent+child.subW)
+		n.subH += verticalGap + child.subH
+	}
+	return n.subW
+}
+
+// placeCatalog 目录形式排版，子节点缩进排列在父节点下方
+func placeCatalog(n *NodeBox, x, y float64, result *Result) {
+	n.X, n.Y = x, y
+	top := y + n.H + verticalGap
+	for _, child := range n.children {
+		placeCatalog(child, x+catalogIndent, top, result)
+		top += child.subH + verticalGap
+
+		from := Point{X: n.X + catalogIndent/2, Y: n.Y + n.H}
+		to := Point{X: child.X, Y: child.Y + child.H/2}
+		result.Edges = append(result.Edges, Edge{
+			FromUID: n.Node.Data.UID,
+			ToUID:   child.Node.Data.UID,
+			Points:  []Point{from, {X: from.X, Y: to.Y}, to},
+		})
+	}
+}
+
+// layoutTimeline 时间轴：一级分支沿水平主轴依次排列，其下以缩进列表展开
+func layoutTimeline(root *NodeBox, result *Result) {
+	root.X, root.Y = 0, -root.H/2
+	prev := root
+	x := root.W + horizontalGap
+	for _, child := range root.children {
+		measureCatalog(child)
+		placeCatalog(child, x, -child.H/2, result)
+
+		from := Point{X: prev.X + prev.W, Y: 0}
+		result.Edges = append(result.Edges, Edge{
+			FromUID: root.Node.Data.UID,
+			ToUID:   child.Node.Data.UID,
+			Points:  []Point{from, {X: child.X, Y: 0}},
+		})
+		prev = child
+		x += child.subW + horizontalGap
+	}
+}
+
+// layoutFishbone 鱼骨图：根节点为鱼头位于右侧，一级分支交替分布在主干上下
+func layoutFishbone(root *NodeBox, result *Result) {
+	root.X, root.Y = 0, -root.H/2
+	cursors := [2]float64{-horizontalGap, -horizontalGap} // 上、下两侧各自的右边界
+	tail := 0.0
+	for i, child := range root.children {
+		measureCatalog(child)
+		side := i % 2
+		x := cursors[side] - child.subW
+		y := fishboneOffset
+		if side == 0 {
+			y = -fishboneOffset - child.subH
+		}
+		placeCatalog(child, x, y, result)
+		cursors[side] = x - horizontalGap
+		tail = math.Min(tail, x)
+
+		// 分支斜线连接到主干
+		from := Point{X: child.X + child.W/2, Y: child.Y + child.H}
+		if side == 1 {
+			from.Y = child.Y
+		}
+		result.Edges = append(result.Edges, Edge{
+			FromUID: root.Node.Data.UID,
+			ToUID:   child.Node.Data.UID,
+			Points:  []Point{from, {X: from.X + fishboneOffset, Y: 0}},
+		})
+	}
+
+	// 主干
+	if len(root.children) > 0 {
+		result.Edges = append(result.Edges, Edge{
+			FromUID: root.Node.Data.UID,
+			Points:  []Point{{X: root.X, Y: 0}, {X: tail - horizontalGap/2, Y: 0}},
+		})
+	}
+}
+
+func collectNodes(n *NodeBox, result *Result) {
+	result.Nodes = append(result.Nodes, n)
+	for _, child := range n.children {
+		collectNodes(child, result)
+	}
+}
+
+// normalize 平移坐标使画布从边距处开始，并计算整体尺寸
+func normalize(result *Result) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	extend := func(x, y float64) {
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	for _, n := range result.Nodes {
+		extend(n.X, n.Y)
+		extend(n.X+n.W, n.Y+n.H)
+	}
+	for _, e := range result.Edges {
+		for _, p := range e.Points {
+			extend(p.X, p.Y)
+		}
+	}
+
+	dx, dy := canvasMargin-minX, canvasMargin-minY
+	for _, n := range result.Nodes {
+		n.X += dx
+		n.Y += dy
+	}
+	for i := range result.Edges {
+		for j := range result.Edges[i].Points {
+			result.Edges[i].Points[j].X += dx
+			result.Edges[i].Points[j].Y += dy
+		}
+	}
+	result.Bounds = Rect{W: maxX - minX + canvasMargin*2, H: maxY - minY + canvasMargin*2}
+}
@@ -0,0 +1,179 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"forge/biz/entity"
+	"forge/biz/generationservice"
+)
+
+// 检查阈值
+const (
+	longTextRunes      = 50  // 单个节点文本的建议最大长度
+	unbalancedMinNodes = 10  // 最大分支的节点数不少于该值时才检查失衡
+	unbalancedRatio    = 4.0 // 最大分支节点数超过同级其他分支平均值的倍数
+)
+
+// 用户导图的占位文本：英文按完整单词匹配，避免 Latest、Contest 等正常文本被误报；
+// 中文只匹配明确的占位短语，“测试”等常见词不视为占位符
+var (
+	placeholderWords   = map[string]struct{}{"todo": {}, "tbd": {}, "placeholder": {}}
+	placeholderPhrases = []string{"待填充", "待补充", "占位符"}
+)
+
+// Lint 检查节点树，返回定位到节点的问题及结构统计（评分由调用方补充）
+func Lint(data *entity.MindMapData) *entity.MindMapLintReport {
+	report := &entity.MindMapLintReport{Issues: make([]entity.MindMapLintIssue, 0)}
+	if data == nil {
+		return report
+	}
+
+	sizes := make(map[*entity.MindMapData]int)
+	countSubtree(data, sizes)
+
+	l := &linter{report: report, sizes: sizes}
+	totalRunes := 0
+	data.Walk(func(node, _ *entity.MindMapData, depth int) bool {
+		totalRunes += utf8.RuneCountInString(node.Data.Text)
+		report.MaxDepth = max(report.MaxDepth, depth+1)
+		return true
+	})
+	report.NodeCount = sizes[data]
+	report.AvgTextLength = float64(totalRunes) / float64(report.NodeCount)
+
+	if report.MaxDepth < generationservice.QualityMinTreeDepth {
+		l.add(entity.LintRuleTooShallow, entity.LintSeverityInfo, data, []string{data.Data.Text},
+			fmt.Sprintf("导图只有 %d 层，建议为中心主题补充分支", report.MaxDepth))
+	}
+	l.visit(data, nil, 1, "")
+	return report
+}
+
+type linter struct {
+	report *entity.MindMapLintReport
+	sizes  map[*entity.MindMapData]int
+}
+
+// visit 先序检查节点，duplicateOf 不为空表示该节点与之前的同级节点文本重复
+func (l *linter) visit(node *entity.MindMapData, parentPath []string, depth int, duplicateOf string) {
+	path := append(parentPath[:len(parentPath):len(parentPath)], node.Data.Text)
+	text := strings.TrimSpace(node.Data.Text)
+
+	if text == "" {
+		l.add(entity.LintRuleEmptyText, entity.LintSeverityError, node, path, "节点文本为空")
+	} else {
+		if IsPlaceholderText(text) {
+			l.add(entity.LintRulePlaceholderText, entity.LintSeverityWarning, node, path, "节点文本包含占位符，请替换为实际内容")
+		}
+		if runes := utf8.RuneCountInString(text); runes > longTextRunes {
+			l.add(entity.LintRuleLongText, entity.LintSeverityWarning, node, path,
+				fmt.Sprintf("节点文本有 %d 个字，建议精简到 %d 字以内，详细内容可放入备注", runes, longTextRunes))
+		}
+	}
+	if duplicateOf != "" {
+		l.add(entity.LintRuleDuplicateSibling, entity.LintSeverityWarning, node, path,
+			fmt.Sprintf("与同级节点“%s”文本重复", duplicateOf))
+	}
+	// 只在首个超出层级上限的节点上提示，避免其子孙重复报告
+	if depth == generationservice.QualityMaxTreeDepth+1 {
+		l.add(entity.LintRuleTooDeep, entity.LintSeverityWarning, node, path,
+			fmt.Sprintf("层级超过 %d 层，建议合并层级或将该分支提取为新导图", generationservice.QualityMaxTreeDepth))
+	}
+
+	// 失衡的分支在父节点处检查，问题定位到该分支的根节点
+	if largest := l.unbalancedChild(node); largest >= 0 {
+		child := &node.Children[largest]
+		l.add(entity.LintRuleUnbalanced, entity.LintSeverityInfo, child, append(path[:len(path):len(path)], child.Data.Text),
+			fmt.Sprintf("该分支包含 %d 个节点，远多于同级其他分支，建议拆分", l.sizes[child]))
+	}
+
+	seen := make(map[string]string, len(node.Children))
+	for i := range node.Children {
+		child := &node.Children[i]
+		key := strings.ToLower(strings.Join(strings.Fields(child.Data.Text), " "))
+		dup := ""
+		if key != "" {
+			if first, ok := seen[key]; ok {
+				dup = first
+			} else {
+				seen[key] = child.Data.Text
+			}
+		}
+		l.visit(child, path, depth+1, dup)
+	}
+}
+
+// unbalancedChild 返回规模远超同级其他分支的子节点下标，不存在时返回 -1
+func (l *linter) unbalancedChild(node *entity.MindMapData) int {
+	if len(node.Children) < 2 {
+		return -1
+	}
+	largest, total := 0, 0
+	for i := range node.Children {
+		size := l.sizes[&node.Children[i]]
+		total += size
+		if size > l.sizes[&node.Children[largest]] {
+			largest = i
+		}
+	}
+	maxSize := l.sizes[&node.Children[largest]]
+	othersAvg := float64(total-maxSize) / float64(len(node.Children)-1)
+	if maxSize < unbalancedMinNodes || float64(maxSize) <= unbalancedRatio*othersAvg {
+		return -1
+	}
+	return largest
+}
+
+func (l *linter) add(rule, severity string, node *entity.MindMapData, path []string, message string) {
+	l.report.Issues = append(l.report.Issues, entity.MindMapLintIssue{
+		Rule:     rule,
+		Severity: severity,
+		Message:  message,
+		NodeUID:  node.Data.UID,
+		Path:     path,
+	})
+}
+
+// countSubtree 统计每个节点的子树规模（含自身）
+func countSubtree(node *entity.MindMapData, sizes map[*entity.MindMapData]int) int {
+	size := 1
+	for i := range node.Children {
+		size += countSubtree(&node.Children[i], sizes)
+	}
+	sizes[node] = size
+	return size
+}
+
+// IsPlaceholderText 节点文本是否为模板占位节点或占位内容，导图检查的质量评分使用同一判断
+func IsPlaceholderText(text string) bool {
+	if _, ok := (entity.NodeData{Text: text}).PlaceholderHint(); ok {
+		return true
+	}
+	return isPlaceholderText(text)
+}
+
+// isPlaceholderText 节点文本是否为占位内容
+func isPlaceholderText(text string) bool {
+	lowerText := strings.ToLower(text)
+	for _, phrase := range placeholderPhrases {
+		if strings.Contains(lowerText, phrase) {
+			return true
+		}
+	}
+	// 按非英文字母数字切分出英文单词，中文与英文相连时同样能切开
+	words := strings.FieldsFunc(lowerText, func(r rune) bool {
+		return r >= utf8.RuneSelf || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+	for _, word := range words {
+		if _, ok := placeholderWords[word]; ok {
+			return true
+		}
+		if len(word) >= 3 && strings.Trim(word, "x") == "" {
+			return true
+		}
+	}
+	return false
+}
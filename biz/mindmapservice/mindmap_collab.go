@@ -0,0 +1,462 @@
+package mindmapservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/infra/cache"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+	"forge/util"
+)
+
+var (
+	ErrCollabSessionNotFound = errors.New("协同会话不存在或已过期")
+	ErrCollabBaseExpired     = errors.New("协同基线版本已失效，请重新同步导图")
+)
+
+const (
+	// collabPresenceTimeout 超过该时长未刷新的在线成员视为已离线
+	collabPresenceTimeout = 90 * time.Second
+	// collabCommitRetries 提交时遇到并发写入冲突的最大重试次数
+	collabCommitRetries = 3
+)
+
+// JoinCollabSession 加入导图的实时协同编辑（有查看权限即可），返回会话信息、最新导图及在线成员
+func (s *MindMapServiceImpl) JoinCollabSession(ctx context.Context, mapID string) (session *types.CollabSession, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.join_collab_session", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, mapID, session, err)
+	}()
+
+	mindMap, role, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := util.GenerateStringID()
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to generate collab session id: %v", err)
+		return nil, ErrInternalError
+	}
+
+	user, _ := entity.GetUser(ctx)
+	member := &types.CollabMember{
+		SessionID:  sessionID,
+		UserID:     user.UserID,
+		UserName:   user.UserName,
+		Avatar:     user.Avatar,
+		Role:       role,
+		LastSeenAt: time.Now(),
+	}
+	if err = s.saveCollabMember(ctx, mapID, member); err != nil {
+		return nil, ErrInternalError
+	}
+
+	members, err := s.listCollabMembers(ctx, mapID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	s.publishCollabEvent(ctx, &types.CollabEvent{
+		Type:      types.CollabEventJoin,
+		MapID:     mapID,
+		SessionID: sessionID,
+		UserID:    user.UserID,
+		Member:    member,
+	})
+
+	zlog.CtxInfof(ctx, "collab session joined, mapID: %s, sessionID: %s, userID: %s", mapID, sessionID, user.UserID)
+	return &types.CollabSession{
+		SessionID: sessionID,
+		Role:      role,
+		MindMap:   mindMap,
+		Members:   members,
+	}, nil
+}
+
+// LeaveCollabSession 离开实时协同编辑
+func (s *MindMapServiceImpl) LeaveCollabSession(ctx context.Context, mapID, sessionID string) error {
+	member, err := s.getOwnCollabMember(ctx, mapID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := cache.RemoveCollabPresence(ctx, mapID, sessionID); err != nil {
+		zlog.CtxErrorf(ctx, "failed to remove collab presence: %v", err)
+		return ErrInternalError
+	}
+
+	s.publishCollabEvent(ctx, &types.CollabEvent{
+		Type:      types.CollabEventLeave,
+		MapID:     mapID,
+		SessionID: sessionID,
+		UserID:    member.UserID,
+		Member:    member,
+	})
+
+	zlog.CtxInfof(ctx, "collab session left, mapID: %s, sessionID: %s", mapID, sessionID)
+	return nil
+}
+
+// UpdateCollabPresence 刷新会话在线状态；selectedNodeID 不为空时更新选中节点并广播（空字符串表示取消选中）
+func (s *MindMapServiceImpl) UpdateCollabPresence(ctx context.Context, mapID, sessionID string, selectedNodeID *string) error {
+	member, err := s.getOwnCollabMember(ctx, mapID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	member.LastSeenAt = time.Now()
+	if selectedNodeID != nil {
+		member.SelectedNodeID = *selectedNodeID
+	}
+	if err := s.saveCollabMember(ctx, mapID, member); err != nil {
+		return ErrInternalError
+	}
+
+	if selectedNodeID != nil {
+		s.publishCollabEvent(ctx, &types.CollabEvent{
+			Type:      types.CollabEventSelect,
+			MapID:     mapID,
+			SessionID: sessionID,
+			UserID:    member.UserID,
+			Member:    member,
+		})
+	}
+	return nil
+}
+
+// CommitCollabOperations 提交协同编辑操作
+// 操作基于客户端持有的 BaseVersion 生成，服务端将其变换到最新版本后执行：节点以UID寻址，
+// 位置参数换算为前序兄弟节点锚点，目标已被并发删除等失效的操作会被丢弃而不是整体失败
+func (s *MindMapServiceImpl) CommitCollabOperations(ctx context.Context, mapID string, req *types.CommitCollabOperationsParams) (result *types.CollabCommitResult, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.commit_collab_operations", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, result, err)
+	}()
+
+	if req == nil || req.BaseVersion <= 0 || len(req.Operations) == 0 || len(req.Operations) > maxPatchOperations {
+		zlog.CtxErrorf(ctx, "invalid collab operations")
+		return nil, ErrInvalidParams
+	}
+	if _, err = s.getOwnCollabMember(ctx, mapID, req.SessionID); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < collabCommitRetries; attempt++ {
+		existingMindMap, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleEditor)
+		if err != nil {
+			return nil, err
+		}
+		if req.BaseVersion > existingMindMap.Version {
+			zlog.CtxWarnf(ctx, "collab base version ahead of current, mapID: %s, base: %d, current: %d",
+				mapID, req.BaseVersion, existingMindMap.Version)
+			return nil, ErrCollabBaseExpired
+		}
+
+		// 基线数据：与最新版本一致时直接使用，否则从历史版本中读取
+		base := existingMindMap.Data.Clone()
+		if req.BaseVersion < existingMindMap.Version {
+			revision, revErr := s.mindMapRepo.GetMindMapRevision(ctx, mapID, req.BaseVersion)
+			if revErr != nil {
+				if errors.Is(revErr, repo.ErrMindMapRevisionNotFound) {
+					return nil, ErrCollabBaseExpired
+				}
+				zlog.CtxErrorf(ctx, "failed to get collab base revision: %v", revErr)
+				return nil, ErrInternalError
+			}
+			base = revision.Data.Clone()
+		}
+
+		data := existingMindMap.Data.Clone()
+		result, err = rebaseNodeOperations(&base, &data, req.Operations)
+		if err != nil {
+			zlog.CtxErrorf(ctx, "failed to rebase collab operations: %v", err)
+			return nil, ErrInternalError
+		}
+		if len(result.Operations) == 0 {
+			// 全部操作均已失效，无需写入
+			result.Version = existingMindMap.Version
+			return result, nil
+		}
+
+		result.Version, err = s.updateMindMap(ctx, mapID, &types.UpdateMindMapParams{
+			Data:            &data,
+			ExpectedVersion: &existingMindMap.Version,
+		}, entity.RevisionSourceCollab)
+		if errors.Is(err, ErrVersionConflict) {
+			zlog.CtxWarnf(ctx, "collab commit conflict, retrying, mapID: %s, attempt: %d", mapID, attempt+1)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		user, _ := entity.GetUser(ctx)
+		s.publishCollabEvent(ctx, &types.CollabEvent{
+			Type:       types.CollabEventOperation,
+			MapID:      mapID,
+			Version:    result.Version,
+			SessionID:  req.SessionID,
+			UserID:     user.UserID,
+			Operations: result.Operations,
+		})
+
+		zlog.CtxInfof(ctx, "collab operations committed, mapID: %s, base: %d, version: %d, applied: %d, skipped: %d",
+			mapID, req.BaseVersion, result.Version, len(result.Operations), len(result.SkippedIndexes))
+		return result, nil
+	}
+
+	return nil, ErrVersionConflict
+}
+
+// SubscribeCollabEvents 订阅全部导图的协同事件，阻塞直到ctx结束
+func (s *MindMapServiceImpl) SubscribeCollabEvents(ctx context.Context, handler func(event *types.CollabEvent)) {
+	cache.SubscribeCollabEvents(ctx, func(mapID string, payload []byte) {
+		event := &types.CollabEvent{}
+		if err := json.Unmarshal(payload, event); err != nil {
+			zlog.Warnf("failed to unmarshal collab event, mapID: %s, err: %v", mapID, err)
+			return
+		}
+		handler(event)
+	})
+}
+
+// publishCollabEvent 广播协同事件，失败只记录日志，不影响已完成的写入
+func (s *MindMapServiceImpl) publishCollabEvent(ctx context.Context, event *types.CollabEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to marshal collab event: %v", err)
+		return
+	}
+	if err := cache.PublishCollabEvent(ctx, event.MapID, payload); err != nil {
+		zlog.CtxErrorf(ctx, "failed to publish collab event, mapID: %s, type: %s, err: %v", event.MapID, event.Type, err)
+	}
+}
+
+// publishCollabReset 通知协同编辑中的客户端导图已被整体覆盖
+func (s *MindMapServiceImpl) publishCollabReset(ctx context.Context, mapID string, version int64) {
+	user, _ := entity.GetUser(ctx)
+	s.publishCollabEvent(ctx, &types.CollabEvent{
+		Type:    types.CollabEventReset,
+		MapID:   mapID,
+		Version: version,
+		UserID:  user.UserID,
+	})
+}
+
+// getOwnCollabMember 获取当前用户自己的协同会话
+func (s *MindMapServiceImpl) getOwnCollabMember(ctx context.Context, mapID, sessionID string) (*types.CollabMember, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, ErrPermissionDenied
+	}
+	if mapID == "" || sessionID == "" {
+		return nil, ErrInvalidParams
+	}
+
+	value, err := cache.GetCollabPresence(ctx, mapID, sessionID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to get collab presence: %v", err)
+		return nil, ErrInternalError
+	}
+	if value == "" {
+		return nil, ErrCollabSessionNotFound
+	}
+
+	member := &types.CollabMember{}
+	if err := json.Unmarshal([]byte(value), member); err != nil {
+		zlog.CtxErrorf(ctx, "failed to unmarshal collab presence: %v", err)
+		return nil, ErrInternalError
+	}
+	if member.UserID != user.UserID {
+		zlog.CtxWarnf(ctx, "collab session belongs to another user, sessionID: %s", sessionID)
+		return nil, ErrCollabSessionNotFound
+	}
+	return member, nil
+}
+
+func (s *MindMapServiceImpl) saveCollabMember(ctx context.Context, mapID string, member *types.CollabMember) error {
+	value, err := json.Marshal(member)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to marshal collab presence: %v", err)
+		return err
+	}
+	if err := cache.SetCollabPresence(ctx, mapID, member.SessionID, string(value)); err != nil {
+		zlog.CtxErrorf(ctx, "failed to set collab presence: %v", err)
+		return err
+	}
+	return nil
+}
+
+// listCollabMembers 获取在线成员（按加入先后排序），顺带清理超时未刷新的成员
+func (s *MindMapServiceImpl) listCollabMembers(ctx context.Context, mapID string) ([]*types.CollabMember, error) {
+	values, err := cache.ListCollabPresence(ctx, mapID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list collab presence: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	members := make([]*types.CollabMember, 0, len(values))
+	stale := make([]string, 0)
+	for sessionID, value := range values {
+		member := &types.CollabMember{}
+		if err := json.Unmarshal([]byte(value), member); err != nil || now.Sub(member.LastSeenAt) > collabPresenceTimeout {
+			stale = append(stale, sessionID)
+			continue
+		}
+		members = append(members, member)
+	}
+	if err := cache.RemoveCollabPresence(ctx, mapID, stale...); err != nil {
+		zlog.CtxWarnf(ctx, "failed to remove stale collab presence: %v", err)
+	}
+
+	// 会话ID为雪花ID，按ID排序即按加入先后排序
+	sort.Slice(members, func(i, j int) bool {
+		if len(members[i].SessionID) != len(members[j].SessionID) {
+			return len(members[i].SessionID) < len(members[j].SessionID)
+		}
+		return members[i].SessionID < members[j].SessionID
+	})
+	return members, nil
+}
+
+// rebaseNodeOperations 将基于 base 生成的操作变换后在 current 上执行
+// 对每个操作，先在基线树上把位置参数换算为"前序兄弟节点UID"锚点，再按锚点在最新树中还原实际位置，
+// 因此与并发的增删移动互不干扰；同一节点的并发改名以后提交者为准，在最新树上执行失败的操作被丢弃
+func rebaseNodeOperations(base, current *entity.MindMapData, ops []types.NodeOperation) (*types.CollabCommitResult, error) {
+	result := &types.CollabCommitResult{
+		Operations:     make([]types.NodeOperation, 0, len(ops)),
+		AddedNodeIDs:   make([]string, 0),
+		SkippedIndexes: make([]int, 0),
+	}
+
+	for i := range ops {
+		op, err := prepareNodeOperation(ops[i])
+		if err != nil {
+			return nil, err
+		}
+
+		anchors, hasAnchors := positionAnchors(base, &op)
+
+		// 基线树同步执行，使同批次后续操作的位置参数仍基于正确的状态换算（失败时忽略）
+		baseOp := op
+		_, _ = applyNodeOperation(base, &baseOp)
+
+		if hasAnchors {
+			index, ok := resolveAnchorIndex(current, &op, anchors)
+			if !ok {
+				result.SkippedIndexes = append(result.SkippedIndexes, i)
+				continue
+			}
+			op.Index = &index
+		}
+
+		addedID, err := applyNodeOperation(current, &op)
+		if err != nil {
+			result.SkippedIndexes = append(result.SkippedIndexes, i)
+			continue
+		}
+		if addedID != "" {
+			result.AddedNodeIDs = append(result.AddedNodeIDs, addedID)
+		}
+		result.Operations = append(result.Operations, op)
+	}
+	return result, nil
+}
+
+// positionAnchors 计算带位置参数的操作在基线树上的前序兄弟节点UID（不含被移动的节点本身）
+func positionAnchors(base *entity.MindMapData, op *types.NodeOperation) ([]string, bool) {
+	if op.Index == nil {
+		return nil, false
+	}
+
+	var parent *entity.MindMapData
+	switch op.Op {
+	case types.NodeOpAddChild, types.NodeOpMove:
+		parent, _, _ = base.FindNode(op.ParentID)
+	case types.NodeOpReorder:
+		_, parent, _ = base.FindNode(op.NodeID)
+	default:
+		return nil, false
+	}
+	if parent == nil {
+		// 基线树中不存在时无法换算，保持原位置参数
+		return nil, false
+	}
+
+	siblings := siblingUIDs(parent.Children, op.NodeID)
+	index := min(max(*op.Index, 0), len(siblings))
+	return siblings[:index], true
+}
+
+// resolveAnchorIndex 在最新树中按锚点还原位置：排在最后一个仍在原父节点下的锚点之后，锚点都不在时排在最前
+// 返回 false 表示操作已失效（如 reorder 的节点已被并发移动到其他父节点下）
+func resolveAnchorIndex(current *entity.MindMapData, op *types.NodeOperation, anchors []string) (int, bool) {
+	var parent *entity.MindMapData
+	switch op.Op {
+	case types.NodeOpAddChild, types.NodeOpMove:
+		parent, _, _ = current.FindNode(op.ParentID)
+	case types.NodeOpReorder:
+		_, parent, _ = current.FindNode(op.NodeID)
+	}
+	if parent == nil {
+		return 0, false
+	}
+
+	siblings := siblingUIDs(parent.Children, op.NodeID)
+	position := make(map[string]int, len(siblings))
+	for i, uid := range siblings {
+		position[uid] = i
+	}
+	for i := len(anchors) - 1; i >= 0; i-- {
+		if pos, ok := position[anchors[i]]; ok {
+			return pos + 1, true
+		}
+	}
+	return 0, true
+}
+
+// siblingUIDs 返回子节点UID列表，排除指定节点
+func siblingUIDs(children []entity.MindMapData, exclude string) []string {
+	uids := make([]string, 0, len(children))
+	for _, child := range children {
+		if exclude != "" && child.Data.UID == exclude {
+			continue
+		}
+		uids = append(uids, child.Data.UID)
+	}
+	return uids
+}
+
+// prepareNodeOperation 复制操作并为 add_child 的子树预先分配UID，保证广播出去的操作可在各客户端确定性重放
+func prepareNodeOperation(op types.NodeOperation) (types.NodeOperation, error) {
+	if op.Op != types.NodeOpAddChild {
+		return op, nil
+	}
+
+	var child entity.MindMapData
+	if op.Node != nil {
+		child = op.Node.Clone()
+	}
+	if op.Text != nil {
+		child.Data.Text = *op.Text
+	}
+	if _, err := child.EnsureUIDs(); err != nil {
+		return op, err
+	}
+	op.Node = &child
+	op.Text = nil
+	return op, nil
+}
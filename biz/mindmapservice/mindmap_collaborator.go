@@ -0,0 +1,238 @@
+package mindmapservice
+
+import (
+	"context"
+	"errors"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+)
+
+var (
+	ErrCollaboratorUserNotFound = errors.New("被邀请的用户不存在")
+	ErrCollaboratorNotFound     = errors.New("协作者不存在")
+)
+
+// getMindMapWithRole 校验当前用户对导图的权限并返回导图及用户角色
+// 无任何权限时返回 ErrMindMapNotFound，避免暴露导图是否存在；权限不足时返回 ErrPermissionDenied
+func (s *MindMapServiceImpl) getMindMapWithRole(ctx context.Context, mapID, requiredRole string) (*entity.MindMap, string, error) {
+	// 从JWT token上下文中获取用户信息
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, "", ErrPermissionDenied
+	}
+
+	// 参数校验
+	if mapID == "" {
+		zlog.CtxErrorf(ctx, "mapID is required")
+		return nil, "", ErrInvalidParams
+	}
+
+	role, err := s.mindMapRepo.GetMindMapRole(ctx, mapID, user.UserID)
+	if err != nil {
+		if errors.Is(err, repo.ErrMindMapNotFound) {
+			zlog.CtxWarnf(ctx, "mindmap not found, mapID: %s", mapID)
+			return nil, "", ErrMindMapNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to get mindmap role: %v", err)
+		return nil, "", ErrInternalError
+	}
+	if role == "" {
+		zlog.CtxWarnf(ctx, "user has no access to mindmap, mapID: %s, userID: %s", mapID, user.UserID)
+		return nil, "", ErrMindMapNotFound
+	}
+	if !entity.MindMapRoleAllows(role, requiredRole) {
+		zlog.CtxWarnf(ctx, "mindmap permission denied, mapID: %s, userID: %s, role: %s, required: %s",
+			mapID, user.UserID, role, requiredRole)
+		return nil, role, ErrPermissionDenied
+	}
+
+	mindMap, err := s.mindMapRepo.GetMindMap(ctx, repo.NewMindMapQueryByMapID(mapID))
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to get mindmap: %v", err)
+		return nil, "", ErrInternalError
+	}
+	if mindMap == nil {
+		return nil, "", ErrMindMapNotFound
+	}
+
+	return mindMap, role, nil
+}
+
+// ListMindMapCollaborators 获取导图协作者列表，所有者排在首位（有查看权限即可）
+func (s *MindMapServiceImpl) ListMindMapCollaborators(ctx context.Context, mapID string) ([]*entity.MindMapCollaborator, error) {
+	mindMap, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators, err := s.mindMapRepo.ListMindMapCollaborators(ctx, mapID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list mindmap collaborators: %v", err)
+		return nil, ErrInternalError
+	}
+
+	owner := &entity.MindMapCollaborator{
+		MapID:     mindMap.MapID,
+		UserID:    mindMap.UserID,
+		Role:      entity.MindMapRoleOwner,
+		CreatedAt: mindMap.CreatedAt,
+		UpdatedAt: mindMap.CreatedAt,
+	}
+	result := append([]*entity.MindMapCollaborator{owner}, collaborators...)
+	for _, collaborator := range result {
+		s.fillCollaboratorProfile(ctx, collaborator)
+	}
+	return result, nil
+}
+
+// AddMindMapCollaborator 通过邮箱或手机号邀请已注册用户成为协作者，已是协作者时更新其角色（仅所有者可操作）
+func (s *MindMapServiceImpl) AddMindMapCollaborator(ctx context.Context, mapID string, req *types.AddMindMapCollaboratorParams) (collaborator *entity.MindMapCollaborator, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.add_mindmap_collaborator", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, collaborator, err)
+	}()
+
+	if req == nil || req.Account == "" || !entity.IsValidCollaboratorRole(req.Role) {
+		zlog.CtxErrorf(ctx, "invalid add collaborator params")
+		return nil, ErrInvalidParams
+	}
+
+	mindMap, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	// 复用用户仓储的联系方式查询
+	var query repo.UserQuery
+	switch req.AccountType {
+	case types.AccountTypeEmail:
+		query = repo.NewUserQueryByEmail(req.Account)
+	case types.AccountTypePhone:
+		query = repo.NewUserQueryByPhone(req.Account)
+	default:
+		zlog.CtxErrorf(ctx, "unsupported collaborator account type: %s", req.AccountType)
+		return nil, ErrInvalidParams
+	}
+	invitee, err := s.userRepo.GetUser(ctx, query)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to get invitee: %v", err)
+		return nil, ErrInternalError
+	}
+	if invitee == nil {
+		return nil, ErrCollaboratorUserNotFound
+	}
+	if invitee.UserID == mindMap.UserID {
+		zlog.CtxWarnf(ctx, "cannot invite owner as collaborator, mapID: %s", mapID)
+		return nil, ErrInvalidParams
+	}
+
+	user, _ := entity.GetUser(ctx)
+	collaborator = &entity.MindMapCollaborator{
+		MapID:     mapID,
+		UserID:    invitee.UserID,
+		Role:      req.Role,
+		InvitedBy: user.UserID,
+		UserName:  invitee.UserName,
+		Avatar:    invitee.Avatar,
+	}
+	if err = s.mindMapRepo.UpsertMindMapCollaborator(ctx, collaborator); err != nil {
+		zlog.CtxErrorf(ctx, "failed to upsert mindmap collaborator: %v", err)
+		return nil, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap collaborator added, mapID: %s, userID: %s, role: %s", mapID, invitee.UserID, req.Role)
+	return collaborator, nil
+}
+
+// UpdateMindMapCollaboratorRole 修改协作者角色（仅所有者可操作）
+func (s *MindMapServiceImpl) UpdateMindMapCollaboratorRole(ctx context.Context, mapID, userID, role string) error {
+	if userID == "" || !entity.IsValidCollaboratorRole(role) {
+		zlog.CtxErrorf(ctx, "invalid update collaborator params, userID: %s, role: %s", userID, role)
+		return ErrInvalidParams
+	}
+
+	if _, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleOwner); err != nil {
+		return err
+	}
+
+	current, err := s.mindMapRepo.GetMindMapRole(ctx, mapID, userID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to get collaborator role: %v", err)
+		return ErrInternalError
+	}
+	if current == "" || current == entity.MindMapRoleOwner {
+		return ErrCollaboratorNotFound
+	}
+
+	user, _ := entity.GetUser(ctx)
+	if err := s.mindMapRepo.UpsertMindMapCollaborator(ctx, &entity.MindMapCollaborator{
+		MapID:     mapID,
+		UserID:    userID,
+		Role:      role,
+		InvitedBy: user.UserID,
+	}); err != nil {
+		zlog.CtxErrorf(ctx, "failed to update mindmap collaborator: %v", err)
+		return ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap collaborator role updated, mapID: %s, userID: %s, role: %s", mapID, userID, role)
+	return nil
+}
+
+// RemoveMindMapCollaborator 移除协作者：所有者可移除任意协作者，协作者可主动退出
+func (s *MindMapServiceImpl) RemoveMindMapCollaborator(ctx context.Context, mapID, userID string) error {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return ErrPermissionDenied
+	}
+	if userID == "" {
+		zlog.CtxErrorf(ctx, "collaborator userID is required")
+		return ErrInvalidParams
+	}
+
+	requiredRole := entity.MindMapRoleOwner
+	if userID == user.UserID {
+		requiredRole = entity.MindMapRoleViewer
+	}
+	_, role, err := s.getMindMapWithRole(ctx, mapID, requiredRole)
+	if err != nil {
+		return err
+	}
+	if userID == user.UserID && role == entity.MindMapRoleOwner {
+		// 所有者不能退出自己的导图
+		return ErrInvalidParams
+	}
+
+	if err := s.mindMapRepo.RemoveMindMapCollaborator(ctx, mapID, userID); err != nil {
+		if errors.Is(err, repo.ErrMindMapCollaboratorNotFound) {
+			return ErrCollaboratorNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to remove mindmap collaborator: %v", err)
+		return ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap collaborator removed, mapID: %s, userID: %s, operator: %s", mapID, userID, user.UserID)
+	return nil
+}
+
+// fillCollaboratorProfile 填充协作者的展示信息，查询失败时仅记录日志
+func (s *MindMapServiceImpl) fillCollaboratorProfile(ctx context.Context, collaborator *entity.MindMapCollaborator) {
+	user, err := s.userRepo.GetUser(ctx, repo.NewUserQueryByID(collaborator.UserID))
+	if err != nil {
+		zlog.CtxWarnf(ctx, "failed to get collaborator profile, userID: %s, err: %v", collaborator.UserID, err)
+		return
+	}
+	if user == nil {
+		return
+	}
+	collaborator.UserName = user.UserName
+	collaborator.Avatar = user.Avatar
+}
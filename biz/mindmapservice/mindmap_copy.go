@@ -0,0 +1,220 @@
+package mindmapservice
+
+import (
+	"context"
+	"strings"
+
+	"forge/biz/entity"
+	"forge/biz/mindmapservice/layout"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+)
+
+// duplicateTitleSuffix 复制导图时默认标题的后缀
+const duplicateTitleSuffix = " 副本"
+
+// DuplicateMindMap 复制导图（全部节点生成新UID），可查看即可复制，副本归当前用户所有
+func (s *MindMapServiceImpl) DuplicateMindMap(ctx context.Context, mapID string, req *types.DuplicateMindMapParams) (mindMap *entity.MindMap, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.duplicate_mindmap", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, mindMap, err)
+	}()
+
+	source, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	data := source.Data.Clone()
+	if _, err := data.RegenerateUIDs(); err != nil {
+		zlog.CtxErrorf(ctx, "failed to regenerate node uids: %v", err)
+		return nil, ErrInternalError
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = truncateUTF8(source.Title, 100-len(duplicateTitleSuffix)) + duplicateTitleSuffix
+	}
+
+	// 复制自己的导图时放在同一文件夹，他人的文件夹对当前用户不可见
+	user, _ := entity.GetUser(ctx)
+	folderID := ""
+	if source.UserID == user.UserID {
+		folderID = source.FolderID
+	}
+
+	mindMap, err = s.CreateMindMap(ctx, &types.CreateMindMapParams{
+		Title:    title,
+		Desc:     source.Desc,
+		Layout:   layout.OrDefault(source.Layout),
+		Data:     data,
+		FolderID: folderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zlog.CtxInfof(ctx, "mindmap duplicated successfully, sourceMapID: %s, mapID: %s", mapID, mindMap.MapID)
+	return mindMap, nil
+}
+
+// ExtractMindMapSubtree 将节点及其子树提取为新导图，可选在原导图中以指向新导图的链接节点替换
+func (s *MindMapServiceImpl) ExtractMindMapSubtree(ctx context.Context, mapID string, req *types.ExtractMindMapSubtreeParams) (result *types.ExtractMindMapSubtreeResult, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.extract_mindmap_subtree", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, result, err)
+	}()
+
+	if req.NodeID == "" {
+		zlog.CtxErrorf(ctx, "nodeID is required")
+		return nil, ErrInvalidParams
+	}
+
+	// 替换原导图中的节点需要编辑权限，仅提取只需查看权限
+	requiredRole := entity.MindMapRoleViewer
+	if req.ReplaceWithLink {
+		requiredRole = entity.MindMapRoleEditor
+	}
+	source, _, err := s.getMindMapWithRole(ctx, mapID, requiredRole)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != source.Version {
+		zlog.CtxWarnf(ctx, "mindmap version conflict, mapID: %s, expected: %d, current: %d",
+			mapID, *req.ExpectedVersion, source.Version)
+		return nil, ErrVersionConflict
+	}
+
+	node, parent, index := source.Data.FindNode(req.NodeID)
+	if node == nil {
+		zlog.CtxWarnf(ctx, "node not found, mapID: %s, nodeID: %s", mapID, req.NodeID)
+		return nil, ErrNodeNotFound
+	}
+	if parent == nil && req.ReplaceWithLink {
+		zlog.CtxWarnf(ctx, "cannot replace root node with link, mapID: %s", mapID)
+		return nil, ErrInvalidNodeOperation
+	}
+	parentID := ""
+	if parent != nil {
+		parentID = parent.Data.UID
+	}
+
+	subtree := node.Clone()
+	if _, err := subtree.RegenerateUIDs(); err != nil {
+		zlog.CtxErrorf(ctx, "failed to regenerate node uids: %v", err)
+		return nil, ErrInternalError
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = truncateUTF8(strings.TrimSpace(node.Data.Text), 100)
+	}
+
+	extracted, err := s.CreateMindMap(ctx, &types.CreateMindMapParams{
+		Title:  title,
+		Desc:   source.Desc,
+		Layout: layout.OrDefault(source.Layout),
+		Data:   subtree,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result = &types.ExtractMindMapSubtreeResult{MindMap: extracted}
+	if !req.ReplaceWithLink {
+		zlog.CtxInfof(ctx, "mindmap subtree extracted, sourceMapID: %s, nodeID: %s, mapID: %s", mapID, req.NodeID, extracted.MapID)
+		return result, nil
+	}
+
+	// 链接节点保留原节点的文本与样式，子树由新导图承载
+	linkNode := entity.MindMapData{Data: node.Data}
+	linkNode.Data.UID = ""
+	linkNode.Data.IsActive = false
+	linkNode.Data.Collapsed = false
+	linkNode.Data.Hyperlink = entity.MindMapLink(extracted.MapID)
+	linkNode.Data.HyperlinkTitle = extracted.Title
+
+	// 以读取到的版本为基线原子执行“删除子树 + 插入链接节点”，期间原导图被修改则整体失败
+	newVersion, addedNodeIDs, err := s.PatchMindMap(ctx, mapID, &types.PatchMindMapParams{
+		Operations: []types.NodeOperation{
+			{Op: types.NodeOpDelete, NodeID: req.NodeID},
+			{Op: types.NodeOpAddChild, ParentID: parentID, Index: &index, Node: &linkNode},
+		},
+		ExpectedVersion: &source.Version,
+	})
+	if err != nil {
+		// 回滚：清除已创建的新导图，避免残留不完整的提取结果
+		s.discardMindMap(ctx, extracted.MapID)
+		return nil, err
+	}
+
+	result.SourceVersion = newVersion
+	if len(addedNodeIDs) > 0 {
+		result.LinkNodeID = addedNodeIDs[0]
+	}
+	zlog.CtxInfof(ctx, "mindmap subtree extracted and replaced with link, sourceMapID: %s, nodeID: %s, mapID: %s, version: %d",
+		mapID, req.NodeID, extracted.MapID, newVersion)
+	return result, nil
+}
+
+// GraftMindMap 将另一导图整体作为子树插入到当前导图的指定节点下（插入的节点均生成新UID）
+func (s *MindMapServiceImpl) GraftMindMap(ctx context.Context, mapID string, req *types.GraftMindMapParams) (newVersion int64, rootNodeID string, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.graft_mindmap", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rootNodeID, err)
+	}()
+
+	if req.SourceMapID == "" || req.ParentNodeID == "" {
+		zlog.CtxErrorf(ctx, "sourceMapID and parentNodeID are required")
+		return 0, "", ErrInvalidParams
+	}
+
+	source, _, err := s.getMindMapWithRole(ctx, req.SourceMapID, entity.MindMapRoleViewer)
+	if err != nil {
+		return 0, "", err
+	}
+
+	subtree := source.Data.Clone()
+	if _, err := subtree.RegenerateUIDs(); err != nil {
+		zlog.CtxErrorf(ctx, "failed to regenerate node uids: %v", err)
+		return 0, "", ErrInternalError
+	}
+
+	// 编辑权限校验、乐观锁及协同广播均由增量更新完成
+	newVersion, addedNodeIDs, err := s.PatchMindMap(ctx, mapID, &types.PatchMindMapParams{
+		Operations: []types.NodeOperation{
+			{Op: types.NodeOpAddChild, ParentID: req.ParentNodeID, Index: req.Index, Node: &subtree},
+		},
+		ExpectedVersion: req.ExpectedVersion,
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	if len(addedNodeIDs) > 0 {
+		rootNodeID = addedNodeIDs[0]
+	}
+
+	zlog.CtxInfof(ctx, "mindmap grafted successfully, mapID: %s, sourceMapID: %s, nodes: %d, version: %d",
+		mapID, req.SourceMapID, subtree.CountNodes(), newVersion)
+	return newVersion, rootNodeID, nil
+}
+
+// discardMindMap 彻底删除刚创建的导图（用于失败回滚，尽力而为）
+func (s *MindMapServiceImpl) discardMindMap(ctx context.Context, mapID string) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		return
+	}
+	if err := s.mindMapRepo.DeleteMindMap(ctx, mapID, user.UserID); err != nil {
+		zlog.CtxErrorf(ctx, "failed to discard mindmap %s: %v", mapID, err)
+		return
+	}
+	if _, err := s.mindMapRepo.PurgeMindMaps(ctx, []string{mapID}); err != nil {
+		zlog.CtxErrorf(ctx, "failed to purge discarded mindmap %s: %v", mapID, err)
+		return
+	}
+	s.deleteMindMapThumbnails(ctx, []string{mapID})
+}
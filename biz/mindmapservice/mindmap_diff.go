@@ -0,0 +1,100 @@
+package mindmapservice
+
+import (
+	"context"
+	"errors"
+
+	"forge/biz/entity"
+	"forge/biz/mindmapservice/differ"
+	"forge/biz/repo"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+)
+
+// diffMaxNodes 与任意数据比较时允许的最大节点数
+const diffMaxNodes = 10000
+
+// DiffMindMapRevisions 比较同一导图两个版本之间的结构差异，版本号为0表示当前版本
+func (s *MindMapServiceImpl) DiffMindMapRevisions(ctx context.Context, mapID string, fromVersion, toVersion int64) (diff *entity.MindMapDiff, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.diff_mindmap_revisions", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "from": fromVersion, "to": toVersion}, diff, err)
+	}()
+
+	if fromVersion < 0 || toVersion < 0 {
+		zlog.CtxErrorf(ctx, "invalid diff versions, from: %d, to: %d", fromVersion, toVersion)
+		return nil, ErrInvalidParams
+	}
+
+	// 复用GetMindMap完成登录态与查看权限校验
+	mindMap, err := s.GetMindMap(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromData, fromVersion, err := s.getMindMapVersionData(ctx, mindMap, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	toData, toVersion, err := s.getMindMapVersionData(ctx, mindMap, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	diff = differ.Compare(&fromData, &toData)
+	diff.FromVersion = fromVersion
+	diff.ToVersion = toVersion
+	return diff, nil
+}
+
+// DiffMindMapWithData 比较导图指定版本（为0表示当前版本）与任意节点树之间的结构差异
+func (s *MindMapServiceImpl) DiffMindMapWithData(ctx context.Context, mapID string, fromVersion int64, data *entity.MindMapData) (diff *entity.MindMapDiff, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.diff_mindmap_with_data", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "from": fromVersion}, diff, err)
+	}()
+
+	if data == nil || fromVersion < 0 {
+		zlog.CtxErrorf(ctx, "data is required and version must not be negative")
+		return nil, ErrInvalidParams
+	}
+	if count := data.CountNodes(); count > diffMaxNodes {
+		zlog.CtxErrorf(ctx, "too many nodes to diff: %d", count)
+		return nil, ErrInvalidParams
+	}
+
+	// 复用GetMindMap完成登录态与查看权限校验
+	mindMap, err := s.GetMindMap(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromData, fromVersion, err := s.getMindMapVersionData(ctx, mindMap, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	diff = differ.Compare(&fromData, data)
+	diff.FromVersion = fromVersion
+	return diff, nil
+}
+
+// getMindMapVersionData 获取导图指定版本的节点树及实际版本号，版本号为0或等于当前版本时直接使用当前数据
+func (s *MindMapServiceImpl) getMindMapVersionData(ctx context.Context, mindMap *entity.MindMap, version int64) (entity.MindMapData, int64, error) {
+	if version == 0 || version == mindMap.Version {
+		return mindMap.Data, mindMap.Version, nil
+	}
+
+	revision, err := s.mindMapRepo.GetMindMapRevision(ctx, mindMap.MapID, version)
+	if err != nil {
+		if errors.Is(err, repo.ErrMindMapRevisionNotFound) {
+			return entity.MindMapData{}, 0, ErrRevisionNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to get mindmap revision: %v", err)
+		return entity.MindMapData{}, 0, ErrInternalError
+	}
+	return revision.Data, revision.Version, nil
+}
@@ -0,0 +1,74 @@
+package mindmapservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"forge/biz/mindmapservice/exporter"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+)
+
+var (
+	ErrUnsupportedExportFormat = errors.New("不支持的导出格式")
+	ErrExportFontNotConfigured = errors.New("导出所需的字体未配置")
+)
+
+// ExportMindMap 将思维导图导出为指定格式的文件
+func (s *MindMapServiceImpl) ExportMindMap(ctx context.Context, mapID string, format string) (file *types.ExportedFile, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.export_mindmap", constant.LoopSpanType_Function)
+	defer func() {
+		var size int
+		if file != nil {
+			size = len(file.Content)
+		}
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "format": format}, map[string]interface{}{"size": size}, err)
+	}()
+
+	exp, err := exporter.GetRegistry().GetExporter(format)
+	if err != nil {
+		zlog.CtxWarnf(ctx, "unsupported export format: %s", format)
+		return nil, ErrUnsupportedExportFormat
+	}
+
+	// 获取思维导图（包含权限校验）
+	mindMap, err := s.GetMindMap(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := exp.Export(mindMap)
+	if errors.Is(err, exporter.ErrPDFFontNotConfigured) {
+		zlog.CtxErrorf(ctx, "pdf font is not configured, mapID: %s", mapID)
+		return nil, ErrExportFontNotConfigured
+	}
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to export mindmap, mapID: %s, format: %s, err: %v", mapID, format, err)
+		return nil, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap exported successfully, mapID: %s, format: %s, size: %d", mapID, format, len(content))
+	return &types.ExportedFile{
+		FileName:    exportFileName(mindMap.Title, mapID) + exp.FileExt(),
+		ContentType: exp.ContentType(),
+		Content:     content,
+	}, nil
+}
+
+// exportFileName 以标题作为文件名，去掉文件系统不允许的字符
+func exportFileName(title, mapID string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`\/:*?"<>|`, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(title))
+	if name == "" {
+		return mapID
+	}
+	return name
+}
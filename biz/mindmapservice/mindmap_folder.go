@@ -0,0 +1,554 @@
+package mindmapservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/pkg/log/zlog"
+	"forge/util"
+)
+
+var (
+	ErrFolderNotFound      = errors.New("文件夹不存在")
+	ErrFolderNameExists    = errors.New("同一目录下已存在同名文件夹")
+	ErrFolderInvalidParent = errors.New("不能将文件夹移动到自身或其子文件夹下")
+	ErrFolderTooDeep       = errors.New("文件夹层级过深")
+	ErrTagNotFound         = errors.New("标签不存在")
+	ErrTagNameExists       = errors.New("标签名称已存在")
+)
+
+const (
+	folderNameMaxRunes = 50
+	tagNameMaxRunes    = 30
+)
+
+// CreateMindMapFolder 创建文件夹
+func (s *MindMapServiceImpl) CreateMindMapFolder(ctx context.Context, req *types.CreateMindMapFolderParams) (*entity.MindMapFolder, error) {
+	user, folders, err := s.loadMindMapFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name, ok := normalizeName(req.Name, folderNameMaxRunes)
+	if !ok {
+		zlog.CtxErrorf(ctx, "invalid folder name: %q", req.Name)
+		return nil, ErrInvalidParams
+	}
+	if req.ParentID != "" {
+		if _, ok := folders[req.ParentID]; !ok {
+			zlog.CtxWarnf(ctx, "parent folder not found: %s", req.ParentID)
+			return nil, ErrFolderNotFound
+		}
+		if folderDepth(folders, req.ParentID)+1 > entity.MindMapFolderMaxDepth {
+			return nil, ErrFolderTooDeep
+		}
+	}
+	if folderNameTaken(folders, req.ParentID, name, "") {
+		return nil, ErrFolderNameExists
+	}
+
+	folderID, err := util.GenerateStringID()
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to generate folder id: %v", err)
+		return nil, ErrInternalError
+	}
+	folder := &entity.MindMapFolder{
+		FolderID: folderID,
+		UserID:   user.UserID,
+		ParentID: req.ParentID,
+		Name:     name,
+	}
+	if err := s.mindMapRepo.CreateMindMapFolder(ctx, folder); err != nil {
+		zlog.CtxErrorf(ctx, "failed to create mindmap folder: %v", err)
+		return nil, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap folder created successfully, folderID: %s, userID: %s", folderID, user.UserID)
+	return folder, nil
+}
+
+// ListMindMapFolders 获取当前用户的全部文件夹（扁平列表，通过 ParentID 组装树结构）
+func (s *MindMapServiceImpl) ListMindMapFolders(ctx context.Context) ([]*entity.MindMapFolder, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, ErrPermissionDenied
+	}
+
+	folders, err := s.mindMapRepo.ListMindMapFolders(ctx, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list mindmap folders: %v", err)
+		return nil, ErrInternalError
+	}
+	return folders, nil
+}
+
+// UpdateMindMapFolder 重命名文件夹或将其移动到其他文件夹下
+func (s *MindMapServiceImpl) UpdateMindMapFolder(ctx context.Context, folderID string, req *types.UpdateMindMapFolderParams) (*entity.MindMapFolder, error) {
+	_, folders, err := s.loadMindMapFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, ok := folders[folderID]
+	if !ok {
+		zlog.CtxWarnf(ctx, "folder not found: %s", folderID)
+		return nil, ErrFolderNotFound
+	}
+	folder := *current
+
+	if req.Name != nil {
+		name, ok := normalizeName(*req.Name, folderNameMaxRunes)
+		if !ok {
+			zlog.CtxErrorf(ctx, "invalid folder name: %q", *req.Name)
+			return nil, ErrInvalidParams
+		}
+		folder.Name = name
+	}
+	if req.ParentID != nil && *req.ParentID != folder.ParentID {
+		parentID := *req.ParentID
+		if parentID != "" {
+			if _, ok := folders[parentID]; !ok {
+				zlog.CtxWarnf(ctx, "parent folder not found: %s", parentID)
+				return nil, ErrFolderNotFound
+			}
+			// 目标文件夹不能是自身或子孙文件夹，否则会形成环
+			if _, inSubtree := collectFolderSubtree(folders, folderID)[parentID]; inSubtree {
+				return nil, ErrFolderInvalidParent
+			}
+			if folderDepth(folders, parentID)+folderHeight(folders, folderID) > entity.MindMapFolderMaxDepth {
+				return nil, ErrFolderTooDeep
+			}
+		}
+		folder.ParentID = parentID
+	}
+	if folderNameTaken(folders, folder.ParentID, folder.Name, folderID) {
+		return nil, ErrFolderNameExists
+	}
+
+	if err := s.mindMapRepo.UpdateMindMapFolder(ctx, &folder); err != nil {
+		if errors.Is(err, repo.ErrMindMapFolderNotFound) {
+			return nil, ErrFolderNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to update mindmap folder: %v", err)
+		return nil, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap folder updated successfully, folderID: %s", folderID)
+	return &folder, nil
+}
+
+// DeleteMindMapFolder 删除文件夹及其全部子文件夹，其中的导图移出为未归档
+func (s *MindMapServiceImpl) DeleteMindMapFolder(ctx context.Context, folderID string) (int, error) {
+	user, folders, err := s.loadMindMapFolders(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := folders[folderID]; !ok {
+		zlog.CtxWarnf(ctx, "folder not found: %s", folderID)
+		return 0, ErrFolderNotFound
+	}
+
+	subtree := collectFolderSubtree(folders, folderID)
+	folderIDs := make([]string, 0, len(subtree))
+	for id := range subtree {
+		folderIDs = append(folderIDs, id)
+	}
+
+	movedCount, err := s.mindMapRepo.DeleteMindMapFolders(ctx, folderIDs, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to delete mindmap folders: %v", err)
+		return 0, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap folder deleted successfully, folderID: %s, folders: %d, moved mindmaps: %d",
+		folderID, len(folderIDs), movedCount)
+	return movedCount, nil
+}
+
+// MoveMindMapsToFolder 批量移动导图到文件夹（用户只能移动自己的导图），folderID 为空表示移出文件夹
+func (s *MindMapServiceImpl) MoveMindMapsToFolder(ctx context.Context, mapIDs []string, folderID string) (movedCount int, failedMapIDs []string, err error) {
+	user, validMapIDs, failedMapIDs, err := s.partitionOwnedMindMapIDs(ctx, mapIDs)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if folderID != "" {
+		_, folders, err := s.loadMindMapFolders(ctx)
+		if err != nil {
+			return 0, nil, err
+		}
+		if _, ok := folders[folderID]; !ok {
+			zlog.CtxWarnf(ctx, "folder not found: %s", folderID)
+			return 0, nil, ErrFolderNotFound
+		}
+	}
+	if len(validMapIDs) == 0 {
+		return 0, failedMapIDs, nil
+	}
+
+	movedCount, err = s.mindMapRepo.MoveMindMapsToFolder(ctx, validMapIDs, user.UserID, folderID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to move mindmaps to folder: %v", err)
+		return 0, failedMapIDs, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "move mindmaps completed, userID: %s, folderID: %s, moved: %d, failed: %d",
+		user.UserID, folderID, movedCount, len(failedMapIDs))
+	return movedCount, failedMapIDs, nil
+}
+
+// CreateMindMapTag 创建标签
+func (s *MindMapServiceImpl) CreateMindMapTag(ctx context.Context, req *types.CreateMindMapTagParams) (*entity.MindMapTag, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, ErrPermissionDenied
+	}
+
+	name, ok := normalizeName(req.Name, tagNameMaxRunes)
+	if !ok {
+		zlog.CtxErrorf(ctx, "invalid tag name: %q", req.Name)
+		return nil, ErrInvalidParams
+	}
+
+	tagID, err := util.GenerateStringID()
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to generate tag id: %v", err)
+		return nil, ErrInternalError
+	}
+	tag := &entity.MindMapTag{
+		TagID:  tagID,
+		UserID: user.UserID,
+		Name:   name,
+		Color:  req.Color,
+	}
+	if err := s.mindMapRepo.CreateMindMapTag(ctx, tag); err != nil {
+		if errors.Is(err, repo.ErrMindMapTagNameExists) {
+			return nil, ErrTagNameExists
+		}
+		zlog.CtxErrorf(ctx, "failed to create mindmap tag: %v", err)
+		return nil, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap tag created successfully, tagID: %s, userID: %s", tagID, user.UserID)
+	return tag, nil
+}
+
+// ListMindMapTags 获取当前用户的全部标签
+func (s *MindMapServiceImpl) ListMindMapTags(ctx context.Context) ([]*entity.MindMapTag, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, ErrPermissionDenied
+	}
+
+	tags, err := s.mindMapRepo.ListMindMapTags(ctx, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list mindmap tags: %v", err)
+		return nil, ErrInternalError
+	}
+	return tags, nil
+}
+
+// UpdateMindMapTag 修改标签名称或颜色
+func (s *MindMapServiceImpl) UpdateMindMapTag(ctx context.Context, tagID string, req *types.UpdateMindMapTagParams) (*entity.MindMapTag, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, ErrPermissionDenied
+	}
+
+	tags, err := s.mindMapRepo.BatchGetMindMapTagsByIDs(ctx, []string{tagID}, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to get mindmap tag: %v", err)
+		return nil, ErrInternalError
+	}
+	if len(tags) == 0 {
+		zlog.CtxWarnf(ctx, "tag not found: %s", tagID)
+		return nil, ErrTagNotFound
+	}
+	tag := tags[0]
+
+	if req.Name != nil {
+		name, ok := normalizeName(*req.Name, tagNameMaxRunes)
+		if !ok {
+			zlog.CtxErrorf(ctx, "invalid tag name: %q", *req.Name)
+			return nil, ErrInvalidParams
+		}
+		tag.Name = name
+	}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+
+	if err := s.mindMapRepo.UpdateMindMapTag(ctx, tag); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrMindMapTagNotFound):
+			return nil, ErrTagNotFound
+		case errors.Is(err, repo.ErrMindMapTagNameExists):
+			return nil, ErrTagNameExists
+		}
+		zlog.CtxErrorf(ctx, "failed to update mindmap tag: %v", err)
+		return nil, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap tag updated successfully, tagID: %s", tagID)
+	return tag, nil
+}
+
+// DeleteMindMapTag 删除标签，同时移除其与导图的关联
+func (s *MindMapServiceImpl) DeleteMindMapTag(ctx context.Context, tagID string) error {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return ErrPermissionDenied
+	}
+
+	if err := s.mindMapRepo.DeleteMindMapTag(ctx, tagID, user.UserID); err != nil {
+		if errors.Is(err, repo.ErrMindMapTagNotFound) {
+			return ErrTagNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to delete mindmap tag: %v", err)
+		return ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap tag deleted successfully, tagID: %s", tagID)
+	return nil
+}
+
+// TagMindMaps 为导图批量添加标签（用户只能标记自己的导图）
+func (s *MindMapServiceImpl) TagMindMaps(ctx context.Context, mapIDs, tagIDs []string) (int, []string, error) {
+	validMapIDs, validTagIDs, failedMapIDs, err := s.prepareMindMapTagging(ctx, mapIDs, tagIDs)
+	if err != nil || len(validMapIDs) == 0 {
+		return 0, failedMapIDs, err
+	}
+
+	addedCount, err := s.mindMapRepo.AddMindMapTags(ctx, validMapIDs, validTagIDs)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to add mindmap tags: %v", err)
+		return 0, failedMapIDs, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "tag mindmaps completed, mindmaps: %d, tags: %d, added: %d, failed: %d",
+		len(validMapIDs), len(validTagIDs), addedCount, len(failedMapIDs))
+	return addedCount, failedMapIDs, nil
+}
+
+// UntagMindMaps 批量移除导图的标签（用户只能修改自己的导图）
+func (s *MindMapServiceImpl) UntagMindMaps(ctx context.Context, mapIDs, tagIDs []string) (int, []string, error) {
+	validMapIDs, validTagIDs, failedMapIDs, err := s.prepareMindMapTagging(ctx, mapIDs, tagIDs)
+	if err != nil || len(validMapIDs) == 0 {
+		return 0, failedMapIDs, err
+	}
+
+	removedCount, err := s.mindMapRepo.RemoveMindMapTags(ctx, validMapIDs, validTagIDs)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to remove mindmap tags: %v", err)
+		return 0, failedMapIDs, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "untag mindmaps completed, mindmaps: %d, tags: %d, removed: %d, failed: %d",
+		len(validMapIDs), len(validTagIDs), removedCount, len(failedMapIDs))
+	return removedCount, failedMapIDs, nil
+}
+
+// prepareMindMapTagging 校验批量标记的导图与标签：标签必须全部属于当前用户，不属于当前用户的导图计入失败列表
+func (s *MindMapServiceImpl) prepareMindMapTagging(ctx context.Context, mapIDs, tagIDs []string) (validMapIDs, validTagIDs, failedMapIDs []string, err error) {
+	if err := s.checkMindMapTagsOwned(ctx, tagIDs); err != nil {
+		return nil, nil, nil, err
+	}
+	_, validMapIDs, failedMapIDs, err = s.partitionOwnedMindMapIDs(ctx, mapIDs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return validMapIDs, dedupeIDs(tagIDs), failedMapIDs, nil
+}
+
+// checkMindMapTagsOwned 校验标签均存在且属于当前用户
+func (s *MindMapServiceImpl) checkMindMapTagsOwned(ctx context.Context, tagIDs []string) error {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return ErrPermissionDenied
+	}
+
+	tagIDs = dedupeIDs(tagIDs)
+	if len(tagIDs) == 0 {
+		zlog.CtxErrorf(ctx, "tagIDs is required")
+		return ErrInvalidParams
+	}
+	tags, err := s.mindMapRepo.BatchGetMindMapTagsByIDs(ctx, tagIDs, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to batch get mindmap tags: %v", err)
+		return ErrInternalError
+	}
+	if len(tags) != len(tagIDs) {
+		zlog.CtxWarnf(ctx, "some tags not found, requested: %d, found: %d", len(tagIDs), len(tags))
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// partitionOwnedMindMapIDs 去重并将mapIDs划分为属于当前用户的和其余的
+func (s *MindMapServiceImpl) partitionOwnedMindMapIDs(ctx context.Context, mapIDs []string) (user *entity.User, validMapIDs, failedMapIDs []string, err error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, nil, nil, ErrPermissionDenied
+	}
+
+	dedupedMapIDs := dedupeIDs(mapIDs)
+	if len(dedupedMapIDs) == 0 {
+		zlog.CtxErrorf(ctx, "no valid mapIDs after deduplication")
+		return nil, nil, nil, ErrInvalidParams
+	}
+
+	ownedMindMaps, err := s.mindMapRepo.BatchGetMindMapsByIDs(ctx, dedupedMapIDs, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to batch get mindmaps for permission check: %v", err)
+		return nil, nil, nil, ErrInternalError
+	}
+	ownedMapIDs := make(map[string]bool, len(ownedMindMaps))
+	for _, mindMap := range ownedMindMaps {
+		ownedMapIDs[mindMap.MapID] = true
+	}
+
+	validMapIDs = make([]string, 0, len(dedupedMapIDs))
+	failedMapIDs = make([]string, 0)
+	for _, mapID := range dedupedMapIDs {
+		if ownedMapIDs[mapID] {
+			validMapIDs = append(validMapIDs, mapID)
+		} else {
+			failedMapIDs = append(failedMapIDs, mapID)
+		}
+	}
+	if len(validMapIDs) == 0 {
+		zlog.CtxWarnf(ctx, "no valid mapIDs belong to user %s", user.UserID)
+	}
+	return user, validMapIDs, failedMapIDs, nil
+}
+
+// loadMindMapFolders 获取当前用户及其全部文件夹（按ID索引）
+func (s *MindMapServiceImpl) loadMindMapFolders(ctx context.Context) (*entity.User, map[string]*entity.MindMapFolder, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, nil, ErrPermissionDenied
+	}
+
+	folders, err := s.mindMapRepo.ListMindMapFolders(ctx, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list mindmap folders: %v", err)
+		return nil, nil, ErrInternalError
+	}
+	folderByID := make(map[string]*entity.MindMapFolder, len(folders))
+	for _, folder := range folders {
+		folderByID[folder.FolderID] = folder
+	}
+	return user, folderByID, nil
+}
+
+// folderDepth 文件夹所在层数，顶层文件夹为1
+func folderDepth(folders map[string]*entity.MindMapFolder, folderID string) int {
+	depth := 0
+	// 以文件夹总数为上限，防止脏数据成环时死循环
+	for id := folderID; id != "" && depth <= len(folders); depth++ {
+		folder, ok := folders[id]
+		if !ok {
+			break
+		}
+		id = folder.ParentID
+	}
+	return depth
+}
+
+// folderHeight 以该文件夹为根的子树层数，没有子文件夹时为1
+func folderHeight(folders map[string]*entity.MindMapFolder, folderID string) int {
+	children := folderChildren(folders)
+	seen := map[string]struct{}{folderID: {}}
+	height := 0
+	for level := []string{folderID}; len(level) > 0; height++ {
+		next := make([]string, 0)
+		for _, id := range level {
+			for _, child := range children[id] {
+				if _, ok := seen[child]; ok {
+					continue
+				}
+				seen[child] = struct{}{}
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+	return height
+}
+
+// collectFolderSubtree 收集文件夹自身及其全部子孙文件夹ID
+func collectFolderSubtree(folders map[string]*entity.MindMapFolder, folderID string) map[string]struct{} {
+	children := folderChildren(folders)
+	subtree := map[string]struct{}{folderID: {}}
+	queue := []string{folderID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range children[id] {
+			if _, ok := subtree[child]; ok {
+				continue
+			}
+			subtree[child] = struct{}{}
+			queue = append(queue, child)
+		}
+	}
+	return subtree
+}
+
+// folderChildren 按父文件夹ID分组的子文件夹ID
+func folderChildren(folders map[string]*entity.MindMapFolder) map[string][]string {
+	children := make(map[string][]string, len(folders))
+	for _, folder := range folders {
+		children[folder.ParentID] = append(children[folder.ParentID], folder.FolderID)
+	}
+	return children
+}
+
+// folderNameTaken 同一父文件夹下是否已有同名文件夹（excludeID 为正在修改的文件夹自身）
+func folderNameTaken(folders map[string]*entity.MindMapFolder, parentID, name, excludeID string) bool {
+	for _, folder := range folders {
+		if folder.FolderID != excludeID && folder.ParentID == parentID && folder.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeName 去除首尾空白并校验名称长度
+func normalizeName(name string, maxRunes int) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxRunes {
+		return "", false
+	}
+	return name, true
+}
+
+// dedupeIDs 去除空值与重复值，保持原有顺序
+func dedupeIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	deduped := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		deduped = append(deduped, id)
+	}
+	return deduped
+}
@@ -0,0 +1,99 @@
+package mindmapservice
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"forge/biz/entity"
+	"forge/biz/mindmapservice/importer"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+)
+
+var (
+	ErrUnsupportedImportFormat = errors.New("不支持的导入文件格式")
+	ErrImportParseFailed       = errors.New("导入文件解析失败")
+)
+
+// defaultImportLayout 导入时未指定布局使用的默认布局
+const defaultImportLayout = "mindMap"
+
+// ImportMindMap 解析外部思维导图文件并创建为新导图（纯规则解析，不经过AI）
+func (s *MindMapServiceImpl) ImportMindMap(ctx context.Context, req *types.ImportMindMapParams) (mindMap *entity.MindMap, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.import_mindmap", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, req, mindMap, err)
+	}()
+
+	if req == nil || req.File == nil {
+		zlog.CtxErrorf(ctx, "import file is required")
+		return nil, ErrInvalidParams
+	}
+	if req.File.Size > importer.MaxImportFileSize {
+		zlog.CtxWarnf(ctx, "import file too large: %d", req.File.Size)
+		return nil, ErrInvalidParams
+	}
+
+	ext := strings.ToLower(filepath.Ext(req.File.Filename))
+	mime, err := importer.DetectMime(req.File)
+	if err != nil {
+		zlog.CtxWarnf(ctx, "failed to detect MIME type for file %s: %v", req.File.Filename, err)
+		mime = ""
+	}
+
+	// 使用注册表获取合适的导入器
+	imp := importer.GetRegistry().GetImporter(mime, ext)
+	if imp == nil {
+		zlog.CtxWarnf(ctx, "no importer found for file %s: MIME=%s, ext=%s", req.File.Filename, mime, ext)
+		return nil, ErrUnsupportedImportFormat
+	}
+
+	zlog.CtxInfof(ctx, "using importer %s for file %s", imp.Name(), req.File.Filename)
+	imported, err := imp.Parse(req.File)
+	if err != nil {
+		zlog.CtxWarnf(ctx, "failed to import %s using %s: %v", req.File.Filename, imp.Name(), err)
+		return nil, ErrImportParseFailed
+	}
+
+	// 标题优先级：请求指定 > 文件解析 > 文件名
+	title := req.Title
+	if title == "" {
+		title = imported.Title
+	}
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(req.File.Filename), filepath.Ext(req.File.Filename))
+	}
+	title = truncateUTF8(title, 100)
+	if imported.Data.Data.Text == "" {
+		imported.Data.Data.Text = title
+	}
+
+	layout := req.Layout
+	if layout == "" {
+		layout = defaultImportLayout
+	}
+
+	return s.CreateMindMap(ctx, &types.CreateMindMapParams{
+		Title:  title,
+		Desc:   req.Desc,
+		Layout: layout,
+		Data:   imported.Data,
+	})
+}
+
+// truncateUTF8 按字节截断字符串且不截断多字节字符
+func truncateUTF8(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
+	}
+	return s[:maxBytes]
+}
@@ -0,0 +1,40 @@
+package mindmapservice
+
+import (
+	"context"
+
+	"forge/biz/mindmapservice/layout"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+)
+
+// ComputeMindMapLayout 按布局计算导图全部节点的坐标、连线及画布范围，layoutType 为空时使用导图自身的布局
+func (s *MindMapServiceImpl) ComputeMindMapLayout(ctx context.Context, mapID, layoutType string) (result *layout.Result, version int64, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.compute_mindmap_layout", constant.LoopSpanType_Function)
+	defer func() {
+		var nodes int
+		if result != nil {
+			nodes = len(result.Nodes)
+		}
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "layout": layoutType}, map[string]interface{}{"nodes": nodes, "version": version}, err)
+	}()
+
+	if layoutType != "" && !layout.IsValid(layoutType) {
+		zlog.CtxWarnf(ctx, "unsupported mindmap layout: %s", layoutType)
+		return nil, 0, ErrUnsupportedLayout
+	}
+
+	// 获取思维导图（包含权限校验）
+	mindMap, err := s.GetMindMap(ctx, mapID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if layoutType == "" {
+		// 早期导图可能保存了服务端不支持的布局，排版时按逻辑结构图处理
+		layoutType = mindMap.Layout
+	}
+
+	return layout.Compute(&mindMap.Data, layoutType), mindMap.Version, nil
+}
@@ -0,0 +1,200 @@
+package mindmapservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+	"forge/util"
+)
+
+var (
+	ErrNodeLinkNotFound = errors.New("链接不存在")
+	ErrNodeLinkExists   = errors.New("该节点已存在指向同一目标的链接")
+)
+
+const (
+	nodeLinkLabelMaxRunes = 100
+	nodeLinkListLimit     = 500 // 单次查询返回的链接数量上限
+)
+
+// CreateMindMapNodeLink 在导图节点上创建指向另一导图（或其中节点）的链接，需要源导图的编辑权限及目标导图的查看权限
+func (s *MindMapServiceImpl) CreateMindMapNodeLink(ctx context.Context, mapID string, req *types.CreateMindMapNodeLinkParams) (link *entity.MindMapNodeLink, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.create_mindmap_node_link", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, link, err)
+	}()
+
+	if req.SourceNodeID == "" || req.TargetMapID == "" {
+		zlog.CtxErrorf(ctx, "sourceNodeID and targetMapID are required")
+		return nil, ErrInvalidParams
+	}
+	if req.TargetMapID == mapID && (req.TargetNodeID == "" || req.TargetNodeID == req.SourceNodeID) {
+		zlog.CtxErrorf(ctx, "node link cannot point to itself, mapID: %s, nodeID: %s", mapID, req.SourceNodeID)
+		return nil, ErrInvalidParams
+	}
+	linkType := req.Type
+	if linkType == "" {
+		linkType = entity.NodeLinkTypeReference
+	}
+	if !entity.IsValidNodeLinkType(linkType) {
+		zlog.CtxErrorf(ctx, "invalid node link type: %s", req.Type)
+		return nil, ErrInvalidParams
+	}
+	label := strings.TrimSpace(req.Label)
+	if utf8.RuneCountInString(label) > nodeLinkLabelMaxRunes {
+		zlog.CtxErrorf(ctx, "node link label too long")
+		return nil, ErrInvalidParams
+	}
+
+	source, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if node, _, _ := source.Data.FindNode(req.SourceNodeID); node == nil {
+		zlog.CtxWarnf(ctx, "source node not found, mapID: %s, nodeID: %s", mapID, req.SourceNodeID)
+		return nil, ErrNodeNotFound
+	}
+
+	target := source
+	if req.TargetMapID != mapID {
+		if target, _, err = s.getMindMapWithRole(ctx, req.TargetMapID, entity.MindMapRoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	targetNodeText := ""
+	if req.TargetNodeID != "" {
+		node, _, _ := target.Data.FindNode(req.TargetNodeID)
+		if node == nil {
+			zlog.CtxWarnf(ctx, "target node not found, mapID: %s, nodeID: %s", req.TargetMapID, req.TargetNodeID)
+			return nil, ErrNodeNotFound
+		}
+		targetNodeText = node.Data.Text
+	}
+
+	linkID, err := util.GenerateStringID()
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to generate node link id: %v", err)
+		return nil, ErrInternalError
+	}
+	user, _ := entity.GetUser(ctx)
+	link = &entity.MindMapNodeLink{
+		LinkID:         linkID,
+		SourceMapID:    mapID,
+		SourceNodeUID:  req.SourceNodeID,
+		TargetMapID:    req.TargetMapID,
+		TargetNodeUID:  req.TargetNodeID,
+		Type:           linkType,
+		Label:          label,
+		UserID:         user.UserID,
+		TargetMapTitle: target.Title,
+		TargetNodeText: targetNodeText,
+	}
+	if err := s.mindMapRepo.CreateMindMapNodeLink(ctx, link); err != nil {
+		if errors.Is(err, repo.ErrMindMapNodeLinkExists) {
+			return nil, ErrNodeLinkExists
+		}
+		zlog.CtxErrorf(ctx, "failed to create mindmap node link: %v", err)
+		return nil, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap node link created successfully, linkID: %s, source: %s/%s, target: %s/%s",
+		linkID, mapID, req.SourceNodeID, req.TargetMapID, req.TargetNodeID)
+	return link, nil
+}
+
+// ListMindMapNodeLinks 获取导图发出的链接，nodeID 不为空时只返回该节点的链接
+func (s *MindMapServiceImpl) ListMindMapNodeLinks(ctx context.Context, mapID, nodeID string) ([]*entity.MindMapNodeLink, error) {
+	if _, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleViewer); err != nil {
+		return nil, err
+	}
+
+	user, _ := entity.GetUser(ctx)
+	links, err := s.mindMapRepo.ListMindMapNodeLinks(ctx, repo.MindMapNodeLinkQuery{
+		UserID:  user.UserID,
+		MapID:   mapID,
+		NodeUID: nodeID,
+		Limit:   nodeLinkListLimit,
+	})
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list mindmap node links: %v", err)
+		return nil, ErrInternalError
+	}
+	return links, nil
+}
+
+// ListMindMapBacklinks 获取指向导图的链接（反向链接），nodeID 不为空时只返回指向该节点的链接；
+// 只包含来自当前用户可访问导图的链接
+func (s *MindMapServiceImpl) ListMindMapBacklinks(ctx context.Context, mapID, nodeID string) ([]*entity.MindMapNodeLink, error) {
+	target, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	user, _ := entity.GetUser(ctx)
+	links, err := s.mindMapRepo.ListMindMapBacklinks(ctx, repo.MindMapNodeLinkQuery{
+		UserID:  user.UserID,
+		MapID:   mapID,
+		NodeUID: nodeID,
+		Limit:   nodeLinkListLimit,
+	})
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list mindmap backlinks: %v", err)
+		return nil, ErrInternalError
+	}
+
+	// 目标即当前导图，直接以最新数据填充目标信息
+	for _, link := range links {
+		link.TargetMapTitle = target.Title
+		if link.TargetNodeUID == "" {
+			continue
+		}
+		if node, _, _ := target.Data.FindNode(link.TargetNodeUID); node != nil {
+			link.TargetNodeText = node.Data.Text
+		}
+	}
+	return links, nil
+}
+
+// DeleteMindMapNodeLink 删除导图发出的链接，需要编辑权限
+func (s *MindMapServiceImpl) DeleteMindMapNodeLink(ctx context.Context, mapID, linkID string) error {
+	if linkID == "" {
+		zlog.CtxErrorf(ctx, "linkID is required")
+		return ErrInvalidParams
+	}
+	if _, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleEditor); err != nil {
+		return err
+	}
+
+	link, err := s.mindMapRepo.GetMindMapNodeLink(ctx, linkID)
+	if err != nil {
+		if errors.Is(err, repo.ErrMindMapNodeLinkNotFound) {
+			return ErrNodeLinkNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to get mindmap node link: %v", err)
+		return ErrInternalError
+	}
+	if link.SourceMapID != mapID {
+		zlog.CtxWarnf(ctx, "node link does not belong to mindmap, mapID: %s, linkID: %s", mapID, linkID)
+		return ErrNodeLinkNotFound
+	}
+
+	if err := s.mindMapRepo.DeleteMindMapNodeLink(ctx, linkID); err != nil {
+		if errors.Is(err, repo.ErrMindMapNodeLinkNotFound) {
+			return ErrNodeLinkNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to delete mindmap node link: %v", err)
+		return ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap node link deleted successfully, mapID: %s, linkID: %s", mapID, linkID)
+	return nil
+}
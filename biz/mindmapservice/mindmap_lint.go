@@ -0,0 +1,53 @@
+package mindmapservice
+
+import (
+	"context"
+	"encoding/json"
+
+	"forge/biz/entity"
+	"forge/biz/generationservice"
+	"forge/biz/mindmapservice/linter"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+)
+
+// LintMindMap 检查导图结构与文本问题：质量评分与训练数据导出使用同一套校验（占位文本按用户导图的规则判断），节点级问题给出节点路径
+func (s *MindMapServiceImpl) LintMindMap(ctx context.Context, mapID string) (report *entity.MindMapLintReport, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.lint_mindmap", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, mapID, report, err)
+	}()
+
+	// 复用GetMindMap完成登录态与查看权限校验
+	mindMap, err := s.GetMindMap(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	report = linter.Lint(&mindMap.Data)
+	report.MapID = mindMap.MapID
+	report.Version = mindMap.Version
+
+	mapJSON, err := json.Marshal(map[string]interface{}{
+		"title":  mindMap.Title,
+		"layout": mindMap.Layout,
+		"root":   mindMap.Data,
+	})
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to marshal mindmap for quality validation: %v", err)
+		return nil, ErrInternalError
+	}
+	// 格式校验不通过时同样返回评分与问题，具体节点由检查结果定位；占位文本与节点级检查使用同一规则
+	metrics, err := generationservice.ValidateMindMapQualityWith(string(mapJSON), linter.IsPlaceholderText)
+	if err != nil {
+		zlog.CtxInfof(ctx, "mindmap failed format validation, mapID: %s: %v", mapID, err)
+	}
+	report.FormatScore = metrics.FormatScore
+	report.ContentScore = metrics.ContentScore
+	report.OverallScore = metrics.OverallScore
+	report.QualityIssues = metrics.Issues
+
+	return report, nil
+}
@@ -0,0 +1,243 @@
+package mindmapservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"forge/biz/entity"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+)
+
+// 节点操作错误定义
+var (
+	ErrNodeNotFound         = errors.New("节点不存在")
+	ErrInvalidNodeOperation = errors.New("节点操作无效")
+)
+
+// maxPatchOperations 单次增量更新允许的最大操作数
+const maxPatchOperations = 500
+
+// PatchMindMap 按顺序原子执行节点级操作（任一操作失败则整体不生效）
+func (s *MindMapServiceImpl) PatchMindMap(ctx context.Context, mapID string, req *types.PatchMindMapParams) (newVersion int64, addedNodeIDs []string, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.patch_mindmap", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, addedNodeIDs, err)
+	}()
+
+	if req == nil || len(req.Operations) == 0 || len(req.Operations) > maxPatchOperations {
+		zlog.CtxErrorf(ctx, "invalid patch operations count")
+		return 0, nil, ErrInvalidParams
+	}
+
+	// 获取当前导图（校验编辑权限）
+	existingMindMap, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleEditor)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// 在副本上执行操作，保证失败时不产生部分修改
+	data := existingMindMap.Data.Clone()
+	addedNodeIDs = make([]string, 0)
+	operations := make([]types.NodeOperation, 0, len(req.Operations))
+	for i := range req.Operations {
+		op, prepErr := prepareNodeOperation(req.Operations[i])
+		if prepErr != nil {
+			zlog.CtxErrorf(ctx, "failed to prepare node operation: %v", prepErr)
+			return 0, nil, ErrInternalError
+		}
+		addedID, opErr := applyNodeOperation(&data, &op)
+		if opErr != nil {
+			zlog.CtxWarnf(ctx, "apply node operation failed, mapID: %s, index: %d, op: %s, err: %v",
+				mapID, i, req.Operations[i].Op, opErr)
+			return 0, nil, fmt.Errorf("第%d个操作失败: %w", i+1, opErr)
+		}
+		if addedID != "" {
+			addedNodeIDs = append(addedNodeIDs, addedID)
+		}
+		operations = append(operations, op)
+	}
+
+	// 未指定版本时以读取到的版本作为期望版本，防止并发写入被覆盖
+	expectedVersion := req.ExpectedVersion
+	if expectedVersion == nil {
+		expectedVersion = &existingMindMap.Version
+	}
+
+	newVersion, err = s.updateMindMap(ctx, mapID, &types.UpdateMindMapParams{
+		Data:            &data,
+		ExpectedVersion: expectedVersion,
+	}, entity.RevisionSourceUpdate)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// 以节点级操作的形式同步给正在协同编辑的客户端
+	user, _ := entity.GetUser(ctx)
+	s.publishCollabEvent(ctx, &types.CollabEvent{
+		Type:       types.CollabEventOperation,
+		MapID:      mapID,
+		Version:    newVersion,
+		UserID:     user.UserID,
+		Operations: operations,
+	})
+
+	zlog.CtxInfof(ctx, "mindmap patched successfully, mapID: %s, operations: %d, version: %d", mapID, len(req.Operations), newVersion)
+	return newVersion, addedNodeIDs, nil
+}
+
+// applyNodeOperation 在节点树上执行单个操作，返回新增节点的UID（仅add_child）
+func applyNodeOperation(root *entity.MindMapData, op *types.NodeOperation) (string, error) {
+	switch op.Op {
+	case types.NodeOpAddChild:
+		return applyAddChild(root, op)
+	case types.NodeOpDelete:
+		return "", applyDelete(root, op)
+	case types.NodeOpMove:
+		return "", applyMove(root, op)
+	case types.NodeOpReorder:
+		return "", applyReorder(root, op)
+	case types.NodeOpEditText:
+		return "", applyEditText(root, op)
+	default:
+		return "", fmt.Errorf("%w: 未知操作类型 %q", ErrInvalidNodeOperation, op.Op)
+	}
+}
+
+func applyAddChild(root *entity.MindMapData, op *types.NodeOperation) (string, error) {
+	parent, _, _ := root.FindNode(op.ParentID)
+	if parent == nil {
+		return "", fmt.Errorf("%w: 父节点 %s", ErrNodeNotFound, op.ParentID)
+	}
+
+	var child entity.MindMapData
+	if op.Node != nil {
+		child = op.Node.Clone()
+	}
+	if op.Text != nil {
+		child.Data.Text = *op.Text
+	}
+	if child.Data.Text == "" {
+		return "", fmt.Errorf("%w: 节点文本不能为空", ErrInvalidNodeOperation)
+	}
+
+	// 客户端指定的UID不能与现有节点冲突
+	var conflictID string
+	child.Walk(func(n, _ *entity.MindMapData, _ int) bool {
+		if n.Data.UID == "" {
+			return true
+		}
+		if existing, _, _ := root.FindNode(n.Data.UID); existing != nil {
+			conflictID = n.Data.UID
+			return false
+		}
+		return true
+	})
+	if conflictID != "" {
+		return "", fmt.Errorf("%w: 节点UID %s 已存在", ErrInvalidNodeOperation, conflictID)
+	}
+	if _, err := child.EnsureUIDs(); err != nil {
+		return "", err
+	}
+
+	children, err := insertChild(parent.Children, child, op.Index)
+	if err != nil {
+		return "", err
+	}
+	parent.Children = children
+	return child.Data.UID, nil
+}
+
+func applyDelete(root *entity.MindMapData, op *types.NodeOperation) error {
+	node, parent, index := root.FindNode(op.NodeID)
+	if node == nil {
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, op.NodeID)
+	}
+	if parent == nil {
+		return fmt.Errorf("%w: 不能删除根节点", ErrInvalidNodeOperation)
+	}
+	parent.Children = removeChild(parent.Children, index)
+	return nil
+}
+
+func applyMove(root *entity.MindMapData, op *types.NodeOperation) error {
+	node, parent, index := root.FindNode(op.NodeID)
+	if node == nil {
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, op.NodeID)
+	}
+	if parent == nil {
+		return fmt.Errorf("%w: 不能移动根节点", ErrInvalidNodeOperation)
+	}
+	if target, _, _ := node.FindNode(op.ParentID); target != nil {
+		return fmt.Errorf("%w: 不能移动到自身或子孙节点下", ErrInvalidNodeOperation)
+	}
+	if target, _, _ := root.FindNode(op.ParentID); target == nil {
+		return fmt.Errorf("%w: 父节点 %s", ErrNodeNotFound, op.ParentID)
+	}
+
+	moved := *node
+	parent.Children = removeChild(parent.Children, index)
+
+	// 删除后切片已变化，需重新查找目标父节点
+	target, _, _ := root.FindNode(op.ParentID)
+	children, err := insertChild(target.Children, moved, op.Index)
+	if err != nil {
+		return err
+	}
+	target.Children = children
+	return nil
+}
+
+func applyReorder(root *entity.MindMapData, op *types.NodeOperation) error {
+	node, parent, index := root.FindNode(op.NodeID)
+	if node == nil {
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, op.NodeID)
+	}
+	if parent == nil {
+		return fmt.Errorf("%w: 根节点没有兄弟节点", ErrInvalidNodeOperation)
+	}
+	if op.Index == nil || *op.Index < 0 || *op.Index >= len(parent.Children) {
+		return fmt.Errorf("%w: 目标位置无效", ErrInvalidNodeOperation)
+	}
+
+	moved := *node
+	children := removeChild(parent.Children, index)
+	parent.Children, _ = insertChild(children, moved, op.Index)
+	return nil
+}
+
+func applyEditText(root *entity.MindMapData, op *types.NodeOperation) error {
+	node, _, _ := root.FindNode(op.NodeID)
+	if node == nil {
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, op.NodeID)
+	}
+	if op.Text == nil || *op.Text == "" {
+		return fmt.Errorf("%w: 节点文本不能为空", ErrInvalidNodeOperation)
+	}
+	node.Data.Text = *op.Text
+	return nil
+}
+
+// insertChild 在指定位置插入子节点，index 为空时追加到末尾
+func insertChild(children []entity.MindMapData, child entity.MindMapData, index *int) ([]entity.MindMapData, error) {
+	pos := len(children)
+	if index != nil {
+		pos = *index
+	}
+	if pos < 0 || pos > len(children) {
+		return nil, fmt.Errorf("%w: 目标位置无效", ErrInvalidNodeOperation)
+	}
+	return slices.Insert(children, pos, child), nil
+}
+
+// removeChild 删除指定位置的子节点，返回新的切片（不修改原底层数组）
+func removeChild(children []entity.MindMapData, index int) []entity.MindMapData {
+	result := make([]entity.MindMapData, 0, len(children)-1)
+	result = append(result, children[:index]...)
+	return append(result, children[index+1:]...)
+}
@@ -0,0 +1,88 @@
+package mindmapservice
+
+import (
+	"context"
+	"errors"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+)
+
+// ListMindMapRevisions 获取思维导图历史版本列表（用户只能查看自己导图的历史版本）
+func (s *MindMapServiceImpl) ListMindMapRevisions(ctx context.Context, mapID string, req *types.ListMindMapRevisionsParams) ([]*entity.MindMapRevision, int64, error) {
+	// 复用GetMindMap完成登录态与查看权限校验
+	if _, err := s.GetMindMap(ctx, mapID); err != nil {
+		return nil, 0, err
+	}
+
+	// 复用列表分页的默认值与上限
+	query := repo.NewMindMapQueryForList("", req.Page, req.PageSize)
+
+	revisions, total, err := s.mindMapRepo.ListMindMapRevisions(ctx, mapID, query.Page, query.PageSize)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list mindmap revisions: %v", err)
+		return nil, 0, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap revisions listed successfully, mapID: %s, count: %d, total: %d", mapID, len(revisions), total)
+	return revisions, total, nil
+}
+
+// GetMindMapRevision 获取思维导图指定历史版本的完整快照
+func (s *MindMapServiceImpl) GetMindMapRevision(ctx context.Context, mapID string, version int64) (revision *entity.MindMapRevision, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.get_mindmap_revision", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "version": version}, revision, err)
+	}()
+
+	if version <= 0 {
+		zlog.CtxErrorf(ctx, "invalid revision version: %d", version)
+		return nil, ErrInvalidParams
+	}
+
+	// 复用GetMindMap完成登录态与查看权限校验
+	if _, err = s.GetMindMap(ctx, mapID); err != nil {
+		return nil, err
+	}
+
+	revision, err = s.mindMapRepo.GetMindMapRevision(ctx, mapID, version)
+	if err != nil {
+		if errors.Is(err, repo.ErrMindMapRevisionNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to get mindmap revision: %v", err)
+		return nil, ErrInternalError
+	}
+
+	return revision, nil
+}
+
+// RestoreMindMapRevision 将思维导图恢复到指定历史版本（恢复本身会产生一个新版本）
+func (s *MindMapServiceImpl) RestoreMindMapRevision(ctx context.Context, mapID string, req *types.RestoreMindMapRevisionParams) (int64, error) {
+	revision, err := s.GetMindMapRevision(ctx, mapID, req.Version)
+	if err != nil {
+		return 0, err
+	}
+
+	params := &types.UpdateMindMapParams{
+		Title:           &revision.Title,
+		Desc:            &revision.Desc,
+		Layout:          &revision.Layout,
+		Data:            &revision.Data,
+		ExpectedVersion: req.ExpectedVersion,
+	}
+
+	newVersion, err := s.updateMindMap(ctx, mapID, params, entity.RevisionSourceRestore)
+	if err != nil {
+		return 0, err
+	}
+	s.publishCollabReset(ctx, mapID, newVersion)
+
+	zlog.CtxInfof(ctx, "mindmap restored successfully, mapID: %s, from version: %d, new version: %d", mapID, req.Version, newVersion)
+	return newVersion, nil
+}
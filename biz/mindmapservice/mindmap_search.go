@@ -0,0 +1,301 @@
+package mindmapservice
+
+import (
+	"context"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+	"forge/pkg/tokenizer"
+)
+
+const (
+	searchMaxQueryRunes  = 100  // 查询文本最大字符数
+	searchCandidateLimit = 1000 // 单次检索最多参与打分的候选文档数
+	searchMaxHitsPerMap  = 5    // 每个导图最多返回的命中节点数
+	searchSnippetRunes   = 60   // 高亮片段的最大字符数
+
+	// BM25 参数，节点文本普遍较短，平均长度取经验值
+	searchBM25K1     = 1.2
+	searchBM25B      = 0.75
+	searchAvgDocLen  = 12.0
+	searchNoteWeight = 0.5 // 备注中的命中权重低于节点文本
+	searchTitleBoost = 2.0 // 标题命中的权重
+)
+
+// 高亮标签
+const (
+	searchHighlightOpen  = "<em>"
+	searchHighlightClose = "</em>"
+)
+
+// 命中片段来源
+const (
+	SearchFieldText = "text"
+	SearchFieldNote = "note"
+)
+
+// SearchMindMaps 在用户可访问（自己创建或参与协作）的导图中全文检索标题与节点文本，按相关度返回导图及命中节点
+func (s *MindMapServiceImpl) SearchMindMaps(ctx context.Context, req *types.SearchMindMapsParams) (results []*types.MindMapSearchResult, total int64, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.search_mindmaps", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, req, total, err)
+	}()
+
+	// 从JWT token上下文中获取用户信息
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, 0, ErrPermissionDenied
+	}
+
+	query := strings.TrimSpace(req.Query)
+	if query == "" || utf8.RuneCountInString(query) > searchMaxQueryRunes {
+		zlog.CtxErrorf(ctx, "invalid search query length: %d", utf8.RuneCountInString(query))
+		return nil, 0, ErrInvalidParams
+	}
+	tokens := tokenizer.QueryTokens(query)
+	if len(tokens) == 0 {
+		// 纯标点等无法分词的查询没有结果
+		return []*types.MindMapSearchResult{}, 0, nil
+	}
+
+	candidates, err := s.mindMapRepo.SearchMindMapNodes(ctx, repo.MindMapSearchQuery{
+		UserID: user.UserID,
+		Tokens: tokens,
+		Limit:  searchCandidateLimit,
+	})
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to search mindmap nodes: %v", err)
+		return nil, 0, ErrInternalError
+	}
+
+	results = rankSearchCandidates(query, tokens, candidates)
+	total = int64(len(results))
+
+	// 分页
+	listQuery := repo.NewMindMapQueryForList(user.UserID, req.Page, req.PageSize)
+	start := (listQuery.Page - 1) * listQuery.PageSize
+	if start >= len(results) {
+		return []*types.MindMapSearchResult{}, total, nil
+	}
+	end := start + listQuery.PageSize
+	if end > len(results) {
+		end = len(results)
+	}
+	results = results[start:end]
+
+	// 补充当前页导图的基本信息，检索期间被删除的导图直接跳过
+	mapIDs := make([]string, 0, len(results))
+	for _, result := range results {
+		mapIDs = append(mapIDs, result.MindMap.MapID)
+	}
+	summaries, err := s.mindMapRepo.BatchGetMindMapSummaries(ctx, mapIDs)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to get mindmap summaries: %v", err)
+		return nil, 0, ErrInternalError
+	}
+	summaryByID := make(map[string]*entity.MindMap, len(summaries))
+	for _, summary := range summaries {
+		summaryByID[summary.MapID] = summary
+	}
+	page := make([]*types.MindMapSearchResult, 0, len(results))
+	for _, result := range results {
+		summary, ok := summaryByID[result.MindMap.MapID]
+		if !ok {
+			continue
+		}
+		result.MindMap = summary
+		page = append(page, result)
+	}
+
+	zlog.CtxInfof(ctx, "mindmaps searched successfully, userID: %s, tokens: %d, candidates: %d, total: %d",
+		user.UserID, len(tokens), len(candidates.Docs), total)
+	return page, total, nil
+}
+
+// rankSearchCandidates 对候选文档打分（BM25 + 短语命中加权），按导图聚合后按相关度降序排列
+func rankSearchCandidates(query string, tokens []string, candidates *repo.MindMapSearchCandidates) []*types.MindMapSearchResult {
+	idf := make(map[string]float64, len(tokens))
+	for _, token := range tokens {
+		df := float64(candidates.DocFreq[token])
+		n := math.Max(float64(candidates.TotalDocs), df)
+		idf[token] = math.Log(1 + (n-df+0.5)/(df+0.5))
+	}
+	phrase := string(tokenizer.Normalize(query))
+
+	resultByMapID := make(map[string]*types.MindMapSearchResult)
+	nodeScores := make(map[string][]float64)
+	for _, doc := range candidates.Docs {
+		score := scoreSearchDoc(doc, tokens, idf, phrase)
+		result, ok := resultByMapID[doc.MapID]
+		if !ok {
+			result = &types.MindMapSearchResult{MindMap: &entity.MindMap{MapID: doc.MapID}}
+			resultByMapID[doc.MapID] = result
+		}
+
+		if doc.IsTitle() {
+			result.Score += score * searchTitleBoost
+			result.TitleSnippet = highlightSnippet(doc.Text, tokens, 0)
+			continue
+		}
+
+		// 优先展示节点文本，备注命中的查询词更多时改为展示备注
+		field, snippet := SearchFieldText, highlightSnippet(doc.Text, tokens, searchSnippetRunes)
+		if countMatchedTokens(doc.Note, tokens) > countMatchedTokens(doc.Text, tokens) {
+			field, snippet = SearchFieldNote, highlightSnippet(doc.Note, tokens, searchSnippetRunes)
+		}
+		result.Hits = append(result.Hits, &types.MindMapSearchHit{
+			NodeUID: doc.NodeUID,
+			Path:    doc.Path,
+			Field:   field,
+			Snippet: snippet,
+			Score:   score,
+		})
+		nodeScores[doc.MapID] = append(nodeScores[doc.MapID], score)
+	}
+
+	results := make([]*types.MindMapSearchResult, 0, len(resultByMapID))
+	for mapID, result := range resultByMapID {
+		// 导图得分：最佳节点得分 + 其余节点得分的衰减累加，避免节点多的导图单纯依靠数量取胜
+		scores := nodeScores[mapID]
+		sort.Sort(sort.Reverse(sort.Float64Slice(scores)))
+		for i, score := range scores {
+			if i == 0 {
+				result.Score += score
+			} else {
+				result.Score += score * 0.25 / float64(i)
+			}
+		}
+
+		sort.SliceStable(result.Hits, func(i, j int) bool {
+			if result.Hits[i].Score != result.Hits[j].Score {
+				return result.Hits[i].Score > result.Hits[j].Score
+			}
+			return len(result.Hits[i].Path) < len(result.Hits[j].Path)
+		})
+		result.HitCount = len(result.Hits)
+		if len(result.Hits) > searchMaxHitsPerMap {
+			result.Hits = result.Hits[:searchMaxHitsPerMap]
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].MindMap.MapID > results[j].MindMap.MapID
+	})
+	return results
+}
+
+// scoreSearchDoc 计算单个文档的相关度得分
+func scoreSearchDoc(doc *entity.MindMapNodeDoc, tokens []string, idf map[string]float64, phrase string) float64 {
+	freqs := make(map[string]float64)
+	textTokens := tokenizer.Tokenize(doc.Text)
+	noteTokens := tokenizer.Tokenize(doc.Note)
+	for _, token := range textTokens {
+		freqs[token]++
+	}
+	for _, token := range noteTokens {
+		freqs[token] += searchNoteWeight
+	}
+	docLen := float64(len(textTokens)) + float64(len(noteTokens))*searchNoteWeight
+
+	score := 0.0
+	for _, token := range tokens {
+		tf := freqs[token]
+		if tf == 0 {
+			continue
+		}
+		norm := searchBM25K1 * (1 - searchBM25B + searchBM25B*docLen/searchAvgDocLen)
+		score += idf[token] * tf * (searchBM25K1 + 1) / (tf + norm)
+	}
+
+	// 完整短语命中加权，文本与查询完全一致时权重最高
+	text := string(tokenizer.Normalize(strings.TrimSpace(doc.Text)))
+	switch {
+	case text == phrase:
+		score *= 2
+	case strings.Contains(text, phrase):
+		score *= 1.5
+	}
+
+	// 浅层节点通常是主题，略微提高其权重
+	if doc.Depth > 0 {
+		score /= 1 + 0.05*float64(doc.Depth)
+	}
+	return score
+}
+
+// highlightSnippet 截取包含首个命中位置的片段并高亮全部命中，maxRunes 为0时不截取
+func highlightSnippet(text string, tokens []string, maxRunes int) string {
+	runes := []rune(text)
+	spans := tokenizer.MatchSpans(text, tokens)
+
+	start, end := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		// 命中位置前保留少量上下文
+		if len(spans) > 0 {
+			start = spans[0].Start - maxRunes/4
+		}
+		if start < 0 {
+			start = 0
+		}
+		end = start + maxRunes
+		if end > len(runes) {
+			end = len(runes)
+			start = end - maxRunes
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	cursor := start
+	for _, span := range spans {
+		if span.End <= start || span.Start >= end {
+			continue
+		}
+		spanStart, spanEnd := max(span.Start, start), min(span.End, end)
+		b.WriteString(html.EscapeString(string(runes[cursor:spanStart])))
+		b.WriteString(searchHighlightOpen)
+		b.WriteString(html.EscapeString(string(runes[spanStart:spanEnd])))
+		b.WriteString(searchHighlightClose)
+		cursor = spanEnd
+	}
+	b.WriteString(html.EscapeString(string(runes[cursor:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// countMatchedTokens 统计 text 中出现的查询词元数量
+func countMatchedTokens(text string, tokens []string) int {
+	if text == "" {
+		return 0
+	}
+	present := make(map[string]struct{})
+	for _, token := range tokenizer.Tokenize(text) {
+		present[token] = struct{}{}
+	}
+	count := 0
+	for _, token := range tokens {
+		if _, ok := present[token]; ok {
+			count++
+		}
+	}
+	return count
+}
@@ -3,13 +3,17 @@ package mindmapservice
 import (
 	"context"
 	"errors"
+	"forge/biz/adapter"
 	"forge/biz/entity"
+	"forge/biz/mindmapservice/layout"
 	"forge/biz/repo"
 	"forge/biz/types"
 	"forge/constant"
 	"forge/pkg/log/zlog"
 	"forge/pkg/loop"
 	"forge/util"
+	"sync"
+	"time"
 )
 
 // 错误定义
@@ -19,16 +23,31 @@ var (
 	ErrInvalidParams        = errors.New("参数无效")
 	ErrPermissionDenied     = errors.New("权限不足")
 	ErrInternalError        = errors.New("内部错误")
+	ErrVersionConflict      = errors.New("思维导图已被修改，请刷新后重试")
+	ErrRevisionNotFound     = errors.New("思维导图历史版本不存在")
+	ErrUnsupportedLayout    = errors.New("不支持的布局类型")
 )
 
 // MindMapServiceImpl 思维导图服务实现
 type MindMapServiceImpl struct {
 	mindMapRepo repo.IMindMapRepo
+	userRepo    repo.UserRepo
+	einoServer  repo.EinoServer    // 用于模板占位节点的AI填充
+	cosService  adapter.COSService // 用于上传导图缩略图
+
+	thumbnailMu       sync.Mutex
+	thumbnailPending  map[string]*entity.MindMap // 正在生成缩略图的导图及其待生成的最新快照
+	thumbnailFailedAt map[string]time.Time       // 缩略图最近一次生成失败的时间，成功后移除
 }
 
-func NewMindMapServiceImpl(mindMapRepo repo.IMindMapRepo) *MindMapServiceImpl {
+func NewMindMapServiceImpl(mindMapRepo repo.IMindMapRepo, userRepo repo.UserRepo, einoServer repo.EinoServer, cosService adapter.COSService) *MindMapServiceImpl {
 	return &MindMapServiceImpl{
-		mindMapRepo: mindMapRepo,
+		mindMapRepo:       mindMapRepo,
+		userRepo:          userRepo,
+		einoServer:        einoServer,
+		cosService:        cosService,
+		thumbnailPending:  make(map[string]*entity.MindMap),
+		thumbnailFailedAt: make(map[string]time.Time),
 	}
 }
 
@@ -58,12 +77,13 @@ func (s *MindMapServiceImpl) CreateMindMap(ctx context.Context, req *types.Creat
 
 	// 构建实体
 	mindMap := &entity.MindMap{
-		MapID:  mapID,
-		UserID: user.UserID, // 从JWT token中获取的用户ID
-		Title:  req.Title,
-		Desc:   req.Desc,
-		Layout: req.Layout,
-		Data:   req.Data,
+		MapID:    mapID,
+		UserID:   user.UserID, // 从JWT token中获取的用户ID
+		Title:    req.Title,
+		Desc:     req.Desc,
+		Layout:   req.Layout,
+		Data:     req.Data,
+		FolderID: req.FolderID,
 	}
 
 	// 实体校验
@@ -71,6 +91,16 @@ func (s *MindMapServiceImpl) CreateMindMap(ctx context.Context, req *types.Creat
 		zlog.CtxErrorf(ctx, "mindmap validation failed: %v", err)
 		return nil, err
 	}
+	if !layout.IsValid(mindMap.Layout) {
+		zlog.CtxErrorf(ctx, "unsupported mindmap layout: %s", mindMap.Layout)
+		return nil, ErrUnsupportedLayout
+	}
+
+	// 为节点补齐UID，保证后续节点级操作可寻址
+	if _, err := mindMap.Data.EnsureUIDs(); err != nil {
+		zlog.CtxErrorf(ctx, "failed to generate node uids: %v", err)
+		return nil, ErrInternalError
+	}
 
 	// 持久化
 	if err := s.mindMapRepo.CreateMindMap(ctx, mindMap); err != nil {
@@ -78,11 +108,14 @@ func (s *MindMapServiceImpl) CreateMindMap(ctx context.Context, req *types.Creat
 		return nil, ErrInternalError
 	}
 
+	// 异步生成缩略图，不阻塞保存
+	s.scheduleMindMapThumbnail(mindMap)
+
 	zlog.CtxInfof(ctx, "mindmap created successfully, mapID: %s, userID: %s", mapID, user.UserID)
 	return mindMap, nil
 }
 
-// GetMindMap 获取思维导图（用户只能获取自己的思维导图）
+// GetMindMap 获取思维导图（所有者及协作者可查看）
 func (s *MindMapServiceImpl) GetMindMap(ctx context.Context, mapID string) (mindMap *entity.MindMap, err error) {
 	// 服务层链路追踪
 	ctx, sp := loop.GetNewSpan(ctx, "service.get_mindmap", constant.LoopSpanType_Function)
@@ -90,38 +123,16 @@ func (s *MindMapServiceImpl) GetMindMap(ctx context.Context, mapID string) (mind
 		loop.SetSpanAllInOne(ctx, sp, mapID, mindMap, err)
 	}()
 
-	// 从JWT token上下文中获取用户信息
-	user, ok := entity.GetUser(ctx)
-	if !ok {
-		zlog.CtxErrorf(ctx, "failed to get user from context")
-		return nil, ErrPermissionDenied
-	}
-
-	// 参数校验
-	if mapID == "" {
-		zlog.CtxErrorf(ctx, "mapID is required")
-		return nil, ErrInvalidParams
-	}
-
-	// 构建查询条件（包含用户ID验证）
-	query := repo.NewMindMapQueryByID(user.UserID, mapID)
-
-	// 查询思维导图
-	mindMap, err = s.mindMapRepo.GetMindMap(ctx, query)
+	mindMap, _, err = s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleViewer)
 	if err != nil {
-		zlog.CtxErrorf(ctx, "failed to get mindmap: %v", err)
-		return nil, ErrInternalError
-	}
-	if mindMap == nil {
-		zlog.CtxWarnf(ctx, "mindmap not found or permission denied, mapID: %s, userID: %s", mapID, user.UserID)
-		return nil, ErrMindMapNotFound
+		return nil, err
 	}
 
-	zlog.CtxInfof(ctx, "mindmap retrieved successfully, mapID: %s, userID: %s", mapID, user.UserID)
+	zlog.CtxInfof(ctx, "mindmap retrieved successfully, mapID: %s", mapID)
 	return mindMap, nil
 }
 
-// ListMindMaps 获取思维导图列表（用户只能获取自己的思维导图列表）
+// ListMindMaps 获取思维导图列表（按范围返回自己创建的和/或参与协作的导图）
 func (s *MindMapServiceImpl) ListMindMaps(ctx context.Context, req *types.ListMindMapsParams) ([]*entity.MindMap, int64, error) {
 	// 从JWT token上下文中获取用户信息
 	user, ok := entity.GetUser(ctx)
@@ -140,6 +151,15 @@ func (s *MindMapServiceImpl) ListMindMaps(ctx context.Context, req *types.ListMi
 	if req.Layout != "" {
 		query.Layout = req.Layout
 	}
+	query.Scope = req.Scope
+	query.FolderID = req.FolderID
+	if len(req.TagIDs) > 0 {
+		// 只能按自己的标签筛选
+		if err := s.checkMindMapTagsOwned(ctx, req.TagIDs); err != nil {
+			return nil, 0, err
+		}
+		query.TagIDs = dedupeIDs(req.TagIDs)
+	}
 
 	// 查询列表
 	mindMaps, total, err := s.mindMapRepo.ListMindMaps(ctx, query)
@@ -148,32 +168,58 @@ func (s *MindMapServiceImpl) ListMindMaps(ctx context.Context, req *types.ListMi
 		return nil, 0, ErrInternalError
 	}
 
+	// 补生成历史导图及AI生成导图的缩略图
+	for _, mindMap := range mindMaps {
+		if mindMap.ThumbnailURL == "" {
+			s.backfillMindMapThumbnail(mindMap)
+		}
+	}
+
 	zlog.CtxInfof(ctx, "mindmaps listed successfully, userID: %s, count: %d, total: %d", user.UserID, len(mindMaps), total)
 	return mindMaps, total, nil
 }
 
-// UpdateMindMap 更新思维导图（用户只能更新自己的思维导图）
-func (s *MindMapServiceImpl) UpdateMindMap(ctx context.Context, mapID string, req *types.UpdateMindMapParams) error {
+// UpdateMindMap 更新思维导图（所有者及编辑者可更新）
+func (s *MindMapServiceImpl) UpdateMindMap(ctx context.Context, mapID string, req *types.UpdateMindMapParams) (int64, error) {
+	newVersion, err := s.updateMindMap(ctx, mapID, req, entity.RevisionSourceUpdate)
+	if err != nil {
+		return 0, err
+	}
+
+	// 全量更新无法表示为节点级操作，通知协同编辑中的客户端重新拉取
+	s.publishCollabReset(ctx, mapID, newVersion)
+	return newVersion, nil
+}
+
+// updateMindMap 校验并写入更新，source 记录到历史版本中
+func (s *MindMapServiceImpl) updateMindMap(ctx context.Context, mapID string, req *types.UpdateMindMapParams, source string) (int64, error) {
 	// 从JWT token上下文中获取用户信息
 	user, ok := entity.GetUser(ctx)
 	if !ok {
 		zlog.CtxErrorf(ctx, "failed to get user from context")
-		return ErrPermissionDenied
+		return 0, ErrPermissionDenied
 	}
 
 	// 参数校验
 	if mapID == "" {
 		zlog.CtxErrorf(ctx, "mapID is required")
-		return ErrInvalidParams
+		return 0, ErrInvalidParams
 	}
 
-	// 先获取现有思维导图（用于校验和构建临时实体）
-	existingMindMap, err := s.GetMindMap(ctx, mapID)
+	// 先获取现有思维导图（用于校验和构建临时实体），同时校验编辑权限
+	existingMindMap, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleEditor)
 	if err != nil {
-		return err // GetMindMap已经包含权限验证
+		return 0, err
 	}
 	if existingMindMap == nil {
-		return ErrMindMapNotFound
+		return 0, ErrMindMapNotFound
+	}
+
+	// 提前拦截过期写入，避免无谓的校验
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != existingMindMap.Version {
+		zlog.CtxWarnf(ctx, "mindmap version conflict, mapID: %s, expected: %d, current: %d",
+			mapID, *req.ExpectedVersion, existingMindMap.Version)
+		return 0, ErrVersionConflict
 	}
 
 	// 将更新应用到临时实体以进行校验（复用实体层的校验逻辑）
@@ -184,43 +230,73 @@ func (s *MindMapServiceImpl) UpdateMindMap(ctx context.Context, mapID string, re
 	if req.Desc != nil {
 		tempMindMap.Desc = *req.Desc
 	}
+	// 只校验发生变化的布局，早期导图保存的不支持布局原样提交时不拦截
+	if req.Layout != nil && *req.Layout != existingMindMap.Layout && !layout.IsValid(*req.Layout) {
+		if source != entity.RevisionSourceRestore {
+			zlog.CtxErrorf(ctx, "unsupported mindmap layout: %s", *req.Layout)
+			return 0, ErrUnsupportedLayout
+		}
+		// 历史版本可能保存了已不支持的布局，恢复时按默认布局处理
+		defaultLayout := layout.OrDefault(*req.Layout)
+		req.Layout = &defaultLayout
+	}
 	if req.Layout != nil {
 		tempMindMap.Layout = *req.Layout
 	}
 	if req.Data != nil {
+		// 为节点补齐UID，保证后续节点级操作可寻址
+		if _, err := req.Data.EnsureUIDs(); err != nil {
+			zlog.CtxErrorf(ctx, "failed to generate node uids: %v", err)
+			return 0, ErrInternalError
+		}
 		tempMindMap.Data = *req.Data
 	}
 
 	// 使用实体层的校验方法统一校验
 	if err := tempMindMap.Validate(); err != nil {
 		zlog.CtxErrorf(ctx, "mindmap validation failed after update: %v", err)
-		return err
+		return 0, err
 	}
 
 	// 构建更新信息
 	updateInfo := &repo.MindMapUpdateInfo{
-		MapID:  mapID,
-		UserID: user.UserID, // 确保只能更新自己的思维导图
-		Title:  req.Title,
-		Desc:   req.Desc,
-		Layout: req.Layout,
-		Data:   req.Data,
-	}
-
-	// 执行更新（repo层已包含权限校验）
-	if err := s.mindMapRepo.UpdateMindMap(ctx, updateInfo); err != nil {
+		MapID:           mapID,
+		UserID:          user.UserID, // 记录实际操作人
+		Title:           req.Title,
+		Desc:            req.Desc,
+		Layout:          req.Layout,
+		Data:            req.Data,
+		ExpectedVersion: req.ExpectedVersion,
+		Source:          source,
+	}
+
+	// 执行更新（repo层包含乐观锁校验）
+	newVersion, err := s.mindMapRepo.UpdateMindMap(ctx, updateInfo)
+	if err != nil {
 		if errors.Is(err, repo.ErrMindMapNotFound) {
-			return ErrMindMapNotFound
+			return 0, ErrMindMapNotFound
+		}
+		if errors.Is(err, repo.ErrMindMapVersionConflict) {
+			return 0, ErrVersionConflict
 		}
 		zlog.CtxErrorf(ctx, "failed to update mindmap: %v", err)
-		return ErrInternalError
+		return 0, ErrInternalError
+	}
+	if newVersion == 0 {
+		// 没有需要更新的字段，版本号保持不变
+		newVersion = existingMindMap.Version
+	}
+	// 节点或布局变化时异步重新生成缩略图
+	if (req.Data != nil || req.Layout != nil) && newVersion != existingMindMap.Version {
+		tempMindMap.Version = newVersion
+		s.scheduleMindMapThumbnail(&tempMindMap)
 	}
 
-	zlog.CtxInfof(ctx, "mindmap updated successfully, mapID: %s, userID: %s", mapID, user.UserID)
-	return nil
+	zlog.CtxInfof(ctx, "mindmap updated successfully, mapID: %s, userID: %s, version: %d", mapID, user.UserID, newVersion)
+	return newVersion, nil
 }
 
-// DeleteMindMap 删除思维导图（用户只能删除自己的思维导图）
+// DeleteMindMap 删除思维导图（仅所有者可删除）
 func (s *MindMapServiceImpl) DeleteMindMap(ctx context.Context, mapID string) error {
 	// 从JWT token上下文中获取用户信息
 	user, ok := entity.GetUser(ctx)
@@ -235,6 +311,11 @@ func (s *MindMapServiceImpl) DeleteMindMap(ctx context.Context, mapID string) er
 		return ErrInvalidParams
 	}
 
+	// 协作者无删除权限
+	if _, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleOwner); err != nil {
+		return err
+	}
+
 	// 执行删除（软删除，repo层已包含权限校验）
 	if err := s.mindMapRepo.DeleteMindMap(ctx, mapID, user.UserID); err != nil {
 		if errors.Is(err, repo.ErrMindMapNotFound) {
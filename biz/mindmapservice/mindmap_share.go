@@ -0,0 +1,189 @@
+package mindmapservice
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+	"forge/util"
+)
+
+var (
+	ErrShareNotFound          = errors.New("分享链接不存在或已失效")
+	ErrShareExpired           = errors.New("分享链接已过期")
+	ErrSharePasswordRequired  = errors.New("该分享需要访问密码")
+	ErrSharePasswordIncorrect = errors.New("分享访问密码错误")
+)
+
+// 分享令牌随机字节数，base64url编码后为32个字符
+const shareTokenBytes = 24
+
+// CreateMindMapShare 为思维导图创建只读分享链接（仅所有者可操作）
+func (s *MindMapServiceImpl) CreateMindMapShare(ctx context.Context, mapID string, req *types.CreateMindMapShareParams) (share *entity.MindMapShare, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.create_mindmap_share", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, mapID, share, err)
+	}()
+
+	// 仅所有者可管理分享
+	mindMap, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		zlog.CtxErrorf(ctx, "share expiresAt must be in the future: %v", req.ExpiresAt)
+		return nil, ErrInvalidParams
+	}
+
+	shareID, err := util.GenerateStringID()
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to generate share id: %v", err)
+		return nil, ErrInternalError
+	}
+	token, err := generateShareToken()
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to generate share token: %v", err)
+		return nil, ErrInternalError
+	}
+
+	share = &entity.MindMapShare{
+		ShareID:   shareID,
+		Token:     token,
+		MapID:     mindMap.MapID,
+		UserID:    mindMap.UserID,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if req.Password != "" {
+		if share.PasswordHash, err = util.HashPassword(req.Password); err != nil {
+			zlog.CtxErrorf(ctx, "failed to hash share password: %v", err)
+			return nil, ErrInternalError
+		}
+	}
+
+	if err = s.mindMapRepo.CreateMindMapShare(ctx, share); err != nil {
+		zlog.CtxErrorf(ctx, "failed to create mindmap share: %v", err)
+		return nil, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap share created successfully, mapID: %s, shareID: %s", mapID, shareID)
+	return share, nil
+}
+
+// ListMindMapShares 获取思维导图的分享链接列表（包含已撤销和已过期的）
+func (s *MindMapServiceImpl) ListMindMapShares(ctx context.Context, mapID string) ([]*entity.MindMapShare, error) {
+	mindMap, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := s.mindMapRepo.ListMindMapShares(ctx, mindMap.MapID, mindMap.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list mindmap shares: %v", err)
+		return nil, ErrInternalError
+	}
+	return shares, nil
+}
+
+// RevokeMindMapShare 撤销分享链接，撤销后链接立即失效
+func (s *MindMapServiceImpl) RevokeMindMapShare(ctx context.Context, mapID, shareID string) error {
+	if shareID == "" {
+		zlog.CtxErrorf(ctx, "shareID is required")
+		return ErrInvalidParams
+	}
+
+	mindMap, _, err := s.getMindMapWithRole(ctx, mapID, entity.MindMapRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mindMapRepo.RevokeMindMapShare(ctx, mindMap.MapID, shareID, mindMap.UserID); err != nil {
+		if errors.Is(err, repo.ErrMindMapShareNotFound) {
+			return ErrShareNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to revoke mindmap share: %v", err)
+		return ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap share revoked successfully, mapID: %s, shareID: %s", mapID, shareID)
+	return nil
+}
+
+// GetSharedMindMap 通过分享令牌只读访问思维导图，无需登录
+func (s *MindMapServiceImpl) GetSharedMindMap(ctx context.Context, token, password string) (mindMap *entity.MindMap, err error) {
+	// 服务层链路追踪（不记录密码）
+	ctx, sp := loop.GetNewSpan(ctx, "service.get_shared_mindmap", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, token, mindMap, err)
+	}()
+
+	if token == "" {
+		return nil, ErrInvalidParams
+	}
+
+	share, err := s.mindMapRepo.GetMindMapShareByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repo.ErrMindMapShareNotFound) {
+			return nil, ErrShareNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to get mindmap share: %v", err)
+		return nil, ErrInternalError
+	}
+
+	if share.IsRevoked() {
+		return nil, ErrShareNotFound
+	}
+	if share.IsExpired(time.Now()) {
+		return nil, ErrShareExpired
+	}
+	if share.HasPassword() {
+		if password == "" {
+			return nil, ErrSharePasswordRequired
+		}
+		matched, err := util.ComparePassword(share.PasswordHash, password)
+		if err != nil {
+			zlog.CtxErrorf(ctx, "failed to compare share password: %v", err)
+			return nil, ErrInternalError
+		}
+		if !matched {
+			zlog.CtxWarnf(ctx, "share password incorrect, shareID: %s", share.ShareID)
+			return nil, ErrSharePasswordIncorrect
+		}
+	}
+
+	// 以分享创建者身份读取导图，导图被删除后分享随之失效
+	mindMap, err = s.mindMapRepo.GetMindMap(ctx, repo.NewMindMapQueryByID(share.UserID, share.MapID))
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to get shared mindmap: %v", err)
+		return nil, ErrInternalError
+	}
+	if mindMap == nil {
+		return nil, ErrShareNotFound
+	}
+
+	// 访问计数失败不影响读取
+	if err := s.mindMapRepo.IncrMindMapShareHit(ctx, share.ShareID); err != nil {
+		zlog.CtxWarnf(ctx, "failed to incr mindmap share hit, shareID: %s: %v", share.ShareID, err)
+	}
+
+	zlog.CtxInfof(ctx, "shared mindmap accessed, mapID: %s, shareID: %s", share.MapID, share.ShareID)
+	return mindMap, nil
+}
+
+// generateShareToken 生成URL安全的随机分享令牌
+func generateShareToken() (string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,356 @@
+package mindmapservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"forge/biz/entity"
+	"forge/biz/generationservice"
+	"forge/biz/mindmapservice/layout"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/constant"
+	"forge/pkg/log/zlog"
+	"forge/pkg/loop"
+	"forge/util"
+)
+
+var (
+	ErrTemplateNotFound      = errors.New("模板不存在")
+	ErrTemplateLimitExceeded = errors.New("模板数量已达上限")
+	ErrTemplateFillFailed    = errors.New("模板智能填充失败，请稍后重试")
+)
+
+const (
+	templateNameMaxRunes     = 50
+	templateMaxPerUser       = 50   // 每个用户最多保存的模板数量
+	templateFillTextMaxRunes = 5000 // AI填充时用户文本的最大字符数
+)
+
+// ListMindMapTemplates 获取内置模板及当前用户保存的模板（不包含Data）
+func (s *MindMapServiceImpl) ListMindMapTemplates(ctx context.Context) ([]*entity.MindMapTemplate, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, ErrPermissionDenied
+	}
+
+	userTemplates, err := s.mindMapRepo.ListMindMapTemplates(ctx, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list mindmap templates: %v", err)
+		return nil, ErrInternalError
+	}
+
+	templates := make([]*entity.MindMapTemplate, 0, len(builtinTemplates)+len(userTemplates))
+	for _, template := range builtinTemplates {
+		summary := *template
+		summary.Data = entity.MindMapData{}
+		templates = append(templates, &summary)
+	}
+	templates = append(templates, userTemplates...)
+	return templates, nil
+}
+
+// GetMindMapTemplate 获取模板详情（内置模板或当前用户自己的模板）
+func (s *MindMapServiceImpl) GetMindMapTemplate(ctx context.Context, templateID string) (*entity.MindMapTemplate, error) {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, ErrPermissionDenied
+	}
+	return s.getMindMapTemplate(ctx, user.UserID, templateID)
+}
+
+// SaveMindMapAsTemplate 将导图保存为用户模板（可查看该导图即可保存），节点UID不会保存到模板中
+func (s *MindMapServiceImpl) SaveMindMapAsTemplate(ctx context.Context, req *types.SaveMindMapAsTemplateParams) (template *entity.MindMapTemplate, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.save_mindmap_as_template", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, req, template, err)
+	}()
+
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, ErrPermissionDenied
+	}
+
+	mindMap, _, err := s.getMindMapWithRole(ctx, req.MapID, entity.MindMapRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if strings.TrimSpace(name) == "" {
+		name = mindMap.Title
+	}
+	name, ok = normalizeName(name, templateNameMaxRunes)
+	if !ok {
+		zlog.CtxErrorf(ctx, "invalid template name: %q", req.Name)
+		return nil, ErrInvalidParams
+	}
+
+	count, err := s.mindMapRepo.CountMindMapTemplates(ctx, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to count mindmap templates: %v", err)
+		return nil, ErrInternalError
+	}
+	if count >= templateMaxPerUser {
+		zlog.CtxWarnf(ctx, "mindmap template limit exceeded, userID: %s, count: %d", user.UserID, count)
+		return nil, ErrTemplateLimitExceeded
+	}
+
+	templateID, err := util.GenerateStringID()
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to generate template id: %v", err)
+		return nil, ErrInternalError
+	}
+	data := mindMap.Data.Clone()
+	data.ClearUIDs()
+	template = &entity.MindMapTemplate{
+		TemplateID: templateID,
+		UserID:     user.UserID,
+		Name:       name,
+		Desc:       req.Desc,
+		Category:   entity.TemplateCategoryCustom,
+		Layout:     layout.OrDefault(mindMap.Layout),
+		Data:       data,
+	}
+	if err := s.mindMapRepo.CreateMindMapTemplate(ctx, template); err != nil {
+		zlog.CtxErrorf(ctx, "failed to create mindmap template: %v", err)
+		return nil, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap template saved successfully, templateID: %s, mapID: %s, userID: %s", templateID, req.MapID, user.UserID)
+	return template, nil
+}
+
+// DeleteMindMapTemplate 删除用户自己的模板，内置模板不可删除
+func (s *MindMapServiceImpl) DeleteMindMapTemplate(ctx context.Context, templateID string) error {
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return ErrPermissionDenied
+	}
+	if _, builtin := getBuiltinTemplate(templateID); builtin {
+		return ErrPermissionDenied
+	}
+
+	if err := s.mindMapRepo.DeleteMindMapTemplate(ctx, templateID, user.UserID); err != nil {
+		if errors.Is(err, repo.ErrMindMapTemplateNotFound) {
+			return ErrTemplateNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to delete mindmap template: %v", err)
+		return ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "mindmap template deleted successfully, templateID: %s, userID: %s", templateID, user.UserID)
+	return nil
+}
+
+// CreateMindMapFromTemplate 基于模板创建导图，传入 FillText 时由AI按文本填充占位节点
+func (s *MindMapServiceImpl) CreateMindMapFromTemplate(ctx context.Context, templateID string, req *types.CreateMindMapFromTemplateParams) (mindMap *entity.MindMap, err error) {
+	// 服务层链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "service.create_mindmap_from_template", constant.LoopSpanType_Function)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, req, mindMap, err)
+	}()
+
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, ErrPermissionDenied
+	}
+	if utf8.RuneCountInString(req.FillText) > templateFillTextMaxRunes {
+		zlog.CtxErrorf(ctx, "template fill text too long: %d", utf8.RuneCountInString(req.FillText))
+		return nil, ErrInvalidParams
+	}
+
+	template, err := s.getMindMapTemplate(ctx, user.UserID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := template.Data.Clone()
+	if strings.TrimSpace(req.FillText) != "" && data.HasPlaceholders() {
+		data, err = s.fillTemplatePlaceholders(ctx, user.UserID, template, req.FillText)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data.ResolvePlaceholders()
+	data.ClearUIDs()
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = truncateUTF8(strings.TrimSpace(data.Data.Text), 100)
+	}
+	if title == "" {
+		title = template.Name
+	}
+	layoutType := req.Layout
+	if layoutType == "" {
+		layoutType = layout.OrDefault(template.Layout)
+	}
+
+	mindMap, err = s.CreateMindMap(ctx, &types.CreateMindMapParams{
+		Title:  title,
+		Desc:   req.Desc,
+		Layout: layoutType,
+		Data:   data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zlog.CtxInfof(ctx, "mindmap created from template, templateID: %s, mapID: %s, filled: %t",
+		templateID, mindMap.MapID, req.FillText != "")
+	return mindMap, nil
+}
+
+// getMindMapTemplate 查找内置模板或用户自己的模板（包含Data）
+func (s *MindMapServiceImpl) getMindMapTemplate(ctx context.Context, userID, templateID string) (*entity.MindMapTemplate, error) {
+	if templateID == "" {
+		return nil, ErrInvalidParams
+	}
+	if template, ok := getBuiltinTemplate(templateID); ok {
+		return template, nil
+	}
+
+	template, err := s.mindMapRepo.GetMindMapTemplate(ctx, templateID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrMindMapTemplateNotFound) {
+			zlog.CtxWarnf(ctx, "mindmap template not found: %s", templateID)
+			return nil, ErrTemplateNotFound
+		}
+		zlog.CtxErrorf(ctx, "failed to get mindmap template: %v", err)
+		return nil, ErrInternalError
+	}
+	return template, nil
+}
+
+// fillTemplatePlaceholders 调用AI按用户文本生成导图，再以模板结构为准合并生成结果
+func (s *MindMapServiceImpl) fillTemplatePlaceholders(ctx context.Context, userID string, template *entity.MindMapTemplate, text string) (entity.MindMapData, error) {
+	result, err := s.einoServer.GenerateMindMap(ctx, buildTemplateFillPrompt(&template.Data, text), userID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to generate mindmap for template fill: %v", err)
+		return entity.MindMapData{}, ErrTemplateFillFailed
+	}
+
+	var generated struct {
+		Root json.RawMessage `json:"root"`
+	}
+	if err := json.Unmarshal([]byte(result), &generated); err != nil || len(generated.Root) == 0 || string(generated.Root) == "null" {
+		zlog.CtxErrorf(ctx, "failed to parse generated mindmap for template fill: %v", err)
+		return entity.MindMapData{}, ErrTemplateFillFailed
+	}
+	root, err := generationservice.ParseAIMindMapData(generated.Root)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to parse generated mindmap for template fill: %v", err)
+		return entity.MindMapData{}, ErrTemplateFillFailed
+	}
+
+	return mergeTemplateFill(&template.Data, root), nil
+}
+
+// buildTemplateFillPrompt 将模板结构渲染为缩进大纲，连同用户文本作为生成导图的输入
+func buildTemplateFillPrompt(data *entity.MindMapData, text string) string {
+	var b strings.Builder
+	b.WriteString("请严格按照下面的模板结构，根据用户文本生成思维导图：\n")
+	b.WriteString("1. 模板中的普通节点保持文本与层级不变；\n")
+	b.WriteString("2. 形如{{提示}}的节点是占位节点，请根据用户文本替换为具体内容，一个占位节点可以展开为多个同级节点，也可以补充子节点；\n")
+	b.WriteString("3. 用户文本中没有相关信息的占位节点保持原样。\n")
+	b.WriteString("模板结构：\n")
+	data.Walk(func(node, _ *entity.MindMapData, depth int) bool {
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString("- ")
+		b.WriteString(node.Data.Text)
+		b.WriteString("\n")
+		return true
+	})
+	b.WriteString("用户文本：\n")
+	b.WriteString(text)
+	return b.String()
+}
+
+// mergeTemplateFill 以模板结构为准合并AI生成结果：普通节点保持不变，占位节点取对应生成节点的内容，
+// 占位节点所在层级中多出的生成节点依次追加，保证模板结构不会被AI改写
+func mergeTemplateFill(tpl, generated *entity.MindMapData) entity.MindMapData {
+	node := entity.MindMapData{Data: tpl.Data}
+	_, isPlaceholder := tpl.Data.PlaceholderHint()
+	if isPlaceholder && generated != nil && strings.TrimSpace(generated.Data.Text) != "" {
+		node.Data.Text = generated.Data.Text
+		if node.Data.Note == "" {
+			node.Data.Note = generated.Data.Note
+		}
+		if len(node.Data.Tags) == 0 {
+			node.Data.Tags = generated.Data.Tags
+		}
+	}
+
+	var generatedChildren []entity.MindMapData
+	if generated != nil {
+		generatedChildren = generated.Children
+	}
+	matched, rest := matchTemplateChildren(tpl.Children, generatedChildren)
+	for i := range tpl.Children {
+		node.Children = append(node.Children, mergeTemplateFill(&tpl.Children[i], matched[i]))
+	}
+
+	// 仅在允许展开的位置追加多出的生成节点：自身为占位节点，或子节点中存在占位节点
+	expandable := isPlaceholder
+	for i := range tpl.Children {
+		if _, ok := tpl.Children[i].Data.PlaceholderHint(); ok {
+			expandable = true
+			break
+		}
+	}
+	if expandable {
+		for _, child := range rest {
+			node.Children = append(node.Children, child.Clone())
+		}
+	}
+	return node
+}
+
+// matchTemplateChildren 为模板子节点匹配生成结果中的子节点：普通节点按文本匹配，占位节点依次取剩余节点，返回匹配结果及未匹配的生成节点
+func matchTemplateChildren(tplChildren, generatedChildren []entity.MindMapData) (matched []*entity.MindMapData, rest []*entity.MindMapData) {
+	matched = make([]*entity.MindMapData, len(tplChildren))
+	used := make([]bool, len(generatedChildren))
+
+	for i := range tplChildren {
+		if _, ok := tplChildren[i].Data.PlaceholderHint(); ok {
+			continue
+		}
+		text := strings.TrimSpace(tplChildren[i].Data.Text)
+		for j := range generatedChildren {
+			if !used[j] && strings.TrimSpace(generatedChildren[j].Data.Text) == text {
+				matched[i], used[j] = &generatedChildren[j], true
+				break
+			}
+		}
+	}
+
+	next := 0
+	for i := range tplChildren {
+		if _, ok := tplChildren[i].Data.PlaceholderHint(); !ok {
+			continue
+		}
+		for next < len(generatedChildren) && used[next] {
+			next++
+		}
+		if next < len(generatedChildren) {
+			matched[i], used[next] = &generatedChildren[next], true
+		}
+	}
+
+	for j := range generatedChildren {
+		if !used[j] {
+			rest = append(rest, &generatedChildren[j])
+		}
+	}
+	return matched, rest
+}
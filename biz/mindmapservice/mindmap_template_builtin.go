@@ -0,0 +1,99 @@
+package mindmapservice
+
+import (
+	"forge/biz/entity"
+	"forge/biz/mindmapservice/layout"
+)
+
+// 内置模板ID前缀，用户模板ID为雪花ID，二者不会冲突
+const builtinTemplatePrefix = "builtin_"
+
+// builtinTemplates 内置模板，按展示顺序排列
+var builtinTemplates = []*entity.MindMapTemplate{
+	{
+		TemplateID: builtinTemplatePrefix + "swot",
+		Name:       "SWOT分析",
+		Desc:       "从优势、劣势、机会、威胁四个维度分析对象，并推导应对策略",
+		Category:   entity.TemplateCategoryAnalysis,
+		BuiltIn:    true,
+		Layout:     layout.LayoutMindMap,
+		Data: tplNode("{{分析对象}}",
+			tplNode("优势 Strengths", tplNode("{{内部优势}}")),
+			tplNode("劣势 Weaknesses", tplNode("{{内部劣势}}")),
+			tplNode("机会 Opportunities", tplNode("{{外部机会}}")),
+			tplNode("威胁 Threats", tplNode("{{外部威胁}}")),
+			tplNode("应对策略",
+				tplNode("SO 发挥优势、抓住机会", tplNode("{{SO策略}}")),
+				tplNode("WO 弥补劣势、利用机会", tplNode("{{WO策略}}")),
+				tplNode("ST 利用优势、规避威胁", tplNode("{{ST策略}}")),
+				tplNode("WT 减少劣势、回避威胁", tplNode("{{WT策略}}")),
+			),
+		),
+	},
+	{
+		TemplateID: builtinTemplatePrefix + "book_notes",
+		Name:       "读书笔记",
+		Desc:       "整理一本书的基本信息、核心观点、章节要点与个人收获",
+		Category:   entity.TemplateCategoryReading,
+		BuiltIn:    true,
+		Layout:     layout.LayoutLogicalStructure,
+		Data: tplNode("{{书名}}",
+			tplNode("基本信息", tplNode("{{作者}}"), tplNode("{{出版信息}}")),
+			tplNode("核心观点", tplNode("{{核心观点}}")),
+			tplNode("章节要点", tplNode("{{章节}}", tplNode("{{要点}}"))),
+			tplNode("金句摘录", tplNode("{{摘录}}")),
+			tplNode("思考与启发", tplNode("{{启发}}")),
+			tplNode("行动清单", tplNode("{{行动}}")),
+		),
+	},
+	{
+		TemplateID: builtinTemplatePrefix + "project_plan",
+		Name:       "项目计划",
+		Desc:       "梳理项目目标、范围、里程碑、任务分工与风险",
+		Category:   entity.TemplateCategoryProject,
+		BuiltIn:    true,
+		Layout:     layout.LayoutLogicalStructure,
+		Data: tplNode("{{项目名称}}",
+			tplNode("项目目标", tplNode("{{目标}}"), tplNode("{{成功标准}}")),
+			tplNode("项目范围", tplNode("包含", tplNode("{{包含内容}}")), tplNode("不包含", tplNode("{{不包含内容}}"))),
+			tplNode("里程碑", tplNode("{{里程碑}}")),
+			tplNode("任务分解", tplNode("{{任务}}", tplNode("{{子任务}}"))),
+			tplNode("资源与分工", tplNode("{{成员与职责}}")),
+			tplNode("风险与应对", tplNode("{{风险}}")),
+		),
+	},
+	{
+		TemplateID: builtinTemplatePrefix + "lecture_outline",
+		Name:       "课程大纲",
+		Desc:       "规划一节课的教学目标、重难点、教学过程与课后作业",
+		Category:   entity.TemplateCategoryStudy,
+		BuiltIn:    true,
+		Layout:     layout.LayoutLogicalStructure,
+		Data: tplNode("{{课程主题}}",
+			tplNode("教学目标", tplNode("{{知识目标}}"), tplNode("{{能力目标}}")),
+			tplNode("重点难点", tplNode("重点", tplNode("{{重点}}")), tplNode("难点", tplNode("{{难点}}"))),
+			tplNode("教学过程",
+				tplNode("导入", tplNode("{{导入方式}}")),
+				tplNode("讲授", tplNode("{{知识点}}")),
+				tplNode("练习", tplNode("{{课堂练习}}")),
+				tplNode("小结", tplNode("{{小结要点}}")),
+			),
+			tplNode("课后作业", tplNode("{{作业}}")),
+		),
+	},
+}
+
+// tplNode 构建模板节点
+func tplNode(text string, children ...entity.MindMapData) entity.MindMapData {
+	return entity.MindMapData{Data: entity.NodeData{Text: text}, Children: children}
+}
+
+// getBuiltinTemplate 按ID查找内置模板
+func getBuiltinTemplate(templateID string) (*entity.MindMapTemplate, bool) {
+	for _, template := range builtinTemplates {
+		if template.TemplateID == templateID {
+			return template, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,139 @@
+package mindmapservice
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"forge/biz/entity"
+	"forge/biz/mindmapservice/exporter"
+	"forge/biz/mindmapservice/layout"
+	"forge/pkg/log/zlog"
+)
+
+// 缩略图参数
+const (
+	thumbnailDepth       = 2   // 展示到第几层子节点，更深的分支折叠显示隐藏节点数
+	thumbnailMaxWidth    = 480 // 缩略图最大宽度
+	thumbnailMaxHeight   = 320 // 缩略图最大高度
+	thumbnailContentType = "image/svg+xml"
+
+	thumbnailRetryBackoff = 10 * time.Minute // 生成失败后，列表补生成的重试间隔
+)
+
+// scheduleMindMapThumbnail 异步生成导图缩略图：同一导图同时只有一个生成任务，
+// 生成期间的多次保存合并为一次，只生成最新的快照
+func (s *MindMapServiceImpl) scheduleMindMapThumbnail(mindMap *entity.MindMap) {
+	if s.cosService == nil || mindMap == nil {
+		return
+	}
+
+	s.thumbnailMu.Lock()
+	_, running := s.thumbnailPending[mindMap.MapID]
+	s.thumbnailPending[mindMap.MapID] = mindMap
+	s.thumbnailMu.Unlock()
+
+	if !running {
+		go s.runMindMapThumbnailWorker(mindMap.MapID)
+	}
+}
+
+// backfillMindMapThumbnail 为没有缩略图的导图补生成缩略图，最近生成失败的导图在退避期内不再重试，
+// 避免COS不可用时每次列表请求都重新渲染上传整页导图
+func (s *MindMapServiceImpl) backfillMindMapThumbnail(mindMap *entity.MindMap) {
+	s.thumbnailMu.Lock()
+	failedAt, failed := s.thumbnailFailedAt[mindMap.MapID]
+	s.thumbnailMu.Unlock()
+
+	if failed && time.Since(failedAt) < thumbnailRetryBackoff {
+		return
+	}
+	s.scheduleMindMapThumbnail(mindMap)
+}
+
+// runMindMapThumbnailWorker 依次生成导图待处理的缩略图，直到没有新的快照
+func (s *MindMapServiceImpl) runMindMapThumbnailWorker(mapID string) {
+	for {
+		s.thumbnailMu.Lock()
+		mindMap := s.thumbnailPending[mapID]
+		if mindMap == nil {
+			delete(s.thumbnailPending, mapID)
+			s.thumbnailMu.Unlock()
+			return
+		}
+		s.thumbnailPending[mapID] = nil
+		s.thumbnailMu.Unlock()
+
+		err := s.generateMindMapThumbnail(context.Background(), mindMap)
+		if err != nil {
+			zlog.Warnf("生成导图缩略图失败, mapID: %s, version: %d, err: %v", mapID, mindMap.Version, err)
+		}
+
+		s.thumbnailMu.Lock()
+		if err != nil {
+			s.thumbnailFailedAt[mapID] = time.Now()
+		} else {
+			delete(s.thumbnailFailedAt, mapID)
+		}
+		s.thumbnailMu.Unlock()
+	}
+}
+
+// generateMindMapThumbnail 渲染导图前几层节点并上传，成功后回写缩略图地址
+func (s *MindMapServiceImpl) generateMindMapThumbnail(ctx context.Context, mindMap *entity.MindMap) (err error) {
+	// 渲染异常不能影响服务
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("render thumbnail panic: %v", r)
+		}
+	}()
+
+	data := mindMap.Data.Clone()
+	foldBelowDepth(&data, thumbnailDepth)
+	svg := exporter.RenderThumbnailSVG(layout.Compute(&data, mindMap.Layout), thumbnailMaxWidth, thumbnailMaxHeight)
+
+	// 每个导图只保留一个缩略图文件，地址带上版本号避免CDN缓存旧图
+	thumbnailURL, err := s.cosService.UploadFile(ctx, mindMapThumbnailPath(mindMap.MapID), svg, thumbnailContentType)
+	if err != nil {
+		return fmt.Errorf("upload thumbnail failed: %w", err)
+	}
+	thumbnailURL = fmt.Sprintf("%s?v=%d", thumbnailURL, mindMap.Version)
+
+	return s.mindMapRepo.UpdateMindMapThumbnail(ctx, mindMap.MapID, thumbnailURL, mindMap.Version)
+}
+
+// deleteMindMapThumbnails 删除已彻底删除的导图的缩略图文件，失败时仅记录日志
+func (s *MindMapServiceImpl) deleteMindMapThumbnails(ctx context.Context, mapIDs []string) {
+	if s.cosService == nil {
+		return
+	}
+
+	s.thumbnailMu.Lock()
+	for _, mapID := range mapIDs {
+		delete(s.thumbnailFailedAt, mapID)
+	}
+	s.thumbnailMu.Unlock()
+
+	for _, mapID := range mapIDs {
+		if err := s.cosService.DeleteFile(ctx, mindMapThumbnailPath(mapID)); err != nil {
+			zlog.CtxWarnf(ctx, "failed to delete mindmap thumbnail, mapID: %s, err: %v", mapID, err)
+		}
+	}
+}
+
+func mindMapThumbnailPath(mapID string) string {
+	return path.Join("mindmap", mapID, "thumbnail.svg")
+}
+
+// foldBelowDepth 折叠第 depth 层有子节点的节点，排版时只保留前几层
+func foldBelowDepth(node *entity.MindMapData, depth int) {
+	if depth == 0 {
+		node.Data.Collapsed = len(node.Children) > 0
+		return
+	}
+	node.Data.Collapsed = false
+	for i := range node.Children {
+		foldBelowDepth(&node.Children[i], depth-1)
+	}
+}
@@ -0,0 +1,173 @@
+package mindmapservice
+
+import (
+	"context"
+	"time"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/biz/types"
+	"forge/pkg/log/zlog"
+)
+
+// purgeBatchSize 后台清理任务每批处理的导图数量
+const purgeBatchSize = 100
+
+// ListTrashedMindMaps 获取当前用户回收站中的导图列表（按删除时间倒序）
+func (s *MindMapServiceImpl) ListTrashedMindMaps(ctx context.Context, req *types.ListTrashedMindMapsParams) ([]*entity.MindMap, int64, error) {
+	// 从JWT token上下文中获取用户信息
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, 0, ErrPermissionDenied
+	}
+
+	// 复用列表分页的默认值与上限
+	query := repo.NewMindMapQueryForList(user.UserID, req.Page, req.PageSize)
+
+	mindMaps, total, err := s.mindMapRepo.ListTrashedMindMaps(ctx, user.UserID, query.Page, query.PageSize)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to list trashed mindmaps: %v", err)
+		return nil, 0, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "trashed mindmaps listed successfully, userID: %s, count: %d, total: %d", user.UserID, len(mindMaps), total)
+	return mindMaps, total, nil
+}
+
+// RestoreMindMaps 从回收站批量恢复导图（用户只能恢复自己的导图）
+func (s *MindMapServiceImpl) RestoreMindMaps(ctx context.Context, mapIDs []string) (restoredCount int, failedMapIDs []string, err error) {
+	user, validMapIDs, failedMapIDs, err := s.partitionTrashedMindMapIDs(ctx, mapIDs)
+	if err != nil || len(validMapIDs) == 0 {
+		return 0, failedMapIDs, err
+	}
+
+	restoredCount, err = s.mindMapRepo.RestoreMindMaps(ctx, validMapIDs, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to restore mindmaps: %v", err)
+		return 0, failedMapIDs, ErrInternalError
+	}
+
+	zlog.CtxInfof(ctx, "restore mindmaps completed, userID: %s, requested: %d, restored: %d, failed: %d",
+		user.UserID, len(mapIDs), restoredCount, len(failedMapIDs))
+	return restoredCount, failedMapIDs, nil
+}
+
+// PurgeMindMaps 从回收站彻底删除导图，同时清除其历史版本、分享链接、协作者和AI会话（用户只能清除自己的导图）
+func (s *MindMapServiceImpl) PurgeMindMaps(ctx context.Context, mapIDs []string) (purgedCount int, failedMapIDs []string, err error) {
+	user, validMapIDs, failedMapIDs, err := s.partitionTrashedMindMapIDs(ctx, mapIDs)
+	if err != nil || len(validMapIDs) == 0 {
+		return 0, failedMapIDs, err
+	}
+
+	purgedCount, err = s.mindMapRepo.PurgeMindMaps(ctx, validMapIDs)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to purge mindmaps: %v", err)
+		return 0, failedMapIDs, ErrInternalError
+	}
+	s.deleteMindMapThumbnails(ctx, validMapIDs)
+
+	zlog.CtxInfof(ctx, "purge mindmaps completed, userID: %s, requested: %d, purged: %d, failed: %d",
+		user.UserID, len(mapIDs), purgedCount, len(failedMapIDs))
+	return purgedCount, failedMapIDs, nil
+}
+
+// PurgeExpiredMindMaps 彻底删除在回收站中超过保留期的导图，返回清除数量
+func (s *MindMapServiceImpl) PurgeExpiredMindMaps(ctx context.Context, retention time.Duration) (int, error) {
+	deletedBefore := time.Now().Add(-retention)
+	total := 0
+	for {
+		mapIDs, err := s.mindMapRepo.ListExpiredTrashedMindMapIDs(ctx, deletedBefore, purgeBatchSize)
+		if err != nil {
+			zlog.CtxErrorf(ctx, "failed to list expired trashed mindmaps: %v", err)
+			return total, ErrInternalError
+		}
+		if len(mapIDs) == 0 {
+			return total, nil
+		}
+
+		purgedCount, err := s.mindMapRepo.PurgeMindMaps(ctx, mapIDs)
+		if err != nil {
+			zlog.CtxErrorf(ctx, "failed to purge expired mindmaps: %v", err)
+			return total, ErrInternalError
+		}
+		total += purgedCount
+		s.deleteMindMapThumbnails(ctx, mapIDs)
+
+		if len(mapIDs) < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// StartTrashPurger 启动回收站后台清理任务，每隔 interval 清除超过 retention 的导图
+// 多实例同时执行时删除操作是幂等的，无需加锁
+func (s *MindMapServiceImpl) StartTrashPurger(retention, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			purgedCount, err := s.PurgeExpiredMindMaps(context.Background(), retention)
+			if err != nil {
+				zlog.Errorf("回收站清理任务执行失败: %v", err)
+				continue
+			}
+			if purgedCount > 0 {
+				zlog.Infof("回收站清理任务完成，清除导图 %d 个", purgedCount)
+			}
+		}
+	}()
+
+	zlog.Infof("回收站清理任务启动，保留时长: %s, 执行间隔: %s", retention, interval)
+}
+
+// partitionTrashedMindMapIDs 去重并将mapIDs划分为位于当前用户回收站中的和其余的
+func (s *MindMapServiceImpl) partitionTrashedMindMapIDs(ctx context.Context, mapIDs []string) (user *entity.User, validMapIDs, failedMapIDs []string, err error) {
+	// 从JWT token上下文中获取用户信息
+	user, ok := entity.GetUser(ctx)
+	if !ok {
+		zlog.CtxErrorf(ctx, "failed to get user from context")
+		return nil, nil, nil, ErrPermissionDenied
+	}
+
+	// 去重
+	seen := make(map[string]bool)
+	dedupedMapIDs := make([]string, 0, len(mapIDs))
+	for _, id := range mapIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		dedupedMapIDs = append(dedupedMapIDs, id)
+	}
+	if len(dedupedMapIDs) == 0 {
+		zlog.CtxErrorf(ctx, "no valid mapIDs after deduplication")
+		return nil, nil, nil, ErrInvalidParams
+	}
+
+	// 权限验证：只处理当前用户回收站中的导图
+	trashedMindMaps, err := s.mindMapRepo.BatchGetTrashedMindMapsByIDs(ctx, dedupedMapIDs, user.UserID)
+	if err != nil {
+		zlog.CtxErrorf(ctx, "failed to batch get trashed mindmaps for permission check: %v", err)
+		return nil, nil, nil, ErrInternalError
+	}
+	trashedMapIDs := make(map[string]bool, len(trashedMindMaps))
+	for _, mindMap := range trashedMindMaps {
+		trashedMapIDs[mindMap.MapID] = true
+	}
+
+	validMapIDs = make([]string, 0, len(dedupedMapIDs))
+	failedMapIDs = make([]string, 0)
+	for _, mapID := range dedupedMapIDs {
+		if trashedMapIDs[mapID] {
+			validMapIDs = append(validMapIDs, mapID)
+		} else {
+			failedMapIDs = append(failedMapIDs, mapID)
+		}
+	}
+	if len(validMapIDs) == 0 {
+		zlog.CtxWarnf(ctx, "no valid trashed mapIDs belong to user %s", user.UserID)
+	}
+	return user, validMapIDs, failedMapIDs, nil
+}
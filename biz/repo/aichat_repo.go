@@ -30,12 +30,27 @@ type AiChatRepo interface {
 
 	//更新特定消息的质量评分
 	UpdateMessageQuality(ctx context.Context, conversationID string, messageID string, qualityScore int) error
+
+	//保存导图修改建议，同一会话中仍待处理的旧建议标记为已失效
+	CreateMapProposal(ctx context.Context, proposal *entity.MapProposal) error
+
+	//获取某个导图修改建议
+	GetMapProposal(ctx context.Context, proposalID, userID string) (*entity.MapProposal, error)
+
+	//获取某个会话的所有导图修改建议（按创建时间倒序）
+	ListMapProposals(ctx context.Context, conversationID, userID string) ([]*entity.MapProposal, error)
+
+	//写入导图修改建议的处理结果，建议已被处理时返回错误
+	ResolveMapProposal(ctx context.Context, proposal *entity.MapProposal) error
 }
 
 type EinoServer interface {
 	//向ai发送消息
 	SendMessage(ctx context.Context, messages []*entity.Message) (types.AgentResponse, error)
-	SendMessageStream(ctx context.Context, messages []*entity.Message) (<-chan types.StreamChunk, error)
+	//流式向ai发送消息，与SendMessage执行同一个agent，过程中的事件通过onChunk回调
+	SendMessageStream(ctx context.Context, messages []*entity.Message, onChunk func(chunk types.StreamChunk) error) (types.AgentResponse, error)
+	//将较早的对话与之前的摘要合并为新的摘要
+	SummarizeMessages(ctx context.Context, summary string, messages []*entity.Message) (string, error)
 	//生成导图
 	GenerateMindMap(ctx context.Context, text, userID string) (string, error)
 
@@ -4,11 +4,22 @@ import (
 	"context"
 	"errors"
 	"forge/biz/entity"
+	"time"
 )
 
 // 哨兵错误定义
 var (
-	ErrMindMapNotFound = errors.New("mindmap not found or no permission")
+	ErrMindMapNotFound             = errors.New("mindmap not found or no permission")
+	ErrMindMapVersionConflict      = errors.New("mindmap version conflict")
+	ErrMindMapRevisionNotFound     = errors.New("mindmap revision not found")
+	ErrMindMapShareNotFound        = errors.New("mindmap share not found")
+	ErrMindMapCollaboratorNotFound = errors.New("mindmap collaborator not found")
+	ErrMindMapFolderNotFound       = errors.New("mindmap folder not found")
+	ErrMindMapTagNotFound          = errors.New("mindmap tag not found")
+	ErrMindMapTagNameExists        = errors.New("mindmap tag name already exists")
+	ErrMindMapTemplateNotFound     = errors.New("mindmap template not found")
+	ErrMindMapNodeLinkNotFound     = errors.New("mindmap node link not found")
+	ErrMindMapNodeLinkExists       = errors.New("mindmap node link already exists")
 )
 
 // IMindMapRepo 思维导图仓储接口
@@ -16,31 +27,161 @@ type IMindMapRepo interface {
 	CreateMindMap(ctx context.Context, mindmap *entity.MindMap) error
 	GetMindMap(ctx context.Context, query MindMapQuery) (*entity.MindMap, error)
 	ListMindMaps(ctx context.Context, query MindMapQuery) ([]*entity.MindMap, int64, error)
-	UpdateMindMap(ctx context.Context, updateInfo *MindMapUpdateInfo) error
+	// UpdateMindMap 更新思维导图并写入一条历史版本，返回更新后的版本号（权限由服务层校验）
+	UpdateMindMap(ctx context.Context, updateInfo *MindMapUpdateInfo) (newVersion int64, err error)
+	// UpdateMindMapThumbnail 写入缩略图地址，不改变版本号与更新时间；已有更新版本的缩略图时忽略
+	UpdateMindMapThumbnail(ctx context.Context, mapID, thumbnailURL string, version int64) error
 	DeleteMindMap(ctx context.Context, mapID string, userID string) error
 	BatchDeleteMindMap(ctx context.Context, mapIDs []string, userID string) (deletedCount int, err error)
 	// BatchGetMindMapsByIDs 批量查询指定mapIDs且属于指定用户的思维导图（用于权限验证）
 	BatchGetMindMapsByIDs(ctx context.Context, mapIDs []string, userID string) ([]*entity.MindMap, error)
+
+	// ListTrashedMindMaps 分页查询用户回收站中的导图（按删除时间倒序）
+	ListTrashedMindMaps(ctx context.Context, userID string, page, pageSize int) ([]*entity.MindMap, int64, error)
+	// BatchGetTrashedMindMapsByIDs 批量查询指定mapIDs中位于该用户回收站的导图（用于权限验证）
+	BatchGetTrashedMindMapsByIDs(ctx context.Context, mapIDs []string, userID string) ([]*entity.MindMap, error)
+	// RestoreMindMaps 将回收站中的导图恢复，返回恢复数量
+	RestoreMindMaps(ctx context.Context, mapIDs []string, userID string) (restoredCount int, err error)
+	// PurgeMindMaps 彻底删除回收站中的导图及其历史版本、分享链接、协作者和AI会话，返回清除数量（权限由服务层校验）
+	PurgeMindMaps(ctx context.Context, mapIDs []string) (purgedCount int, err error)
+	// ListExpiredTrashedMindMapIDs 查询删除时间早于 deletedBefore 的回收站导图ID，最多返回 limit 条
+	ListExpiredTrashedMindMapIDs(ctx context.Context, deletedBefore time.Time, limit int) ([]string, error)
+
+	// CreateMindMapFolder 创建文件夹
+	CreateMindMapFolder(ctx context.Context, folder *entity.MindMapFolder) error
+	// ListMindMapFolders 查询用户的全部文件夹（按名称排序，由调用方组装树结构）
+	ListMindMapFolders(ctx context.Context, userID string) ([]*entity.MindMapFolder, error)
+	// UpdateMindMapFolder 修改文件夹名称及父文件夹，文件夹不存在返回 ErrMindMapFolderNotFound
+	UpdateMindMapFolder(ctx context.Context, folder *entity.MindMapFolder) error
+	// DeleteMindMapFolders 删除文件夹，其中的导图移出为未归档，返回被移出的导图数量
+	DeleteMindMapFolders(ctx context.Context, folderIDs []string, userID string) (movedCount int, err error)
+	// MoveMindMapsToFolder 将用户自己的导图移动到文件夹（folderID 为空表示移出文件夹），返回移动数量
+	MoveMindMapsToFolder(ctx context.Context, mapIDs []string, userID, folderID string) (movedCount int, err error)
+
+	// CreateMindMapTag 创建标签，同名标签已存在返回 ErrMindMapTagNameExists
+	CreateMindMapTag(ctx context.Context, tag *entity.MindMapTag) error
+	// ListMindMapTags 查询用户的全部标签（按名称排序）
+	ListMindMapTags(ctx context.Context, userID string) ([]*entity.MindMapTag, error)
+	// UpdateMindMapTag 修改标签名称与颜色，标签不存在返回 ErrMindMapTagNotFound，同名标签已存在返回 ErrMindMapTagNameExists
+	UpdateMindMapTag(ctx context.Context, tag *entity.MindMapTag) error
+	// DeleteMindMapTag 删除标签及其与导图的关联
+	DeleteMindMapTag(ctx context.Context, tagID, userID string) error
+	// BatchGetMindMapTagsByIDs 批量查询指定tagIDs且属于指定用户的标签（用于权限验证）
+	BatchGetMindMapTagsByIDs(ctx context.Context, tagIDs []string, userID string) ([]*entity.MindMapTag, error)
+	// AddMindMapTags 为导图批量添加标签，已存在的关联忽略，返回新增关联数量（权限由服务层校验）
+	AddMindMapTags(ctx context.Context, mapIDs, tagIDs []string) (addedCount int, err error)
+	// RemoveMindMapTags 批量移除导图的标签，返回移除的关联数量（权限由服务层校验）
+	RemoveMindMapTags(ctx context.Context, mapIDs, tagIDs []string) (removedCount int, err error)
+
+	// CreateMindMapTemplate 保存用户模板
+	CreateMindMapTemplate(ctx context.Context, template *entity.MindMapTemplate) error
+	// GetMindMapTemplate 获取用户自己的模板（包含Data），不存在返回 ErrMindMapTemplateNotFound
+	GetMindMapTemplate(ctx context.Context, templateID, userID string) (*entity.MindMapTemplate, error)
+	// ListMindMapTemplates 查询用户的全部模板（按创建时间倒序，不包含Data）
+	ListMindMapTemplates(ctx context.Context, userID string) ([]*entity.MindMapTemplate, error)
+	// CountMindMapTemplates 统计用户的模板数量
+	CountMindMapTemplates(ctx context.Context, userID string) (int64, error)
+	// DeleteMindMapTemplate 删除用户模板，不存在返回 ErrMindMapTemplateNotFound
+	DeleteMindMapTemplate(ctx context.Context, templateID, userID string) error
+
+	// CreateMindMapNodeLink 创建节点链接，同一源节点到同一目标的链接已存在返回 ErrMindMapNodeLinkExists
+	CreateMindMapNodeLink(ctx context.Context, link *entity.MindMapNodeLink) error
+	// GetMindMapNodeLink 获取节点链接，不存在返回 ErrMindMapNodeLinkNotFound
+	GetMindMapNodeLink(ctx context.Context, linkID string) (*entity.MindMapNodeLink, error)
+	// DeleteMindMapNodeLink 删除节点链接，不存在返回 ErrMindMapNodeLinkNotFound
+	DeleteMindMapNodeLink(ctx context.Context, linkID string) error
+	// ListMindMapNodeLinks 查询导图（或其中某个节点）发出的链接，并填充用户可见的目标导图标题与节点文本
+	ListMindMapNodeLinks(ctx context.Context, query MindMapNodeLinkQuery) ([]*entity.MindMapNodeLink, error)
+	// ListMindMapBacklinks 查询指向导图（或其中某个节点）的链接，只返回来自用户可访问且未删除导图的链接，并填充源导图标题与节点文本
+	ListMindMapBacklinks(ctx context.Context, query MindMapNodeLinkQuery) ([]*entity.MindMapNodeLink, error)
+
+	// SearchMindMapNodes 在用户可访问（自己创建或参与协作）的导图中检索同时包含全部词元的文档，按命中词频降序返回
+	SearchMindMapNodes(ctx context.Context, query MindMapSearchQuery) (*MindMapSearchCandidates, error)
+	// BatchGetMindMapSummaries 批量查询未删除导图的基本信息（不含Data，不校验权限）
+	BatchGetMindMapSummaries(ctx context.Context, mapIDs []string) ([]*entity.MindMap, error)
+
+	// ListMindMapRevisions 分页查询导图历史版本（按版本号倒序，不包含Data）
+	ListMindMapRevisions(ctx context.Context, mapID string, page, pageSize int) ([]*entity.MindMapRevision, int64, error)
+	// GetMindMapRevision 获取导图指定版本的完整快照
+	GetMindMapRevision(ctx context.Context, mapID string, version int64) (*entity.MindMapRevision, error)
+
+	// CreateMindMapShare 创建分享链接
+	CreateMindMapShare(ctx context.Context, share *entity.MindMapShare) error
+	// ListMindMapShares 查询导图的全部分享链接（按创建时间倒序，包含已撤销的）
+	ListMindMapShares(ctx context.Context, mapID string, userID string) ([]*entity.MindMapShare, error)
+	// GetMindMapShareByToken 根据分享令牌查询分享链接
+	GetMindMapShareByToken(ctx context.Context, token string) (*entity.MindMapShare, error)
+	// RevokeMindMapShare 撤销分享链接（保留记录用于统计）
+	RevokeMindMapShare(ctx context.Context, mapID, shareID, userID string) error
+	// IncrMindMapShareHit 分享链接访问计数加一
+	IncrMindMapShareHit(ctx context.Context, shareID string) error
+
+	// GetMindMapRole 查询用户在导图上的角色：所有者返回 owner，协作者返回其角色，无权限返回空字符串；导图不存在返回 ErrMindMapNotFound
+	GetMindMapRole(ctx context.Context, mapID string, userID string) (string, error)
+	// UpsertMindMapCollaborator 添加协作者，已存在时更新角色
+	UpsertMindMapCollaborator(ctx context.Context, collaborator *entity.MindMapCollaborator) error
+	// ListMindMapCollaborators 查询导图的全部协作者（按加入时间正序）
+	ListMindMapCollaborators(ctx context.Context, mapID string) ([]*entity.MindMapCollaborator, error)
+	// RemoveMindMapCollaborator 移除协作者
+	RemoveMindMapCollaborator(ctx context.Context, mapID string, userID string) error
 }
 
+// 列表查询范围
+const (
+	MindMapScopeOwned  = "owned"  // 自己创建的导图（默认）
+	MindMapScopeShared = "shared" // 作为协作者参与的导图
+	MindMapScopeAll    = "all"    // 以上两者
+)
+
+// MindMapFolderUnfiled 查询未归档（不在任何文件夹中）的导图
+const MindMapFolderUnfiled = "unfiled"
+
 // MindMapQuery 查询条件
 type MindMapQuery struct {
-	UserID   string // 用户ID（必填）
-	MapID    string // 思维导图ID
-	Title    string // 标题关键词（模糊查询）
-	Layout   string // 布局类型
-	Page     int    // 页码（从1开始）
-	PageSize int    // 每页大小（最大99）
+	UserID   string   // 用户ID（列表查询必填；单个查询时不为空则校验归属）
+	MapID    string   // 思维导图ID
+	Scope    string   // 列表查询范围，见 MindMapScope* 常量，为空时等同 owned
+	Title    string   // 标题关键词（模糊查询）
+	Layout   string   // 布局类型
+	FolderID string   // 所属文件夹ID，MindMapFolderUnfiled 表示未归档的导图，为空时不筛选
+	TagIDs   []string // 标签ID，导图需包含全部标签
+	Page     int      // 页码（从1开始）
+	PageSize int      // 每页大小（最大99）
+}
+
+// MindMapSearchQuery 全文检索条件
+type MindMapSearchQuery struct {
+	UserID string   // 检索用户ID（必填）
+	Tokens []string // 查询词元（必填，已去重），文档需包含全部词元
+	Limit  int      // 最多返回的候选文档数量
+}
+
+// MindMapSearchCandidates 全文检索候选结果及打分所需的统计信息
+type MindMapSearchCandidates struct {
+	Docs      []*entity.MindMapNodeDoc
+	DocFreq   map[string]int64 // 各查询词元出现的文档数
+	TotalDocs int64            // 索引中的文档总数
+}
+
+// MindMapNodeLinkQuery 节点链接查询条件
+type MindMapNodeLinkQuery struct {
+	UserID  string // 查询用户ID（必填），用于判断关联导图是否可见
+	MapID   string // 出链查询为源导图ID，反链查询为目标导图ID（必填）
+	NodeUID string // 节点UID，为空表示整个导图
+	Limit   int    // 最多返回的链接数量
 }
 
 // MindMapUpdateInfo 更新信息（部分更新）
 type MindMapUpdateInfo struct {
 	MapID  string              // 思维导图ID（必填）
-	UserID string              // 用户ID（用于权限验证）
+	UserID string              // 操作用户ID（必填，记录到历史版本）
 	Title  *string             // 标题
 	Desc   *string             // 描述
 	Layout *string             // 布局
 	Data   *entity.MindMapData // 数据（全量更新）
+
+	ExpectedVersion *int64 // 期望的当前版本号（乐观锁），为空时不校验
+	Source          string // 历史版本来源，为空时记为 update
 }
 
 // 查询构建函数
@@ -52,6 +193,11 @@ func NewMindMapQueryByID(userID, mapID string) MindMapQuery {
 	return MindMapQuery{UserID: userID, MapID: mapID}
 }
 
+// NewMindMapQueryByMapID 仅按导图ID查询，调用方需自行完成权限校验
+func NewMindMapQueryByMapID(mapID string) MindMapQuery {
+	return MindMapQuery{MapID: mapID}
+}
+
 func NewMindMapQueryForList(userID string, page, pageSize int) MindMapQuery {
 	if page <= 0 {
 		page = 1
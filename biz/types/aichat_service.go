@@ -28,6 +28,9 @@ type IAiChatService interface {
 	//删除某会话
 	DelConversation(ctx context.Context, req *DelConversationParams) error
 
+	//切换会话的当前分支
+	SwitchMessageBranch(ctx context.Context, req *SwitchMessageBranchParams) (*entity.Conversation, error)
+
 	//获取某会话的详细信息
 	GetConversation(ctx context.Context, req *GetConversationParams) (*entity.Conversation, error)
 
@@ -48,12 +51,29 @@ type IAiChatService interface {
 
 	//手动触发质量评估
 	TriggerQualityAssessment(ctx context.Context, date string) (int, int, int, error)
+
+	//获取某会话的导图修改建议
+	ListMapProposals(ctx context.Context, req *ListMapProposalsParams) ([]*entity.MapProposal, error)
+
+	//采纳导图修改建议并写入导图
+	AcceptMapProposal(ctx context.Context, req *ResolveMapProposalParams) (*entity.MapProposal, error)
+
+	//拒绝导图修改建议
+	RejectMapProposal(ctx context.Context, req *ResolveMapProposalParams) (*entity.MapProposal, error)
 }
 
 type ProcessUserMessageParams struct {
 	ConversationID string
 	Message        string
 	MapData        string
+	EditMessageID  string // 编辑该用户消息并从此处重新生成，原消息及其后续回复保留为兄弟分支
+	Regenerate     bool   // 重新生成当前分支最后一轮回复，忽略 Message
+}
+
+// SwitchMessageBranchParams 切换会话分支参数
+type SwitchMessageBranchParams struct {
+	ConversationID string
+	MessageID      string // 切换到该消息所在的分支
 }
 
 type SaveNewConversationParams struct {
@@ -81,6 +101,7 @@ type UpdateConversationTitleParams struct {
 
 type AgentResponse struct {
 	NewMapJson string            `json:"new_map_json"`
+	ProposalID string            `json:"proposal_id"` // NewMapJson 对应的待处理修改建议，由服务层填充
 	Content    string            `json:"content"`
 	ToolCallID string            `json:"tool_call_id"`
 	ToolCalls  []schema.ToolCall `json:"tool_calls"`
@@ -113,3 +134,14 @@ type ExportQualityDataParams struct {
 	EndDate   *string
 	Limit     int
 }
+
+// ListMapProposalsParams 获取导图修改建议参数
+type ListMapProposalsParams struct {
+	ConversationID string
+}
+
+// ResolveMapProposalParams 处理导图修改建议参数
+type ResolveMapProposalParams struct {
+	ProposalID string
+	Reason     string // 拒绝原因，可选
+}
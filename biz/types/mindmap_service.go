@@ -3,29 +3,129 @@ package types
 import (
 	"context"
 	"forge/biz/entity"
+	"forge/biz/mindmapservice/layout"
+	"mime/multipart"
+	"time"
 )
 
 type IMindMapService interface {
 	CreateMindMap(ctx context.Context, req *CreateMindMapParams) (*entity.MindMap, error)
 	GetMindMap(ctx context.Context, mapID string) (*entity.MindMap, error)
 	ListMindMaps(ctx context.Context, req *ListMindMapsParams) ([]*entity.MindMap, int64, error)
-	UpdateMindMap(ctx context.Context, mapID string, req *UpdateMindMapParams) error
+	UpdateMindMap(ctx context.Context, mapID string, req *UpdateMindMapParams) (newVersion int64, err error)
 	DeleteMindMap(ctx context.Context, mapID string) error
 	BatchDeleteMindMap(ctx context.Context, mapIDs []string) (deletedCount int, failedMapIDs []string, err error)
+	PatchMindMap(ctx context.Context, mapID string, req *PatchMindMapParams) (newVersion int64, addedNodeIDs []string, err error)
+	ExportMindMap(ctx context.Context, mapID string, format string) (*ExportedFile, error)
+	// ComputeMindMapLayout 按布局计算导图节点坐标、连线及画布范围，layoutType 为空时使用导图自身的布局
+	ComputeMindMapLayout(ctx context.Context, mapID, layoutType string) (result *layout.Result, version int64, err error)
+	ImportMindMap(ctx context.Context, req *ImportMindMapParams) (*entity.MindMap, error)
+
+	// DuplicateMindMap 复制导图（全部节点生成新UID），可查看即可复制，副本归当前用户所有
+	DuplicateMindMap(ctx context.Context, mapID string, req *DuplicateMindMapParams) (*entity.MindMap, error)
+	// ExtractMindMapSubtree 将节点及其子树提取为新导图，可选在原导图中以指向新导图的链接节点替换
+	ExtractMindMapSubtree(ctx context.Context, mapID string, req *ExtractMindMapSubtreeParams) (*ExtractMindMapSubtreeResult, error)
+	// GraftMindMap 将另一导图整体作为子树插入到当前导图的指定节点下，返回新版本号及插入子树的根节点UID
+	GraftMindMap(ctx context.Context, mapID string, req *GraftMindMapParams) (newVersion int64, rootNodeID string, err error)
+
+	// 回收站
+	ListTrashedMindMaps(ctx context.Context, req *ListTrashedMindMapsParams) ([]*entity.MindMap, int64, error)
+	RestoreMindMaps(ctx context.Context, mapIDs []string) (restoredCount int, failedMapIDs []string, err error)
+	// PurgeMindMaps 彻底删除回收站中的导图（不可恢复）
+	PurgeMindMaps(ctx context.Context, mapIDs []string) (purgedCount int, failedMapIDs []string, err error)
+
+	// 文件夹
+	CreateMindMapFolder(ctx context.Context, req *CreateMindMapFolderParams) (*entity.MindMapFolder, error)
+	ListMindMapFolders(ctx context.Context) ([]*entity.MindMapFolder, error)
+	UpdateMindMapFolder(ctx context.Context, folderID string, req *UpdateMindMapFolderParams) (*entity.MindMapFolder, error)
+	// DeleteMindMapFolder 删除文件夹及其全部子文件夹，其中的导图移出为未归档
+	DeleteMindMapFolder(ctx context.Context, folderID string) (movedCount int, err error)
+	// MoveMindMapsToFolder 批量移动导图到文件夹，folderID 为空表示移出文件夹
+	MoveMindMapsToFolder(ctx context.Context, mapIDs []string, folderID string) (movedCount int, failedMapIDs []string, err error)
+
+	// 标签
+	CreateMindMapTag(ctx context.Context, req *CreateMindMapTagParams) (*entity.MindMapTag, error)
+	ListMindMapTags(ctx context.Context) ([]*entity.MindMapTag, error)
+	UpdateMindMapTag(ctx context.Context, tagID string, req *UpdateMindMapTagParams) (*entity.MindMapTag, error)
+	DeleteMindMapTag(ctx context.Context, tagID string) error
+	// TagMindMaps 为导图批量添加标签，返回新增的关联数量
+	TagMindMaps(ctx context.Context, mapIDs, tagIDs []string) (affectedCount int, failedMapIDs []string, err error)
+	// UntagMindMaps 批量移除导图的标签，返回移除的关联数量
+	UntagMindMaps(ctx context.Context, mapIDs, tagIDs []string) (affectedCount int, failedMapIDs []string, err error)
+
+	// 模板
+	// ListMindMapTemplates 获取内置模板及当前用户保存的模板（不包含Data）
+	ListMindMapTemplates(ctx context.Context) ([]*entity.MindMapTemplate, error)
+	GetMindMapTemplate(ctx context.Context, templateID string) (*entity.MindMapTemplate, error)
+	// SaveMindMapAsTemplate 将已有导图保存为用户模板
+	SaveMindMapAsTemplate(ctx context.Context, req *SaveMindMapAsTemplateParams) (*entity.MindMapTemplate, error)
+	DeleteMindMapTemplate(ctx context.Context, templateID string) error
+	// CreateMindMapFromTemplate 基于模板创建导图，传入 FillText 时由AI按文本填充占位节点
+	CreateMindMapFromTemplate(ctx context.Context, templateID string, req *CreateMindMapFromTemplateParams) (*entity.MindMap, error)
+
+	// 跨导图节点链接
+	// CreateMindMapNodeLink 在导图节点上创建指向另一导图（或其中节点）的链接
+	CreateMindMapNodeLink(ctx context.Context, mapID string, req *CreateMindMapNodeLinkParams) (*entity.MindMapNodeLink, error)
+	// ListMindMapNodeLinks 获取导图发出的链接，nodeID 不为空时只返回该节点的链接
+	ListMindMapNodeLinks(ctx context.Context, mapID, nodeID string) ([]*entity.MindMapNodeLink, error)
+	DeleteMindMapNodeLink(ctx context.Context, mapID, linkID string) error
+	// ListMindMapBacklinks 获取指向导图（或其中节点）的反向链接
+	ListMindMapBacklinks(ctx context.Context, mapID, nodeID string) ([]*entity.MindMapNodeLink, error)
+
+	// LintMindMap 检查导图结构与文本问题（空节点、占位符、同级重复、文本过长、分支失衡、层级过深等），问题定位到节点路径
+	LintMindMap(ctx context.Context, mapID string) (*entity.MindMapLintReport, error)
+
+	// SearchMindMaps 在用户可访问的导图中全文检索标题与节点文本，按相关度返回导图及命中节点
+	SearchMindMaps(ctx context.Context, req *SearchMindMapsParams) ([]*MindMapSearchResult, int64, error)
+
+	// 历史版本
+	ListMindMapRevisions(ctx context.Context, mapID string, req *ListMindMapRevisionsParams) ([]*entity.MindMapRevision, int64, error)
+	GetMindMapRevision(ctx context.Context, mapID string, version int64) (*entity.MindMapRevision, error)
+	RestoreMindMapRevision(ctx context.Context, mapID string, req *RestoreMindMapRevisionParams) (newVersion int64, err error)
+	// DiffMindMapRevisions 比较同一导图两个版本之间的结构差异，版本号为0表示当前版本
+	DiffMindMapRevisions(ctx context.Context, mapID string, fromVersion, toVersion int64) (*entity.MindMapDiff, error)
+	// DiffMindMapWithData 比较导图指定版本（为0表示当前版本）与任意节点树之间的结构差异
+	DiffMindMapWithData(ctx context.Context, mapID string, fromVersion int64, data *entity.MindMapData) (*entity.MindMapDiff, error)
+
+	// 分享链接
+	CreateMindMapShare(ctx context.Context, mapID string, req *CreateMindMapShareParams) (*entity.MindMapShare, error)
+	ListMindMapShares(ctx context.Context, mapID string) ([]*entity.MindMapShare, error)
+	RevokeMindMapShare(ctx context.Context, mapID, shareID string) error
+	// GetSharedMindMap 通过分享令牌只读访问导图，无需登录态
+	GetSharedMindMap(ctx context.Context, token, password string) (*entity.MindMap, error)
+
+	// 协作者
+	ListMindMapCollaborators(ctx context.Context, mapID string) ([]*entity.MindMapCollaborator, error)
+	AddMindMapCollaborator(ctx context.Context, mapID string, req *AddMindMapCollaboratorParams) (*entity.MindMapCollaborator, error)
+	UpdateMindMapCollaboratorRole(ctx context.Context, mapID, userID, role string) error
+	RemoveMindMapCollaborator(ctx context.Context, mapID, userID string) error
+
+	// 实时协同编辑
+	JoinCollabSession(ctx context.Context, mapID string) (*CollabSession, error)
+	LeaveCollabSession(ctx context.Context, mapID, sessionID string) error
+	// UpdateCollabPresence 刷新在线状态，selectedNodeID 不为空时同时更新选中节点并广播
+	UpdateCollabPresence(ctx context.Context, mapID, sessionID string, selectedNodeID *string) error
+	CommitCollabOperations(ctx context.Context, mapID string, req *CommitCollabOperationsParams) (*CollabCommitResult, error)
+	// SubscribeCollabEvents 订阅全部导图的协同事件（跨实例），阻塞直到ctx结束
+	SubscribeCollabEvents(ctx context.Context, handler func(event *CollabEvent))
 }
 
 // 创建参数 - 服务层参数对象，无需json tag
 type CreateMindMapParams struct {
-	Title  string
-	Desc   string
-	Layout string
-	Data   entity.MindMapData
+	Title    string
+	Desc     string
+	Layout   string
+	Data     entity.MindMapData
+	FolderID string // 所属文件夹（仅内部复制导图时使用），为空表示未归档
 }
 
 // 列表查询参数 - 服务层参数对象，无需json tag
 type ListMindMapsParams struct {
 	Title    string
 	Layout   string
+	Scope    string   // owned / shared / all，为空时只查自己创建的
+	FolderID string   // 文件夹ID，unfiled 表示未归档，为空时不筛选
+	TagIDs   []string // 标签ID，导图需包含全部标签
 	Page     int
 	PageSize int
 }
@@ -36,11 +136,254 @@ type UpdateMindMapParams struct {
 	Desc   *string
 	Layout *string
 	Data   *entity.MindMapData
+
+	ExpectedVersion *int64 // 客户端持有的版本号，不为空时进行乐观锁校验
+}
+
+// 节点操作类型
+const (
+	NodeOpAddChild = "add_child" // 在父节点下添加子节点（可携带整棵子树）
+	NodeOpDelete   = "delete"    // 删除节点及其子树
+	NodeOpMove     = "move"      // 移动节点到新的父节点下
+	NodeOpReorder  = "reorder"   // 调整节点在兄弟节点中的位置
+	NodeOpEditText = "edit_text" // 修改节点文本
+)
+
+// 节点级增量更新参数 - 操作按顺序原子执行
+type PatchMindMapParams struct {
+	Operations      []NodeOperation
+	ExpectedVersion *int64 // 客户端持有的版本号，不为空时进行乐观锁校验
+}
+
+// 单个节点操作
+type NodeOperation struct {
+	Op       string
+	NodeID   string              // 目标节点UID（delete/move/reorder/edit_text）
+	ParentID string              // 父节点UID（add_child/move）
+	Index    *int                // 在兄弟节点中的位置，为空表示追加到末尾
+	Text     *string             // 节点文本（add_child/edit_text）
+	Node     *entity.MindMapData // 要添加的子树（add_child，可选）
+}
+
+// 复制导图参数
+type DuplicateMindMapParams struct {
+	Title string // 为空时使用原标题加“副本”后缀
+}
+
+// 提取子树参数
+type ExtractMindMapSubtreeParams struct {
+	NodeID          string // 要提取的节点UID，该节点成为新导图的根节点
+	Title           string // 为空时使用节点文本
+	ReplaceWithLink bool   // 是否在原导图中将该子树替换为指向新导图的链接节点（需要编辑权限）
+	ExpectedVersion *int64 // 替换时客户端持有的原导图版本号，不为空时进行乐观锁校验
+}
+
+// 提取子树结果
+type ExtractMindMapSubtreeResult struct {
+	MindMap       *entity.MindMap // 新导图
+	SourceVersion int64           // 替换后原导图的版本号，未替换时为0
+	LinkNodeID    string          // 原导图中链接节点的UID，未替换时为空
+}
+
+// 嫁接导图参数
+type GraftMindMapParams struct {
+	SourceMapID     string // 被插入的导图ID（需要查看权限）
+	ParentNodeID    string // 插入位置的父节点UID
+	Index           *int   // 在兄弟节点中的位置，为空表示追加到末尾
+	ExpectedVersion *int64 // 客户端持有的当前导图版本号，不为空时进行乐观锁校验
+}
+
+// 导出文件
+type ExportedFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// 导入参数
+type ImportMindMapParams struct {
+	File   *multipart.FileHeader
+	Title  string // 为空时使用文件中解析出的标题
+	Desc   string
+	Layout string // 为空时使用默认布局
+}
+
+// 回收站列表参数
+type ListTrashedMindMapsParams struct {
+	Page     int
+	PageSize int
+}
+
+// 创建文件夹参数
+type CreateMindMapFolderParams struct {
+	Name     string
+	ParentID string // 为空表示顶层文件夹
+}
+
+// 更新文件夹参数
+type UpdateMindMapFolderParams struct {
+	Name     *string
+	ParentID *string // 空字符串表示移动到顶层
 }
 
+// 创建标签参数
+type CreateMindMapTagParams struct {
+	Name  string
+	Color string
+}
+
+// 更新标签参数
+type UpdateMindMapTagParams struct {
+	Name  *string
+	Color *string
+}
+
+// 保存模板参数
+type SaveMindMapAsTemplateParams struct {
+	MapID string
+	Name  string // 为空时使用导图标题
+	Desc  string
+}
+
+// 基于模板创建导图参数
+type CreateMindMapFromTemplateParams struct {
+	Title    string // 为空时使用填充后的根节点文本
+	Desc     string
+	Layout   string // 为空时使用模板布局
+	FillText string // 用于AI填充占位节点的用户文本，为空时占位节点替换为提示语
+}
+
+// 创建节点链接参数
+type CreateMindMapNodeLinkParams struct {
+	SourceNodeID string // 当前导图中的源节点UID
+	TargetMapID  string // 目标导图ID，可以是当前导图
+	TargetNodeID string // 目标节点UID，为空表示指向整个导图
+	Type         string // 链接类型，为空时为 reference
+	Label        string
+}
+
+// 全文检索参数
+type SearchMindMapsParams struct {
+	Query    string
+	Page     int
+	PageSize int
+}
+
+// MindMapSearchResult 单个导图的检索结果
+type MindMapSearchResult struct {
+	MindMap      *entity.MindMap // 导图基本信息（不含Data）
+	Score        float64
+	TitleSnippet string              // 标题命中时的高亮标题，未命中为空
+	Hits         []*MindMapSearchHit // 相关度最高的若干命中节点
+	HitCount     int                 // 命中节点总数
+}
+
+// MindMapSearchHit 命中节点
+type MindMapSearchHit struct {
+	NodeUID string
+	Path    []string // 从根节点到命中节点的文本路径
+	Field   string   // 高亮片段来源：text / note
+	Snippet string   // 高亮片段，命中部分以 <em></em> 包裹，其余内容已做HTML转义
+	Score   float64
+}
+
+// 历史版本列表参数
+type ListMindMapRevisionsParams struct {
+	Page     int
+	PageSize int
+}
+
+// 恢复历史版本参数
+type RestoreMindMapRevisionParams struct {
+	Version         int64  // 要恢复到的历史版本号
+	ExpectedVersion *int64 // 客户端持有的当前版本号，不为空时进行乐观锁校验
+}
+
+// 创建分享链接参数
+type CreateMindMapShareParams struct {
+	ExpiresAt *time.Time // 过期时间，为空表示永久有效
+	Password  string     // 访问密码，为空表示无需密码
+}
+
+// 邀请协作者参数
+type AddMindMapCollaboratorParams struct {
+	AccountType string // 被邀请人联系方式类型：phone / email
+	Account     string // 被邀请人手机号或邮箱
+	Role        string // viewer / commenter / editor
+}
+
+// 协同编辑事件类型
+const (
+	CollabEventOperation = "op"     // 节点级操作
+	CollabEventReset     = "reset"  // 整体覆盖（全量更新/恢复版本），客户端需重新拉取导图
+	CollabEventJoin      = "join"   // 成员加入
+	CollabEventLeave     = "leave"  // 成员离开
+	CollabEventSelect    = "select" // 成员选中节点变化
+)
+
+// CollabEvent 协同编辑事件，经Redis在多个服务实例间广播
+type CollabEvent struct {
+	Type       string
+	MapID      string
+	Version    int64           // 操作生效后的导图版本号（op/reset）
+	SessionID  string          // 产生事件的会话，REST接口写入时为空
+	UserID     string          // 产生事件的用户
+	Operations []NodeOperation // 已按最新版本变换过的操作（op）
+	Member     *CollabMember   // 在线成员信息（join/leave/select）
+}
+
+// CollabMember 协同编辑在线成员
+type CollabMember struct {
+	SessionID      string
+	UserID         string
+	UserName       string
+	Avatar         string
+	Role           string
+	SelectedNodeID string
+	LastSeenAt     time.Time
+}
+
+// CollabSession 加入协同编辑后的初始状态
+type CollabSession struct {
+	SessionID string
+	Role      string
+	MindMap   *entity.MindMap
+	Members   []*CollabMember // 当前在线成员（包含自己）
+}
+
+// 提交协同操作参数
+type CommitCollabOperationsParams struct {
+	SessionID   string
+	BaseVersion int64 // 客户端生成这批操作时所基于的导图版本号
+	Operations  []NodeOperation
+}
+
+// 协同操作提交结果
+type CollabCommitResult struct {
+	Version        int64
+	Operations     []NodeOperation // 变换后实际生效的操作，按顺序作用于提交前的最新版本即可得到 Version 对应的数据
+	AddedNodeIDs   []string
+	SkippedIndexes []int // 因并发冲突失效而被丢弃的操作下标（如目标节点已被他人删除）
+}
+
+// 流式事件类型
+const (
+	StreamEventTextDelta      = "text_delta"      // 回复文本增量
+	StreamEventReasoningDelta = "reasoning_delta" // 思考过程增量
+	StreamEventToolStarted    = "tool_started"    // 开始调用工具
+	StreamEventToolResult     = "tool_result"     // 工具调用结束（导图类工具的结果通过 map_update 下发）
+	StreamEventMapUpdate      = "map_update"      // 工具产出新的导图，附带待处理的修改建议
+	StreamEventDone           = "done"            // 本轮对话结束，附带完整回复
+	StreamEventError          = "error"           // 出错，流随即结束
+)
+
 // 定义流式数据块
 type StreamChunk struct {
-	Content string
-	IsLast  bool
-	Error   error
+	Event      string // 见 StreamEvent* 常量
+	Content    string
+	ToolCallID string
+	ToolName   string
+	Arguments  string // 工具调用参数
+	NewMapJson string
+	ProposalID string
 }
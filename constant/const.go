@@ -10,4 +10,11 @@ const (
 const (
 	// REDIS_VERIFICATION_CODE_KEY 验证码 Redis key
 	REDIS_VERIFICATION_CODE_KEY = "verification_code:%s"
+
+	// REDIS_MINDMAP_COLLAB_CHANNEL 导图协同事件发布订阅频道（按导图ID区分）
+	REDIS_MINDMAP_COLLAB_CHANNEL = "mindmap_collab:%s"
+	// REDIS_MINDMAP_COLLAB_CHANNEL_PATTERN 订阅全部导图协同事件的频道模式
+	REDIS_MINDMAP_COLLAB_CHANNEL_PATTERN = "mindmap_collab:*"
+	// REDIS_MINDMAP_COLLAB_PRESENCE_KEY 导图在线成员 Redis hash key（field 为会话ID）
+	REDIS_MINDMAP_COLLAB_PRESENCE_KEY = "mindmap_collab_presence:%s"
 )
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"forge/constant"
+	"forge/pkg/log/zlog"
+)
+
+// 在线成员hash的过期时间，每次写入时续期，用于兜底清理异常退出的实例留下的数据
+const collabPresenceExpiration = 10 * time.Minute
+
+// 未启用Redis时的单实例兜底实现
+var (
+	localCollabMu          sync.RWMutex
+	localCollabSubscribers []func(mapID string, payload []byte)
+	localCollabPresence    = make(map[string]map[string]string)
+)
+
+// PublishCollabEvent 发布导图协同事件，启用Redis时由所有实例的订阅者接收
+func PublishCollabEvent(ctx context.Context, mapID string, payload []byte) error {
+	if redisClient == nil {
+		localCollabMu.RLock()
+		subscribers := append([]func(string, []byte){}, localCollabSubscribers...)
+		localCollabMu.RUnlock()
+		for _, subscriber := range subscribers {
+			subscriber(mapID, payload)
+		}
+		return nil
+	}
+	return redisClient.Publish(ctx, fmt.Sprintf(constant.REDIS_MINDMAP_COLLAB_CHANNEL, mapID), payload).Err()
+}
+
+// SubscribeCollabEvents 订阅全部导图的协同事件，阻塞直到ctx结束
+func SubscribeCollabEvents(ctx context.Context, handler func(mapID string, payload []byte)) {
+	if redisClient == nil {
+		localCollabMu.Lock()
+		localCollabSubscribers = append(localCollabSubscribers, handler)
+		localCollabMu.Unlock()
+		<-ctx.Done()
+		return
+	}
+
+	pubsub := redisClient.PSubscribe(ctx, constant.REDIS_MINDMAP_COLLAB_CHANNEL_PATTERN)
+	defer pubsub.Close()
+
+	prefix := strings.TrimSuffix(constant.REDIS_MINDMAP_COLLAB_CHANNEL, "%s")
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				zlog.Warnf("mindmap collab subscription closed")
+				return
+			}
+			handler(strings.TrimPrefix(msg.Channel, prefix), []byte(msg.Payload))
+		}
+	}
+}
+
+// SetCollabPresence 写入导图在线成员信息
+func SetCollabPresence(ctx context.Context, mapID, sessionID, value string) error {
+	if redisClient == nil {
+		localCollabMu.Lock()
+		defer localCollabMu.Unlock()
+		if localCollabPresence[mapID] == nil {
+			localCollabPresence[mapID] = make(map[string]string)
+		}
+		localCollabPresence[mapID][sessionID] = value
+		return nil
+	}
+
+	key := fmt.Sprintf(constant.REDIS_MINDMAP_COLLAB_PRESENCE_KEY, mapID)
+	pipe := redisClient.TxPipeline()
+	pipe.HSet(ctx, key, sessionID, value)
+	pipe.Expire(ctx, key, collabPresenceExpiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetCollabPresence 获取指定会话的在线成员信息，不存在时返回空字符串
+func GetCollabPresence(ctx context.Context, mapID, sessionID string) (string, error) {
+	if redisClient == nil {
+		localCollabMu.RLock()
+		defer localCollabMu.RUnlock()
+		return localCollabPresence[mapID][sessionID], nil
+	}
+
+	values, err := redisClient.HMGet(ctx, fmt.Sprintf(constant.REDIS_MINDMAP_COLLAB_PRESENCE_KEY, mapID), sessionID).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(values) == 0 || values[0] == nil {
+		return "", nil
+	}
+	value, _ := values[0].(string)
+	return value, nil
+}
+
+// ListCollabPresence 获取导图全部在线成员信息（会话ID -> 成员信息）
+func ListCollabPresence(ctx context.Context, mapID string) (map[string]string, error) {
+	if redisClient == nil {
+		localCollabMu.RLock()
+		defer localCollabMu.RUnlock()
+		result := make(map[string]string, len(localCollabPresence[mapID]))
+		for sessionID, value := range localCollabPresence[mapID] {
+			result[sessionID] = value
+		}
+		return result, nil
+	}
+	return redisClient.HGetAll(ctx, fmt.Sprintf(constant.REDIS_MINDMAP_COLLAB_PRESENCE_KEY, mapID)).Result()
+}
+
+// RemoveCollabPresence 删除导图在线成员信息
+func RemoveCollabPresence(ctx context.Context, mapID string, sessionIDs ...string) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+	if redisClient == nil {
+		localCollabMu.Lock()
+		defer localCollabMu.Unlock()
+		for _, sessionID := range sessionIDs {
+			delete(localCollabPresence[mapID], sessionID)
+		}
+		if len(localCollabPresence[mapID]) == 0 {
+			delete(localCollabPresence, mapID)
+		}
+		return nil
+	}
+	return redisClient.HDel(ctx, fmt.Sprintf(constant.REDIS_MINDMAP_COLLAB_PRESENCE_KEY, mapID), sessionIDs...).Err()
+}
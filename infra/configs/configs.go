@@ -25,6 +25,7 @@ type IConfig interface {
 	GetCozeLoopConfig() CozeLoopConfig   // CozeLoop 可观测性配置
 	GetRateLimitConfig() RateLimitConfig // 限流配置
 	GetSearchConfig() SearchConfig
+	GetTrashConfig() TrashConfig // 回收站配置
 }
 
 var (
@@ -93,6 +94,9 @@ func (c *config) GetRateLimitConfig() RateLimitConfig { return c.RateLimitConfig
 // 搜索服务配置读取
 func (c *config) GetSearchConfig() SearchConfig { return c.SearchConfig }
 
+// 回收站配置读取
+func (c *config) GetTrashConfig() TrashConfig { return c.TrashConfig }
+
 func mustInit(path string) *config {
 	// 初始化时间为东八区的时间
 	var cstZone = time.FixedZone("CST", 8*3600) // 东八
@@ -150,6 +154,7 @@ type config struct {
 	CozeLoopConfig  CozeLoopConfig    `mapstructure:"cozeloop"`
 	RateLimitConfig RateLimitConfig   `mapstructure:"rate_limit"`
 	SearchConfig    SearchConfig      `mapstructure:"search"`
+	TrashConfig     TrashConfig       `mapstructure:"trash"`
 }
 
 type ApplicationConfig struct {
@@ -225,6 +230,25 @@ type AiChatConfig struct {
 	// 质量评估模型配置
 	QualityApiKey    string `mapstructure:"quality_api_key"`
 	QualityModelName string `mapstructure:"quality_model_name"`
+	// 对话上下文token预算，超出后较早的对话压缩为摘要
+	ContextTokenBudget       int            `mapstructure:"context_token_budget"`        // 默认预算，默认 32000
+	ModelContextTokenBudgets map[string]int `mapstructure:"model_context_token_budgets"` // 按模型名单独配置的预算
+	// 模型服务商与按用途选择的模型，未配置的用途沿用上面的火山方舟配置
+	Providers map[string]LLMProviderConfig `mapstructure:"providers"` // 键为服务商名称
+	Models    map[string]LLMModelConfig    `mapstructure:"models"`    // 键为用途：chat/summary/tool/generate/tab/quality
+}
+
+// LLMProviderConfig 模型服务商配置
+type LLMProviderConfig struct {
+	Type    string `mapstructure:"type"` // ark（火山方舟）或 openai（OpenAI兼容接口，含 Ollama、llama.cpp 等本地服务），为空时取服务商名称
+	ApiKey  string `mapstructure:"api_key"`
+	BaseURL string `mapstructure:"base_url"`
+}
+
+// LLMModelConfig 某个用途使用的模型
+type LLMModelConfig struct {
+	Provider string `mapstructure:"provider"` // providers 中的服务商名称
+	Model    string `mapstructure:"model"`
 }
 
 type SMSConfig struct {
@@ -234,6 +258,7 @@ type SMSConfig struct {
 
 type UniOfficeConfig struct {
 	MeteredKey string `mapstructure:"metered_key"`
+	FontPath   string `mapstructure:"font_path"` // PDF渲染使用的TTF字体路径，需支持中文，为空时使用内置字体且无法导出含中文的导图
 }
 
 // OAuthConfig OAuth 第三方登录配置
@@ -267,3 +292,9 @@ type SearchConfig struct {
 	Provider string `mapstructure:"provider"` // 搜索服务提供商
 	APIKey   string `mapstructure:"api_key"`  // API密钥
 }
+
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	RetentionDays        int `mapstructure:"retention_days"`         // 删除后保留天数，超过后彻底清除，默认 30 天
+	PurgeIntervalMinutes int `mapstructure:"purge_interval_minutes"` // 后台清理任务执行间隔（分钟），默认 60 分钟
+}
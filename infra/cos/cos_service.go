@@ -148,3 +148,12 @@ func (c *cosServiceImpl) UploadFile(ctx context.Context, resourcePath string, fi
 	zlog.CtxInfof(ctx, "file uploaded successfully to COS, path: %s", resourcePath)
 	return fullURL, nil
 }
+
+// DeleteFile 删除COS上的文件
+func (c *cosServiceImpl) DeleteFile(ctx context.Context, resourcePath string) error {
+	if _, err := c.cosClient.Object.Delete(ctx, resourcePath); err != nil {
+		zlog.CtxErrorf(ctx, "failed to delete file from COS, path: %s, error: %v", resourcePath, err)
+		return fmt.Errorf("failed to delete file from COS: %w", err)
+	}
+	return nil
+}
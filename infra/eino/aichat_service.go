@@ -12,29 +12,24 @@ import (
 	"forge/infra/configs"
 	"forge/pkg/log/zlog"
 	"forge/pkg/loop"
-	"io"
 	"sync"
 
 	"github.com/cloudwego/eino-ext/callbacks/cozeloop"
-	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
-	"github.com/coze-dev/cozeloop-go/spec/tracespec"
-	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
-	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 )
 
 type AiChatClient struct {
-	ApiKey              string
-	ModelName           string
-	Agent               compose.Runnable[[]*schema.Message, types.AgentResponse]
-	ToolAiClient        *ark.ChatModel
-	GenerateMapAiClient *ark.ChatModel
-	StreamChatClient    *ark.ChatModel // 流式输出专用客户端（不带JSON限制）
-	ArkClient           *arkruntime.Client
-	SearchService       *SearchService // 搜索服务
+	ModelName                string // 对话模型名称
+	Agent                    compose.Runnable[[]*schema.Message, types.AgentResponse]
+	ToolAiClient             model.ToolCallingChatModel
+	GenerateMapAiClient      model.ToolCallingChatModel
+	StructuredOutputAiClient model.ToolCallingChatModel // 导图生成专用客户端（严格JSON Schema结构化输出，不开启思考）
+	SummaryAiClient          model.ToolCallingChatModel // 对话摘要专用客户端（不开启思考）
+	SearchService            *SearchService             // 搜索服务
 }
 
 type State struct {
@@ -59,7 +54,8 @@ var (
 	einoCallbackOnce sync.Once
 )
 
-func NewAiChatClient(apiKey, modelName string) repo.EinoServer {
+// NewAiChatClient 按配置中各用途的模型构建对话客户端
+func NewAiChatClient() repo.EinoServer {
 
 	var toolSchemaMap map[string]interface{}
 	if err := json.Unmarshal([]byte(mindMapSchemaString), &toolSchemaMap); err != nil {
@@ -80,32 +76,26 @@ func NewAiChatClient(apiKey, modelName string) repo.EinoServer {
 	var aiChatClient AiChatClient
 
 	//初始化工具专用模型
-	toolModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:   apiKey,
-		Model:    modelName,
-		Thinking: &model.Thinking{Type: model.ThinkingTypeEnabled},
-		ResponseFormat: &ark.ResponseFormat{Type: model.ResponseFormatJSONSchema, JSONSchema: &model.ResponseFormatJSONSchemaJSONSchemaParam{
+	toolModel, err := NewChatModel(ctx, ModelRoleTool, ChatModelOptions{
+		Thinking: true,
+		JSONSchema: &JSONSchemaOutput{
 			Name:        "mindmap_editor",
 			Description: "思维导图编辑机器人输出，输出单行json，不允许有任何换行",
 			Schema:      toolSchemaMap,
-			Strict:      true,
-		}},
+		},
 	})
 	if toolModel == nil || err != nil {
 		zlog.Errorf("ToolAi模型连接失败: %v", err)
 		panic(fmt.Errorf("ToolAi模型连接失败: %v", err))
 	}
 
-	generateModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:   apiKey,
-		Model:    modelName,
-		Thinking: &model.Thinking{Type: model.ThinkingTypeEnabled},
-		ResponseFormat: &ark.ResponseFormat{Type: model.ResponseFormatJSONSchema, JSONSchema: &model.ResponseFormatJSONSchemaJSONSchemaParam{
+	generateModel, err := NewChatModel(ctx, ModelRoleGenerate, ChatModelOptions{
+		Thinking: true,
+		JSONSchema: &JSONSchemaOutput{
 			Name:        "mindmap_generator",
 			Description: "思维导图生成机器人输出，输出单行json，不允许有任何换行",
 			Schema:      generateSchemaMap,
-			Strict:      true,
-		}},
+		},
 	})
 
 	if generateModel == nil || err != nil {
@@ -113,24 +103,23 @@ func NewAiChatClient(apiKey, modelName string) repo.EinoServer {
 		panic(fmt.Errorf("generateModel模型连接失败: %v", err))
 	}
 
-	// 初始化流式输出专用模型（不带JSON Schema限制，可以输出普通文字）
-	streamChatClient, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:   apiKey,
-		Model:    modelName,
-		Thinking: &model.Thinking{Type: model.ThinkingTypeEnabled},
-		// 注意：这里不设置 ResponseFormat，让模型自由输出文字
+	//导图生成与批量生成使用结构化输出确保 JSON 格式准确
+	structuredOutputModel, err := NewChatModel(ctx, ModelRoleGenerate, ChatModelOptions{
+		JSONSchema: &JSONSchemaOutput{
+			Name:        "mindmap_schema",
+			Description: "思维导图JSON结构，包含title、desc、layout和递归的root节点树",
+			Schema:      generationservice.GetMindMapJSONSchema(),
+		},
 	})
-	if streamChatClient == nil || err != nil {
-		zlog.Errorf("StreamChatClient模型连接失败: %v", err)
-		panic(fmt.Errorf("StreamChatClient模型连接失败: %v", err))
+	if structuredOutputModel == nil || err != nil {
+		zlog.Errorf("结构化输出模型连接失败: %v", err)
+		panic(fmt.Errorf("结构化输出模型连接失败: %v", err))
 	}
 
-	aiChatClient.ApiKey = apiKey
-	aiChatClient.ModelName = modelName
+	aiChatClient.ModelName = ResolveModelName(ModelRoleChat)
 	aiChatClient.ToolAiClient = toolModel
 	aiChatClient.GenerateMapAiClient = generateModel
-	aiChatClient.StreamChatClient = streamChatClient
-	aiChatClient.ArkClient = arkruntime.NewClientWithApiKey(apiKey) // 初始化火山引擎客户端，复用避免重复创建
+	aiChatClient.StructuredOutputAiClient = structuredOutputModel
 
 	// 初始化搜索服务
 	searchConfig := configs.Config().GetSearchConfig()
@@ -138,26 +127,19 @@ func NewAiChatClient(apiKey, modelName string) repo.EinoServer {
 	zlog.Infof("搜索服务初始化完成，使用提供商: %s", searchConfig.Provider)
 
 	//构建agent
-	aiChatModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:   apiKey,
-		Model:    modelName,
-		Thinking: &model.Thinking{Type: model.ThinkingTypeEnabled},
-	})
+	aiChatModel, err := NewChatModel(ctx, ModelRoleChat, ChatModelOptions{Thinking: true})
 	if aiChatModel == nil || err != nil {
 		zlog.Errorf("ai模型连接失败: %v", err)
 		panic(fmt.Errorf("ai模型连接失败: %v", err))
 	}
 
-	sumUpClient, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:   apiKey,
-		Model:    modelName,
-		Thinking: &model.Thinking{Type: model.ThinkingTypeDisabled},
-	})
+	sumUpClient, err := NewChatModel(ctx, ModelRoleSummary, ChatModelOptions{})
 
 	if sumUpClient == nil || err != nil {
 		zlog.Errorf("sumUp模型连接失败: %v", err)
 		panic(fmt.Errorf("sumUp 模型连接失败: %v", err))
 	}
+	aiChatClient.SummaryAiClient = sumUpClient
 
 	//创建工具
 	updateMindMapTool := aiChatClient.CreateUpdateMindMapTool()
@@ -187,7 +169,7 @@ func NewAiChatClient(apiKey, modelName string) repo.EinoServer {
 		webSearchToolInfo,
 		generateMindMapToolInfo,
 	}
-	err = aiChatModel.BindTools(infosTool)
+	aiChatModel, err = aiChatModel.WithTools(infosTool)
 	if err != nil {
 		zlog.Errorf("ai绑定工具失败: %v", err)
 		panic(fmt.Errorf("ai绑定工具失败: %v", err))
@@ -389,77 +371,13 @@ func (a *AiChatClient) SendMessage(ctx context.Context, messages []*entity.Messa
 	return resp, nil
 }
 
-func (a *AiChatClient) SendMessageStream(ctx context.Context, messages []*entity.Message) (<-chan types.StreamChunk, error) {
-
-	// 创建通道用于传输流式数据
-	chunkChan := make(chan types.StreamChunk, 10)
-
-	go a.handleStreaming(ctx, messages, chunkChan)
-
-	return chunkChan, nil
-}
-
-// 异步返回分块信息
-func (a *AiChatClient) handleStreaming(
-	ctx context.Context,
-	messages []*entity.Message,
-	chunkChan chan<- types.StreamChunk,
-) {
-	defer close(chunkChan) // 确保通道被关闭
-
-	// 将消息转换为Eino框架需要的输入格式
-	input := messagesDo2Input(messages)
-
-	//调用流式生成（使用StreamChatClient，输出普通文字而非JSON）
-	stream, err := a.StreamChatClient.Stream(ctx, input)
-
-	if err != nil {
-		zlog.Errorf("流式模型调用失败: %v", err)
-		chunkChan <- types.StreamChunk{Error: err}
-		return
-	}
-
-	// 处理流式响应
-	for {
-		// 读取流式数据
-		chunkData, err := stream.Recv()
-		if err != nil {
-			if err == io.EOF {
-				chunkChan <- types.StreamChunk{IsLast: true}
-			} else {
-				// The underlying stream should return an error on context cancellation.
-				chunkChan <- types.StreamChunk{Error: err}
-			}
-			return
-		}
-
-		if chunkData.Content == "" {
-			continue
-		}
-
-		// 发送数据块，同时检查上下文是否被取消
-		select {
-		case chunkChan <- types.StreamChunk{
-			Content: chunkData.Content,
-			IsLast:  false,
-		}:
-		case <-ctx.Done():
-			chunkChan <- types.StreamChunk{Error: ctx.Err()}
-			return
-		}
-	}
-}
-
 // 传入文本生成导图（使用结构化输出确保 JSON 格式准确）
 func (a *AiChatClient) GenerateMindMap(ctx context.Context, text, userID string) (result string, err error) {
 	// 使用与批量生成相同的结构化输出方式
 	messages := initGenerateMindMapMessage(text, userID)
 
-	// 获取 JSON Schema
-	mindMapSchema := generationservice.GetMindMapJSONSchema()
-
 	// 使用结构化输出调用 API
-	resp, err := a.generateWithStructuredOutput(ctx, messages, mindMapSchema)
+	resp, err := a.generateWithStructuredOutput(ctx, messages)
 	if err != nil {
 		zlog.CtxErrorf(ctx, "模型调用失败: %v", err)
 		return "", err
@@ -477,129 +395,17 @@ func (a *AiChatClient) GenerateMindMapBatch(ctx context.Context, text, userID st
 	}
 }
 
-// generateWithStructuredOutput 使用结构化输出调用火山引擎 API
-// 保持原有的 ResponseFormat 参数以确保 JSON 格式严格性
-// 同时添加手动 Model Span 追踪（因为直接 API 调用不会被 Eino 回调捕获）
-func (a *AiChatClient) generateWithStructuredOutput(
-	ctx context.Context,
-	messages []*schema.Message,
-	jsonSchema map[string]interface{},
-) (result *schema.Message, err error) {
-	// 声明响应变量，使其在defer块中可用
-	var resp model.ChatCompletionResponse
-
-	// 创建手动 Model Span 用于追踪结构化输出调用
-	ctx, modelSpan := loop.StartModelSpan(ctx, "eino.generate_structured_output", "doubao", a.ModelName)
-	defer func() {
-		if modelSpan != nil {
-			// 构建追踪消息
-			traceMessages := make([]*tracespec.ModelMessage, 0, len(messages))
-			for _, msg := range messages {
-				var role string
-				switch msg.Role {
-				case schema.System:
-					role = tracespec.VRoleSystem
-				case schema.User:
-					role = tracespec.VRoleUser
-				case schema.Assistant:
-					role = tracespec.VRoleAssistant
-				default:
-					role = tracespec.VRoleUser
-				}
-				traceMessages = append(traceMessages, &tracespec.ModelMessage{
-					Role:    role,
-					Content: msg.Content,
-				})
-			}
-
-			// 设置 Model Span 数据 - 记录完整的响应内容
-			var responseContent string
-			if result != nil && result.Content != "" {
-				responseContent = result.Content // 记录完整的 JSON 输出
-			}
-
-			// 提取token使用量信息
-			var inputTokens, outputTokens int64
-			inputTokens = int64(resp.Usage.PromptTokens)
-			outputTokens = int64(resp.Usage.CompletionTokens)
-
-			loop.SetModelSpanData(ctx, modelSpan, traceMessages, responseContent, inputTokens, outputTokens, err)
-		}
-	}()
-
-	// 使用复用的火山引擎客户端
-	client := a.ArkClient
-
-	// 转换消息格式
-	arkMessages := make([]*model.ChatCompletionMessage, 0, len(messages))
-	for _, msg := range messages {
-		role := ""
-		switch msg.Role {
-		case schema.System:
-			role = "system"
-		case schema.User:
-			role = "user"
-		case schema.Assistant:
-			role = "assistant"
-		default:
-			role = "user"
-		}
-		// ChatCompletionMessageContent 需要包装字符串
-		content := &model.ChatCompletionMessageContent{
-			StringValue: &msg.Content,
-		}
-		arkMessages = append(arkMessages, &model.ChatCompletionMessage{
-			Role:    role,
-			Content: content,
-		})
-	}
-
-	// 构建结构化输出配置
-	responseFormat := &model.ResponseFormat{
-		Type: model.ResponseFormatJSONSchema,
-		JSONSchema: &model.ResponseFormatJSONSchemaJSONSchemaParam{
-			Name:        "mindmap_schema",
-			Description: "思维导图JSON结构，包含title、desc、layout和递归的root节点树",
-			Schema:      jsonSchema,
-			Strict:      true, // 严格模式，确保格式完全符合
-		},
-	}
-
-	// 构建请求（使用 CreateChatCompletionRequest 替代已废弃的 ChatCompletionRequest）
-	request := model.CreateChatCompletionRequest{
-		Model:          a.ModelName,
-		Messages:       arkMessages,
-		ResponseFormat: responseFormat,
-		Thinking:       &model.Thinking{Type: model.ThinkingTypeDisabled},
-	}
-
-	// 调用 API
-	resp, err = client.CreateChatCompletion(ctx, request)
+// generateWithStructuredOutput 使用严格JSON Schema结构化输出生成导图，
+// 调用经由 Eino 回调自动上报追踪
+func (a *AiChatClient) generateWithStructuredOutput(ctx context.Context, messages []*schema.Message) (*schema.Message, error) {
+	resp, err := a.StructuredOutputAiClient.Generate(ctx, messages)
 	if err != nil {
 		return nil, fmt.Errorf("结构化输出调用失败: %w", err)
 	}
-
-	if len(resp.Choices) == 0 {
+	if resp == nil || resp.Content == "" {
 		return nil, errors.New("API返回结果为空")
 	}
-
-	// 提取返回内容（增加健壮性检查）
-	choice := resp.Choices[0]
-	messageContent := choice.Message.Content
-	var contentStr string
-	if messageContent.StringValue != nil {
-		contentStr = *messageContent.StringValue
-	} else if len(messageContent.ListValue) > 0 && messageContent.ListValue[0].Text != "" {
-		contentStr = messageContent.ListValue[0].Text
-	} else {
-		return nil, errors.New("API返回内容格式不正确")
-	}
-
-	result = &schema.Message{
-		Content: contentStr,
-		Role:    schema.Assistant,
-	}
-	return result, nil
+	return resp, nil
 }
 
 // generateForSFTTraining 策略1：SFT训练数据策略 - 并行生成+结构化输出
@@ -608,9 +414,6 @@ func (a *AiChatClient) generateForSFTTraining(ctx context.Context, text, userID
 	// 使用标准System Prompt（已简化，无需格式要求）
 	sftSystemPrompt := generationservice.SFTStandardSystemPrompt
 
-	// 获取 JSON Schema
-	mindMapSchema := generationservice.GetMindMapJSONSchema()
-
 	// 并行生成结果通道
 	type generationResult struct {
 		content      string
@@ -635,7 +438,7 @@ func (a *AiChatClient) generateForSFTTraining(ctx context.Context, text, userID
 			}
 
 			// 使用结构化输出调用 API，确保 JSON 格式准确
-			resp, err := a.generateWithStructuredOutput(ctx, messages, mindMapSchema)
+			resp, err := a.generateWithStructuredOutput(ctx, messages)
 
 			if err != nil {
 				zlog.CtxWarnf(ctx, "并行生成失败 index:%d, err:%v", index, err)
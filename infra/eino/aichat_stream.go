@@ -0,0 +1,152 @@
+package eino
+
+import (
+	"context"
+	"errors"
+	"forge/biz/entity"
+	"forge/biz/types"
+	"forge/pkg/log/zlog"
+	"io"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	callbackutils "github.com/cloudwego/eino/utils/callbacks"
+)
+
+// SendMessageStream 以流式方式执行与 SendMessage 相同的 agent（含工具调用），
+// 模型的文本/思考增量与工具调用过程通过回调实时转发给 onChunk，返回最终结果
+func (a *AiChatClient) SendMessageStream(ctx context.Context, messages []*entity.Message, onChunk func(chunk types.StreamChunk) error) (types.AgentResponse, error) {
+	input := messagesDo2Input(messages)
+
+	// onChunk 出错（如客户端断开）时取消本次调用
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	emitter := &streamEmitter{onChunk: onChunk, cancel: cancel}
+	handler := callbackutils.NewHandlerHelper().
+		ChatModel(&callbackutils.ModelCallbackHandler{
+			OnEndWithStreamOutput: emitter.onModelStream,
+		}).
+		Tool(&callbackutils.ToolCallbackHandler{
+			OnStart: emitter.onToolStart,
+			OnEnd:   emitter.onToolEnd,
+		}).
+		Handler()
+
+	stream, err := a.Agent.Stream(ctx, input, compose.WithCallbacks(handler).DesignateNode("model", "sumUpModel", "tools"))
+	if err != nil {
+		if emitErr := emitter.Err(); emitErr != nil {
+			return types.AgentResponse{}, emitErr
+		}
+		zlog.CtxErrorf(ctx, "流式模型调用失败: %v", err)
+		return types.AgentResponse{}, err
+	}
+	defer stream.Close()
+
+	// lambda2 是非流式节点，结果只有一个数据块
+	var resp types.AgentResponse
+	received := false
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if emitErr := emitter.Err(); emitErr != nil {
+				return types.AgentResponse{}, emitErr
+			}
+			zlog.CtxErrorf(ctx, "流式模型调用失败: %v", err)
+			return types.AgentResponse{}, err
+		}
+		resp = chunk
+		received = true
+	}
+
+	if emitErr := emitter.Err(); emitErr != nil {
+		return types.AgentResponse{}, emitErr
+	}
+	if !received {
+		return types.AgentResponse{}, errors.New("agent出错")
+	}
+	return resp, nil
+}
+
+// streamEmitter 将 agent 执行过程中的回调转换为流式事件，工具可能并发执行，发送需要加锁
+type streamEmitter struct {
+	mu      sync.Mutex
+	onChunk func(chunk types.StreamChunk) error
+	cancel  context.CancelFunc
+	err     error
+}
+
+func (e *streamEmitter) emit(chunk types.StreamChunk) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return
+	}
+	if err := e.onChunk(chunk); err != nil {
+		e.err = err
+		e.cancel()
+	}
+}
+
+// Err 返回发送事件时的第一个错误
+func (e *streamEmitter) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// onModelStream 同步读取模型输出流的副本，转发文本与思考增量
+func (e *streamEmitter) onModelStream(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+	defer output.Close()
+	for {
+		frame, err := output.Recv()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				zlog.CtxWarnf(ctx, "读取模型流式输出失败: %v", err)
+			}
+			return ctx
+		}
+		if frame == nil || frame.Message == nil {
+			continue
+		}
+		if frame.Message.ReasoningContent != "" {
+			e.emit(types.StreamChunk{Event: types.StreamEventReasoningDelta, Content: frame.Message.ReasoningContent})
+		}
+		if frame.Message.Content != "" {
+			e.emit(types.StreamChunk{Event: types.StreamEventTextDelta, Content: frame.Message.Content})
+		}
+	}
+}
+
+func (e *streamEmitter) onToolStart(ctx context.Context, info *callbacks.RunInfo, input *tool.CallbackInput) context.Context {
+	chunk := types.StreamChunk{
+		Event:      types.StreamEventToolStarted,
+		ToolCallID: compose.GetToolCallID(ctx),
+		ToolName:   info.Name,
+	}
+	if input != nil {
+		chunk.Arguments = input.ArgumentsInJSON
+	}
+	e.emit(chunk)
+	return ctx
+}
+
+func (e *streamEmitter) onToolEnd(ctx context.Context, info *callbacks.RunInfo, output *tool.CallbackOutput) context.Context {
+	chunk := types.StreamChunk{
+		Event:      types.StreamEventToolResult,
+		ToolCallID: compose.GetToolCallID(ctx),
+		ToolName:   info.Name,
+	}
+	if output != nil {
+		chunk.Content = output.Response
+	}
+	e.emit(chunk)
+	return ctx
+}
@@ -0,0 +1,70 @@
+package eino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"forge/biz/entity"
+	"forge/pkg/log/zlog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// 单条消息写入摘要输入时保留的最大字符数，避免工具输出的导图JSON撑满上下文
+const summaryMessageMaxRunes = 2000
+
+const conversationSummaryPrompt = `你是「导图助手」的对话记录整理员。
+任务：把一段较早的对话压缩成摘要，供助手在后续对话中回顾。
+
+要求：
+1. 保留用户的目标、偏好、已确认的结论和未完成的事项
+2. 保留对导图做过的修改（改了哪些节点、为什么改），不需要复述完整导图，最新导图会另外提供
+3. 如果给出了之前的摘要，将其与新对话合并成一份完整摘要，不要丢失之前摘要中的信息
+4. 使用简洁的中文条目输出，不超过800字，只输出摘要本身`
+
+// SummarizeMessages 将较早的对话与之前的摘要合并为新的摘要
+func (a *AiChatClient) SummarizeMessages(ctx context.Context, summary string, messages []*entity.Message) (string, error) {
+	var transcript strings.Builder
+	if summary != "" {
+		transcript.WriteString("之前的摘要：\n")
+		transcript.WriteString(summary)
+		transcript.WriteString("\n\n")
+	}
+	transcript.WriteString("需要压缩的对话：\n")
+	for _, msg := range messages {
+		content := msg.Content
+		if utf8.RuneCountInString(content) > summaryMessageMaxRunes {
+			content = string([]rune(content)[:summaryMessageMaxRunes]) + "…（已截断）"
+		}
+		switch msg.Role {
+		case entity.USER:
+			transcript.WriteString("用户：")
+		case entity.ASSISTANT:
+			transcript.WriteString("助手：")
+			for _, toolCall := range msg.ToolCalls {
+				transcript.WriteString(fmt.Sprintf("（调用工具 %s，参数 %s）", toolCall.Function.Name, toolCall.Function.Arguments))
+			}
+		case entity.TOOL:
+			transcript.WriteString("工具结果：")
+		default:
+			continue
+		}
+		transcript.WriteString(content)
+		transcript.WriteString("\n")
+	}
+
+	resp, err := a.SummaryAiClient.Generate(ctx, []*schema.Message{
+		{Role: schema.System, Content: conversationSummaryPrompt},
+		{Role: schema.User, Content: transcript.String()},
+	})
+	if err != nil {
+		zlog.CtxErrorf(ctx, "对话摘要生成失败: %v", err)
+		return "", err
+	}
+	if resp == nil || strings.TrimSpace(resp.Content) == "" {
+		return "", errors.New("对话摘要为空")
+	}
+	return strings.TrimSpace(resp.Content), nil
+}
@@ -20,7 +20,13 @@ const mindMapSchemaString = `{
             "text": {"type": "string"},
             "expand": {"type": "boolean"},
             "isActive": {"type": "boolean"},
-            "uid": {"type": "string"}
+            "uid": {"type": "string"},
+            "note": {"type": "string"},
+            "hyperlink": {"type": "string"},
+            "hyperlinkTitle": {"type": "string"},
+            "color": {"type": "string"},
+            "icon": {"type": "array", "items": {"type": "string"}},
+            "tag": {"type": "array", "items": {"type": "string"}}
           },
           "required": ["text", "uid"]
         },
@@ -48,7 +54,13 @@ const mindMapSchemaString = `{
             "text": {"type": "string"},
             "expand": {"type": "boolean"},
             "isActive": {"type": "boolean"},
-            "uid": {"type": "string"}
+            "uid": {"type": "string"},
+            "note": {"type": "string"},
+            "hyperlink": {"type": "string"},
+            "hyperlinkTitle": {"type": "string"},
+            "color": {"type": "string"},
+            "icon": {"type": "array", "items": {"type": "string"}},
+            "tag": {"type": "array", "items": {"type": "string"}}
           },
           "required": ["text", "uid"]
         },
@@ -0,0 +1,183 @@
+package eino
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"forge/infra/configs"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino-ext/components/model/ark"
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/eino-contrib/jsonschema"
+	arkmodel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+)
+
+// 模型用途
+const (
+	ModelRoleChat     = "chat"     // 对话agent
+	ModelRoleSummary  = "summary"  // 工具调用总结与对话摘要，未配置时与 chat 相同
+	ModelRoleTool     = "tool"     // 导图编辑工具
+	ModelRoleGenerate = "generate" // 导图生成
+	ModelRoleTab      = "tab"      // Tab补全
+	ModelRoleQuality  = "quality"  // 质量评估
+)
+
+// 模型服务商类型
+const (
+	ProviderTypeArk    = "ark"    // 火山方舟
+	ProviderTypeOpenAI = "openai" // OpenAI兼容接口，Ollama、llama.cpp、vLLM 等本地服务也使用该类型
+)
+
+// ChatModelOptions 调用方对模型的要求，由各服务商转换为自己的参数
+type ChatModelOptions struct {
+	Thinking   bool              // 是否开启深度思考，不支持的服务商忽略
+	JSONSchema *JSONSchemaOutput // 结构化输出，为空时输出普通文本
+}
+
+// JSONSchemaOutput 结构化输出格式（严格模式）
+type JSONSchemaOutput struct {
+	Name        string
+	Description string
+	Schema      map[string]interface{}
+}
+
+// ChatModelBuilder 按服务商配置构建eino模型
+type ChatModelBuilder func(ctx context.Context, provider configs.LLMProviderConfig, modelName string, opts ChatModelOptions) (model.ToolCallingChatModel, error)
+
+var (
+	providerMu       sync.RWMutex
+	providerBuilders = map[string]ChatModelBuilder{
+		ProviderTypeArk:    newArkChatModel,
+		ProviderTypeOpenAI: newOpenAIChatModel,
+	}
+)
+
+// RegisterProvider 注册服务商类型，用于接入新的服务商或在离线测试中替换为本地模型
+func RegisterProvider(providerType string, builder ChatModelBuilder) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	providerBuilders[providerType] = builder
+}
+
+// ResolveModel 获取某个用途使用的服务商与模型名，
+// models 中未配置该用途时沿用 api_key/model_name（Tab补全、质量评估为各自的 key 与模型）访问火山方舟
+func ResolveModel(role string) (configs.LLMProviderConfig, string, error) {
+	aiConfig := configs.Config().GetAiChatConfig()
+
+	modelConfig, ok := aiConfig.Models[role]
+	if !ok {
+		switch role {
+		case ModelRoleSummary:
+			return ResolveModel(ModelRoleChat)
+		case ModelRoleTab:
+			return configs.LLMProviderConfig{Type: ProviderTypeArk, ApiKey: aiConfig.TabApiKey}, aiConfig.TabModelName, nil
+		case ModelRoleQuality:
+			return configs.LLMProviderConfig{Type: ProviderTypeArk, ApiKey: aiConfig.QualityApiKey}, aiConfig.QualityModelName, nil
+		default:
+			return configs.LLMProviderConfig{Type: ProviderTypeArk, ApiKey: aiConfig.ApiKey}, aiConfig.ModelName, nil
+		}
+	}
+
+	// 配置文件中的键名会被转为小写
+	providerName := strings.ToLower(modelConfig.Provider)
+	provider, ok := aiConfig.Providers[providerName]
+	if !ok {
+		return configs.LLMProviderConfig{}, "", fmt.Errorf("用途 %s 使用的模型服务商 %s 未配置", role, modelConfig.Provider)
+	}
+	if provider.Type == "" {
+		provider.Type = providerName
+	}
+	provider.Type = strings.ToLower(provider.Type)
+	if modelConfig.Model == "" {
+		return configs.LLMProviderConfig{}, "", fmt.Errorf("用途 %s 未配置模型名称", role)
+	}
+	return provider, modelConfig.Model, nil
+}
+
+// ResolveModelName 获取某个用途使用的模型名，配置错误时返回空字符串
+func ResolveModelName(role string) string {
+	_, modelName, err := ResolveModel(role)
+	if err != nil {
+		return ""
+	}
+	return modelName
+}
+
+// NewChatModel 按用途从配置构建模型
+func NewChatModel(ctx context.Context, role string, opts ChatModelOptions) (model.ToolCallingChatModel, error) {
+	provider, modelName, err := ResolveModel(role)
+	if err != nil {
+		return nil, err
+	}
+
+	providerMu.RLock()
+	builder, ok := providerBuilders[provider.Type]
+	providerMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("不支持的模型服务商类型: %s", provider.Type)
+	}
+
+	chatModel, err := builder(ctx, provider, modelName, opts)
+	if err != nil {
+		return nil, fmt.Errorf("创建%s模型失败（%s/%s）: %w", role, provider.Type, modelName, err)
+	}
+	return chatModel, nil
+}
+
+func newArkChatModel(ctx context.Context, provider configs.LLMProviderConfig, modelName string, opts ChatModelOptions) (model.ToolCallingChatModel, error) {
+	thinking := arkmodel.ThinkingTypeDisabled
+	if opts.Thinking {
+		thinking = arkmodel.ThinkingTypeEnabled
+	}
+
+	config := &ark.ChatModelConfig{
+		APIKey:   provider.ApiKey,
+		BaseURL:  provider.BaseURL,
+		Model:    modelName,
+		Thinking: &arkmodel.Thinking{Type: thinking},
+	}
+	if opts.JSONSchema != nil {
+		config.ResponseFormat = &ark.ResponseFormat{
+			Type: arkmodel.ResponseFormatJSONSchema,
+			JSONSchema: &arkmodel.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:        opts.JSONSchema.Name,
+				Description: opts.JSONSchema.Description,
+				Schema:      opts.JSONSchema.Schema,
+				Strict:      true,
+			},
+		}
+	}
+	return ark.NewChatModel(ctx, config)
+}
+
+func newOpenAIChatModel(ctx context.Context, provider configs.LLMProviderConfig, modelName string, opts ChatModelOptions) (model.ToolCallingChatModel, error) {
+	config := &openai.ChatModelConfig{
+		APIKey:  provider.ApiKey,
+		BaseURL: provider.BaseURL,
+		Model:   modelName,
+	}
+	if opts.JSONSchema != nil {
+		// OpenAI SDK 使用强类型的 schema，通过JSON转换
+		schemaBytes, err := json.Marshal(opts.JSONSchema.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("序列化JSON Schema失败: %w", err)
+		}
+		schema := &jsonschema.Schema{}
+		if err := json.Unmarshal(schemaBytes, schema); err != nil {
+			return nil, fmt.Errorf("解析JSON Schema失败: %w", err)
+		}
+		config.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:        opts.JSONSchema.Name,
+				Description: opts.JSONSchema.Description,
+				JSONSchema:  schema,
+				Strict:      true,
+			},
+		}
+	}
+	return openai.NewChatModel(ctx, config)
+}
@@ -3,40 +3,30 @@ package eino
 import (
 	"context"
 	"fmt"
-	"forge/infra/configs"
 	"forge/pkg/log/zlog"
 	"strconv"
 	"strings"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
-	arkmodel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 )
 
 type QualityAssessmentClient struct {
-	ApiKey    string
 	ModelName string
-	ChatModel *ark.ChatModel
+	ChatModel model.ToolCallingChatModel
 }
 
 func NewQualityAssessmentClient() *QualityAssessmentClient {
-	config := configs.Config().GetAiChatConfig()
-	
-	// 使用 Eino 框架创建模型客户端
+	// 使用 Eino 框架创建模型客户端，模型由配置中的 quality 用途决定
 	ctx := context.Background()
-	chatModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:   config.QualityApiKey,
-		Model:    config.QualityModelName,
-		Thinking: &arkmodel.Thinking{Type: arkmodel.ThinkingTypeDisabled},
-	})
+	chatModel, err := NewChatModel(ctx, ModelRoleQuality, ChatModelOptions{})
 	if err != nil {
 		zlog.Errorf("创建质量评估模型失败: %v", err)
 		// 如果创建失败，返回 nil 模型，在调用时会处理
 	}
 
 	return &QualityAssessmentClient{
-		ApiKey:    config.QualityApiKey,
-		ModelName: config.QualityModelName,
+		ModelName: ResolveModelName(ModelRoleQuality),
 		ChatModel: chatModel,
 	}
 }
@@ -126,11 +116,7 @@ func (q *QualityAssessmentClient) parseQualityScore(response string) (int, error
 // AssessQualityWithEino 使用eino架构的质量评估（备用方案）
 func (q *QualityAssessmentClient) AssessQualityWithEino(ctx context.Context, userInput, mapData string) (int, error) {
 	// 创建eino模型客户端
-	qualityModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:   q.ApiKey,
-		Model:    q.ModelName,
-		Thinking: &arkmodel.Thinking{Type: arkmodel.ThinkingTypeDisabled},
-	})
+	qualityModel, err := NewChatModel(ctx, ModelRoleQuality, ChatModelOptions{})
 	if err != nil {
 		return 0, fmt.Errorf("创建质量评估模型失败: %w", err)
 	}
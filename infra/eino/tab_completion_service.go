@@ -4,38 +4,28 @@ import (
 	"context"
 	"fmt"
 	"forge/biz/entity"
-	"forge/infra/configs"
 	"forge/pkg/log/zlog"
 
-	"github.com/cloudwego/eino-ext/components/model/ark"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
-	arkmodel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 )
 
 type TabCompletionClient struct {
-	ApiKey    string
 	ModelName string
-	ChatModel *ark.ChatModel
+	ChatModel model.ToolCallingChatModel
 }
 
 func NewTabCompletionClient() *TabCompletionClient {
-	config := configs.Config().GetAiChatConfig()
-
-	// 使用 Eino 框架创建模型客户端
+	// 使用 Eino 框架创建模型客户端，模型由配置中的 tab 用途决定
 	ctx := context.Background()
-	chatModel, err := ark.NewChatModel(ctx, &ark.ChatModelConfig{
-		APIKey:   config.TabApiKey,
-		Model:    config.TabModelName,
-		Thinking: &arkmodel.Thinking{Type: arkmodel.ThinkingTypeDisabled},
-	})
+	chatModel, err := NewChatModel(ctx, ModelRoleTab, ChatModelOptions{})
 	if err != nil {
 		zlog.Errorf("创建Tab补全模型失败: %v", err)
 		// 如果创建失败，返回 nil 模型，在调用时会处理
 	}
 
 	return &TabCompletionClient{
-		ApiKey:    config.TabApiKey,
-		ModelName: config.TabModelName,
+		ModelName: ResolveModelName(ModelRoleTab),
 		ChatModel: chatModel,
 	}
 }
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"forge/biz/aichatservice"
+	"forge/biz/entity"
+	"forge/infra/storage/po"
+
+	"gorm.io/gorm"
+)
+
+func (a *aiChatPersistence) CreateMapProposal(ctx context.Context, proposal *entity.MapProposal) error {
+	if proposal.ConversationID == "" {
+		return aichatservice.CONVERSATION_ID_NOT_NULL
+	} else if proposal.UserID == "" {
+		return aichatservice.USER_ID_NOT_NULL
+	} else if proposal.MapID == "" {
+		return aichatservice.MAP_ID_NOT_NULL
+	}
+
+	proposalPO, err := CastMapProposalDO2PO(proposal)
+	if err != nil {
+		return err
+	}
+
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&po.MapProposalPO{}).
+			Where("conversation_id = ? AND status = ?", proposal.ConversationID, entity.MapProposalPending).
+			Update("status", entity.MapProposalSuperseded).Error
+		if err != nil {
+			return fmt.Errorf("更新旧修改建议时 数据库出错 %w", err)
+		}
+
+		if err := tx.Create(proposalPO).Error; err != nil {
+			return fmt.Errorf("保存修改建议时 数据库出错 %w", err)
+		}
+		proposal.CreatedAt = proposalPO.CreatedAt
+		return nil
+	})
+}
+
+func (a *aiChatPersistence) GetMapProposal(ctx context.Context, proposalID, userID string) (*entity.MapProposal, error) {
+	if proposalID == "" {
+		return nil, aichatservice.MAP_PROPOSAL_NOT_EXIST
+	} else if userID == "" {
+		return nil, aichatservice.USER_ID_NOT_NULL
+	}
+
+	var proposalPO po.MapProposalPO
+	err := a.db.WithContext(ctx).Model(&po.MapProposalPO{}).
+		Where("proposal_id = ? AND user_id = ?", proposalID, userID).
+		First(&proposalPO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, aichatservice.MAP_PROPOSAL_NOT_EXIST
+		}
+		return nil, fmt.Errorf("获取修改建议时 数据库出错 %w", err)
+	}
+
+	return CastMapProposalPO2DO(&proposalPO)
+}
+
+func (a *aiChatPersistence) ListMapProposals(ctx context.Context, conversationID, userID string) ([]*entity.MapProposal, error) {
+	if conversationID == "" {
+		return nil, aichatservice.CONVERSATION_ID_NOT_NULL
+	} else if userID == "" {
+		return nil, aichatservice.USER_ID_NOT_NULL
+	}
+
+	var proposalPOs []po.MapProposalPO
+	err := a.db.WithContext(ctx).Model(&po.MapProposalPO{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Order("id DESC").
+		Find(&proposalPOs).Error
+	if err != nil {
+		return nil, fmt.Errorf("获取修改建议时 数据库出错 %w", err)
+	}
+
+	proposals := make([]*entity.MapProposal, 0, len(proposalPOs))
+	for i := range proposalPOs {
+		proposal, err := CastMapProposalPO2DO(&proposalPOs[i])
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals, nil
+}
+
+func (a *aiChatPersistence) ResolveMapProposal(ctx context.Context, proposal *entity.MapProposal) error {
+	if proposal.ProposalID == "" {
+		return aichatservice.MAP_PROPOSAL_NOT_EXIST
+	}
+
+	// 只有待处理的建议可以写入结果，避免重复采纳或拒绝
+	result := a.db.WithContext(ctx).Model(&po.MapProposalPO{}).
+		Where("proposal_id = ? AND status = ?", proposal.ProposalID, entity.MapProposalPending).
+		Updates(map[string]interface{}{
+			"status":          proposal.Status,
+			"applied_version": proposal.AppliedVersion,
+			"reject_reason":   proposal.RejectReason,
+			"resolved_at":     proposal.ResolvedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("更新修改建议时 数据库出错 %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return aichatservice.MAP_PROPOSAL_RESOLVED
+	}
+	return nil
+}
@@ -23,7 +23,7 @@ var cp *aiChatPersistence
 func InitAiChatStorage() {
 	db := database.ForgeDB()
 
-	if err := db.AutoMigrate(&po.ConversationPO{}); err != nil {
+	if err := db.AutoMigrate(&po.ConversationPO{}, &po.MapProposalPO{}); err != nil {
 		panic(fmt.Sprintf("自动建表失败 :%v", err))
 	}
 
@@ -133,6 +133,9 @@ func (a *aiChatPersistence) UpdateConversationMessage(ctx context.Context, conve
 	if conversationPO.Messages != nil {
 		Updates["messages"] = conversationPO.Messages
 	}
+	Updates["current_message_id"] = conversationPO.CurrentMessageID
+	Updates["summary"] = conversationPO.Summary
+	Updates["summary_until_id"] = conversationPO.SummaryUntilID
 
 	err = a.db.WithContext(ctx).Model(&po.ConversationPO{}).Where("conversation_id = ? AND user_id = ?", conversationPO.ConversationID, conversationPO.UserID).Updates(Updates).Error
 	if err != nil {
@@ -187,6 +190,11 @@ func (a *aiChatPersistence) DeleteConversation(ctx context.Context, conversation
 	if result.Error != nil {
 		return fmt.Errorf("删除会话时出错 %w", result.Error)
 	}
+
+	//会话删除后其修改建议不再可处理
+	if err := a.db.WithContext(ctx).Where("conversation_id = ?", conversationID).Delete(&po.MapProposalPO{}).Error; err != nil {
+		return fmt.Errorf("删除会话修改建议时出错 %w", err)
+	}
 	return nil
 }
 
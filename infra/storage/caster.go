@@ -82,12 +82,17 @@ func CastMindMapDO2PO(mindmap *entity.MindMap) (*po.MindMapPO, error) {
 	}
 
 	mindmapPO := &po.MindMapPO{
-		MapID:  mindmap.MapID,
-		UserID: mindmap.UserID,
-		Title:  mindmap.Title,
-		Desc:   mindmap.Desc,
-		Data:   string(dataBytes),
-		Layout: mindmap.Layout,
+		MapID:    mindmap.MapID,
+		UserID:   mindmap.UserID,
+		Title:    mindmap.Title,
+		Desc:     mindmap.Desc,
+		Data:     string(dataBytes),
+		Layout:   mindmap.Layout,
+		Version:  mindmap.Version,
+		FolderID: mindmap.FolderID,
+
+		ThumbnailURL:     mindmap.ThumbnailURL,
+		ThumbnailVersion: mindmap.ThumbnailVersion,
 	}
 
 	// 处理时间字段
@@ -114,12 +119,17 @@ func CastMindMapPO2DO(mindmapPO *po.MindMapPO) (*entity.MindMap, error) {
 	}
 
 	mindmap := &entity.MindMap{
-		MapID:  mindmapPO.MapID,
-		UserID: mindmapPO.UserID,
-		Title:  mindmapPO.Title,
-		Desc:   mindmapPO.Desc,
-		Data:   data,
-		Layout: mindmapPO.Layout,
+		MapID:    mindmapPO.MapID,
+		UserID:   mindmapPO.UserID,
+		Title:    mindmapPO.Title,
+		Desc:     mindmapPO.Desc,
+		Data:     data,
+		Layout:   mindmapPO.Layout,
+		Version:  mindmapPO.Version,
+		FolderID: mindmapPO.FolderID,
+
+		ThumbnailURL:     mindmapPO.ThumbnailURL,
+		ThumbnailVersion: mindmapPO.ThumbnailVersion,
 	}
 
 	// 处理时间字段
@@ -129,10 +139,326 @@ func CastMindMapPO2DO(mindmapPO *po.MindMapPO) (*entity.MindMap, error) {
 	if mindmapPO.UpdatedAt != nil {
 		mindmap.UpdatedAt = *mindmapPO.UpdatedAt
 	}
+	mindmap.DeletedAt = mindmapPO.DeletedAt
 
 	return mindmap, nil
 }
 
+// BuildMindMapRevisionPO 根据导图当前存储状态构建历史版本快照
+func BuildMindMapRevisionPO(mindmapPO *po.MindMapPO, userID, source string) *po.MindMapRevisionPO {
+	if mindmapPO == nil {
+		return nil
+	}
+	return &po.MindMapRevisionPO{
+		MapID:   mindmapPO.MapID,
+		Version: mindmapPO.Version,
+		UserID:  userID,
+		Title:   mindmapPO.Title,
+		Desc:    mindmapPO.Desc,
+		Data:    mindmapPO.Data,
+		Layout:  mindmapPO.Layout,
+		Source:  source,
+	}
+}
+
+// CastMindMapRevisionPO2DO 历史版本持久化对象转领域对象（Data为空时不反序列化）
+func CastMindMapRevisionPO2DO(revisionPO *po.MindMapRevisionPO) (*entity.MindMapRevision, error) {
+	if revisionPO == nil {
+		return nil, nil
+	}
+
+	revision := &entity.MindMapRevision{
+		MapID:   revisionPO.MapID,
+		Version: revisionPO.Version,
+		UserID:  revisionPO.UserID,
+		Title:   revisionPO.Title,
+		Desc:    revisionPO.Desc,
+		Layout:  revisionPO.Layout,
+		Source:  revisionPO.Source,
+	}
+
+	if revisionPO.Data != "" {
+		if err := json.Unmarshal([]byte(revisionPO.Data), &revision.Data); err != nil {
+			return nil, fmt.Errorf("unmarshal revision data failed: %w", err)
+		}
+	}
+	if revisionPO.CreatedAt != nil {
+		revision.CreatedAt = *revisionPO.CreatedAt
+	}
+
+	return revision, nil
+}
+
+// CastMindMapShareDO2PO 分享链接领域对象转持久化对象
+func CastMindMapShareDO2PO(share *entity.MindMapShare) *po.MindMapSharePO {
+	if share == nil {
+		return nil
+	}
+	return &po.MindMapSharePO{
+		ShareID:      share.ShareID,
+		Token:        share.Token,
+		MapID:        share.MapID,
+		UserID:       share.UserID,
+		PasswordHash: share.PasswordHash,
+		ExpiresAt:    share.ExpiresAt,
+		RevokedAt:    share.RevokedAt,
+		HitCount:     share.HitCount,
+		LastHitAt:    share.LastHitAt,
+	}
+}
+
+// CastMindMapSharePO2DO 分享链接持久化对象转领域对象
+func CastMindMapSharePO2DO(sharePO *po.MindMapSharePO) *entity.MindMapShare {
+	if sharePO == nil {
+		return nil
+	}
+	share := &entity.MindMapShare{
+		ShareID:      sharePO.ShareID,
+		Token:        sharePO.Token,
+		MapID:        sharePO.MapID,
+		UserID:       sharePO.UserID,
+		PasswordHash: sharePO.PasswordHash,
+		ExpiresAt:    sharePO.ExpiresAt,
+		RevokedAt:    sharePO.RevokedAt,
+		HitCount:     sharePO.HitCount,
+		LastHitAt:    sharePO.LastHitAt,
+	}
+	if sharePO.CreatedAt != nil {
+		share.CreatedAt = *sharePO.CreatedAt
+	}
+	return share
+}
+
+// CastMindMapCollaboratorDO2PO 协作者领域对象转持久化对象
+func CastMindMapCollaboratorDO2PO(collaborator *entity.MindMapCollaborator) *po.MindMapCollaboratorPO {
+	if collaborator == nil {
+		return nil
+	}
+	return &po.MindMapCollaboratorPO{
+		MapID:     collaborator.MapID,
+		UserID:    collaborator.UserID,
+		Role:      collaborator.Role,
+		InvitedBy: collaborator.InvitedBy,
+	}
+}
+
+// CastMindMapCollaboratorPO2DO 协作者持久化对象转领域对象
+func CastMindMapCollaboratorPO2DO(collaboratorPO *po.MindMapCollaboratorPO) *entity.MindMapCollaborator {
+	if collaboratorPO == nil {
+		return nil
+	}
+	collaborator := &entity.MindMapCollaborator{
+		MapID:     collaboratorPO.MapID,
+		UserID:    collaboratorPO.UserID,
+		Role:      collaboratorPO.Role,
+		InvitedBy: collaboratorPO.InvitedBy,
+	}
+	if collaboratorPO.CreatedAt != nil {
+		collaborator.CreatedAt = *collaboratorPO.CreatedAt
+	}
+	if collaboratorPO.UpdatedAt != nil {
+		collaborator.UpdatedAt = *collaboratorPO.UpdatedAt
+	}
+	return collaborator
+}
+
+// CastMindMapSearchDocDO2PO 检索文档领域对象转持久化对象
+func CastMindMapSearchDocDO2PO(doc *entity.MindMapNodeDoc) (*po.MindMapSearchDocPO, error) {
+	path := doc.Path
+	if path == nil {
+		path = []string{}
+	}
+	pathBytes, err := json.Marshal(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search doc path: %w", err)
+	}
+	return &po.MindMapSearchDocPO{
+		MapID:   doc.MapID,
+		NodeUID: doc.NodeUID,
+		Text:    doc.Text,
+		Note:    doc.Note,
+		Path:    string(pathBytes),
+		Depth:   doc.Depth,
+	}, nil
+}
+
+// CastMindMapSearchDocPO2DO 检索文档持久化对象转领域对象
+func CastMindMapSearchDocPO2DO(docPO *po.MindMapSearchDocPO) (*entity.MindMapNodeDoc, error) {
+	doc := &entity.MindMapNodeDoc{
+		MapID:   docPO.MapID,
+		NodeUID: docPO.NodeUID,
+		Text:    docPO.Text,
+		Note:    docPO.Note,
+		Depth:   docPO.Depth,
+	}
+	if docPO.Path != "" {
+		if err := json.Unmarshal([]byte(docPO.Path), &doc.Path); err != nil {
+			return nil, fmt.Errorf("unmarshal search doc path failed: %w", err)
+		}
+	}
+	return doc, nil
+}
+
+// CastMindMapFolderDO2PO 文件夹领域对象转持久化对象
+func CastMindMapFolderDO2PO(folder *entity.MindMapFolder) *po.MindMapFolderPO {
+	if folder == nil {
+		return nil
+	}
+	return &po.MindMapFolderPO{
+		FolderID: folder.FolderID,
+		UserID:   folder.UserID,
+		ParentID: folder.ParentID,
+		Name:     folder.Name,
+	}
+}
+
+// CastMindMapFolderPO2DO 文件夹持久化对象转领域对象
+func CastMindMapFolderPO2DO(folderPO *po.MindMapFolderPO) *entity.MindMapFolder {
+	if folderPO == nil {
+		return nil
+	}
+	folder := &entity.MindMapFolder{
+		FolderID: folderPO.FolderID,
+		UserID:   folderPO.UserID,
+		ParentID: folderPO.ParentID,
+		Name:     folderPO.Name,
+	}
+	if folderPO.CreatedAt != nil {
+		folder.CreatedAt = *folderPO.CreatedAt
+	}
+	if folderPO.UpdatedAt != nil {
+		folder.UpdatedAt = *folderPO.UpdatedAt
+	}
+	return folder
+}
+
+// CastMindMapTagDO2PO 标签领域对象转持久化对象
+func CastMindMapTagDO2PO(tag *entity.MindMapTag) *po.MindMapTagPO {
+	if tag == nil {
+		return nil
+	}
+	return &po.MindMapTagPO{
+		TagID:  tag.TagID,
+		UserID: tag.UserID,
+		Name:   tag.Name,
+		Color:  tag.Color,
+	}
+}
+
+// CastMindMapTagPO2DO 标签持久化对象转领域对象
+func CastMindMapTagPO2DO(tagPO *po.MindMapTagPO) *entity.MindMapTag {
+	if tagPO == nil {
+		return nil
+	}
+	tag := &entity.MindMapTag{
+		TagID:  tagPO.TagID,
+		UserID: tagPO.UserID,
+		Name:   tagPO.Name,
+		Color:  tagPO.Color,
+	}
+	if tagPO.CreatedAt != nil {
+		tag.CreatedAt = *tagPO.CreatedAt
+	}
+	if tagPO.UpdatedAt != nil {
+		tag.UpdatedAt = *tagPO.UpdatedAt
+	}
+	return tag
+}
+
+// CastMindMapTemplateDO2PO 模板领域对象转持久化对象
+func CastMindMapTemplateDO2PO(template *entity.MindMapTemplate) (*po.MindMapTemplatePO, error) {
+	if template == nil {
+		return nil, nil
+	}
+
+	dataBytes, err := json.Marshal(template.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template data: %w", err)
+	}
+
+	return &po.MindMapTemplatePO{
+		TemplateID: template.TemplateID,
+		UserID:     template.UserID,
+		Name:       template.Name,
+		Desc:       template.Desc,
+		Category:   template.Category,
+		Layout:     template.Layout,
+		Data:       string(dataBytes),
+	}, nil
+}
+
+// CastMindMapTemplatePO2DO 模板持久化对象转领域对象（Data为空时不反序列化）
+func CastMindMapTemplatePO2DO(templatePO *po.MindMapTemplatePO) (*entity.MindMapTemplate, error) {
+	if templatePO == nil {
+		return nil, nil
+	}
+
+	template := &entity.MindMapTemplate{
+		TemplateID: templatePO.TemplateID,
+		UserID:     templatePO.UserID,
+		Name:       templatePO.Name,
+		Desc:       templatePO.Desc,
+		Category:   templatePO.Category,
+		Layout:     templatePO.Layout,
+	}
+
+	if templatePO.Data != "" {
+		if err := json.Unmarshal([]byte(templatePO.Data), &template.Data); err != nil {
+			return nil, fmt.Errorf("unmarshal template data failed: %w", err)
+		}
+	}
+	if templatePO.CreatedAt != nil {
+		template.CreatedAt = *templatePO.CreatedAt
+	}
+	if templatePO.UpdatedAt != nil {
+		template.UpdatedAt = *templatePO.UpdatedAt
+	}
+
+	return template, nil
+}
+
+// CastMindMapNodeLinkDO2PO 节点链接领域对象转持久化对象
+func CastMindMapNodeLinkDO2PO(link *entity.MindMapNodeLink) *po.MindMapNodeLinkPO {
+	if link == nil {
+		return nil
+	}
+	return &po.MindMapNodeLinkPO{
+		LinkID:        link.LinkID,
+		SourceMapID:   link.SourceMapID,
+		SourceNodeUID: link.SourceNodeUID,
+		TargetMapID:   link.TargetMapID,
+		TargetNodeUID: link.TargetNodeUID,
+		Type:          link.Type,
+		Label:         link.Label,
+		UserID:        link.UserID,
+		BrokenReason:  link.BrokenReason,
+		BrokenAt:      link.BrokenAt,
+	}
+}
+
+// CastMindMapNodeLinkPO2DO 节点链接持久化对象转领域对象
+func CastMindMapNodeLinkPO2DO(linkPO *po.MindMapNodeLinkPO) *entity.MindMapNodeLink {
+	if linkPO == nil {
+		return nil
+	}
+	link := &entity.MindMapNodeLink{
+		LinkID:        linkPO.LinkID,
+		SourceMapID:   linkPO.SourceMapID,
+		SourceNodeUID: linkPO.SourceNodeUID,
+		TargetMapID:   linkPO.TargetMapID,
+		TargetNodeUID: linkPO.TargetNodeUID,
+		Type:          linkPO.Type,
+		Label:         linkPO.Label,
+		UserID:        linkPO.UserID,
+		BrokenReason:  linkPO.BrokenReason,
+		BrokenAt:      linkPO.BrokenAt,
+	}
+	if linkPO.CreatedAt != nil {
+		link.CreatedAt = *linkPO.CreatedAt
+	}
+	return link
+}
+
 func CastConversationPO2DO(conversationPO *po.ConversationPO) (*entity.Conversation, error) {
 	if conversationPO == nil {
 		return nil, nil
@@ -144,13 +470,16 @@ func CastConversationPO2DO(conversationPO *po.ConversationPO) (*entity.Conversat
 	}
 
 	return &entity.Conversation{
-		ConversationID: conversationPO.ConversationID,
-		UserID:         conversationPO.UserID,
-		MapID:          conversationPO.MapID,
-		Title:          conversationPO.Title,
-		Messages:       messages,
-		CreatedAt:      conversationPO.CreatedAt,
-		UpdatedAt:      conversationPO.UpdatedAt,
+		ConversationID:   conversationPO.ConversationID,
+		UserID:           conversationPO.UserID,
+		MapID:            conversationPO.MapID,
+		Title:            conversationPO.Title,
+		Messages:         messages,
+		CurrentMessageID: conversationPO.CurrentMessageID,
+		Summary:          conversationPO.Summary,
+		SummaryUntilID:   conversationPO.SummaryUntilID,
+		CreatedAt:        conversationPO.CreatedAt,
+		UpdatedAt:        conversationPO.UpdatedAt,
 	}, nil
 
 }
@@ -183,14 +512,83 @@ func CastConversationDO2PO(conversation *entity.Conversation) (*po.ConversationP
 	}
 
 	conversationPO := &po.ConversationPO{
-		ConversationID: conversation.ConversationID,
-		UserID:         conversation.UserID,
-		MapID:          conversation.MapID,
-		Title:          conversation.Title,
-		Messages:       datatypes.JSON(jsonBytes),
-		CreatedAt:      conversation.CreatedAt,
-		UpdatedAt:      conversation.UpdatedAt,
+		ConversationID:   conversation.ConversationID,
+		UserID:           conversation.UserID,
+		MapID:            conversation.MapID,
+		Title:            conversation.Title,
+		Messages:         datatypes.JSON(jsonBytes),
+		CurrentMessageID: conversation.CurrentMessageID,
+		Summary:          conversation.Summary,
+		SummaryUntilID:   conversation.SummaryUntilID,
+		CreatedAt:        conversation.CreatedAt,
+		UpdatedAt:        conversation.UpdatedAt,
 	}
 	return conversationPO, nil
 
 }
+
+func CastMapProposalDO2PO(proposal *entity.MapProposal) (*po.MapProposalPO, error) {
+	if proposal == nil {
+		return nil, nil
+	}
+
+	dataBytes, err := json.Marshal(proposal.Data)
+	if err != nil {
+		return nil, fmt.Errorf("json序列化失败: %w", err)
+	}
+	diffBytes, err := json.Marshal(proposal.Diff)
+	if err != nil {
+		return nil, fmt.Errorf("json序列化失败: %w", err)
+	}
+
+	return &po.MapProposalPO{
+		ProposalID:     proposal.ProposalID,
+		ConversationID: proposal.ConversationID,
+		MapID:          proposal.MapID,
+		UserID:         proposal.UserID,
+		MessageID:      proposal.MessageID,
+		ToolCallID:     proposal.ToolCallID,
+		ToolName:       proposal.ToolName,
+		Requirement:    proposal.Requirement,
+		Data:           datatypes.JSON(dataBytes),
+		BaseVersion:    proposal.BaseVersion,
+		Diff:           datatypes.JSON(diffBytes),
+		Status:         proposal.Status,
+		AppliedVersion: proposal.AppliedVersion,
+		RejectReason:   proposal.RejectReason,
+		ResolvedAt:     proposal.ResolvedAt,
+		CreatedAt:      proposal.CreatedAt,
+	}, nil
+}
+
+func CastMapProposalPO2DO(proposalPO *po.MapProposalPO) (*entity.MapProposal, error) {
+	if proposalPO == nil {
+		return nil, nil
+	}
+
+	proposal := &entity.MapProposal{
+		ProposalID:     proposalPO.ProposalID,
+		ConversationID: proposalPO.ConversationID,
+		MapID:          proposalPO.MapID,
+		UserID:         proposalPO.UserID,
+		MessageID:      proposalPO.MessageID,
+		ToolCallID:     proposalPO.ToolCallID,
+		ToolName:       proposalPO.ToolName,
+		Requirement:    proposalPO.Requirement,
+		BaseVersion:    proposalPO.BaseVersion,
+		Status:         proposalPO.Status,
+		AppliedVersion: proposalPO.AppliedVersion,
+		RejectReason:   proposalPO.RejectReason,
+		ResolvedAt:     proposalPO.ResolvedAt,
+		CreatedAt:      proposalPO.CreatedAt,
+	}
+	if err := json.Unmarshal(proposalPO.Data, &proposal.Data); err != nil {
+		return nil, fmt.Errorf("反序列化失败: %w", err)
+	}
+	if len(proposalPO.Diff) > 0 {
+		if err := json.Unmarshal(proposalPO.Diff, &proposal.Diff); err != nil {
+			return nil, fmt.Errorf("反序列化失败: %w", err)
+		}
+	}
+	return proposal, nil
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/infra/storage/po"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetMindMapRole 查询用户在导图上的角色
+func (m *mindMapPersistence) GetMindMapRole(ctx context.Context, mapID string, userID string) (string, error) {
+	if mapID == "" || userID == "" {
+		return "", fmt.Errorf("MapID and UserID are required")
+	}
+
+	var mindmapPO po.MindMapPO
+	if err := m.db.WithContext(ctx).
+		Select("map_id", "user_id").
+		Where("map_id = ? AND is_deleted = 0", mapID).
+		First(&mindmapPO).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", repo.ErrMindMapNotFound
+		}
+		return "", fmt.Errorf("get mindmap owner failed: %w", err)
+	}
+	if mindmapPO.UserID == userID {
+		return entity.MindMapRoleOwner, nil
+	}
+
+	var collaboratorPO po.MindMapCollaboratorPO
+	if err := m.db.WithContext(ctx).
+		Where("map_id = ? AND user_id = ?", mapID, userID).
+		First(&collaboratorPO).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("get mindmap collaborator failed: %w", err)
+	}
+	return collaboratorPO.Role, nil
+}
+
+// UpsertMindMapCollaborator 添加协作者，已存在时更新角色
+func (m *mindMapPersistence) UpsertMindMapCollaborator(ctx context.Context, collaborator *entity.MindMapCollaborator) error {
+	collaboratorPO := CastMindMapCollaboratorDO2PO(collaborator)
+	err := m.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "map_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "invited_by", "updated_at"}),
+	}).Create(collaboratorPO).Error
+	if err != nil {
+		return fmt.Errorf("upsert mindmap collaborator failed: %w", err)
+	}
+	return nil
+}
+
+// ListMindMapCollaborators 查询导图的全部协作者（按加入时间正序）
+func (m *mindMapPersistence) ListMindMapCollaborators(ctx context.Context, mapID string) ([]*entity.MindMapCollaborator, error) {
+	if mapID == "" {
+		return nil, fmt.Errorf("MapID is required")
+	}
+
+	var collaboratorPOs []po.MindMapCollaboratorPO
+	if err := m.db.WithContext(ctx).
+		Where("map_id = ?", mapID).
+		Order("created_at ASC").
+		Find(&collaboratorPOs).Error; err != nil {
+		return nil, fmt.Errorf("list mindmap collaborators failed: %w", err)
+	}
+
+	collaborators := make([]*entity.MindMapCollaborator, 0, len(collaboratorPOs))
+	for i := range collaboratorPOs {
+		collaborators = append(collaborators, CastMindMapCollaboratorPO2DO(&collaboratorPOs[i]))
+	}
+	return collaborators, nil
+}
+
+// RemoveMindMapCollaborator 移除协作者
+func (m *mindMapPersistence) RemoveMindMapCollaborator(ctx context.Context, mapID string, userID string) error {
+	if mapID == "" || userID == "" {
+		return fmt.Errorf("MapID and UserID are required")
+	}
+
+	result := m.db.WithContext(ctx).
+		Where("map_id = ? AND user_id = ?", mapID, userID).
+		Delete(&po.MindMapCollaboratorPO{})
+	if result.Error != nil {
+		return fmt.Errorf("remove mindmap collaborator failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repo.ErrMindMapCollaboratorNotFound
+	}
+	return nil
+}
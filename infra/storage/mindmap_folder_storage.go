@@ -0,0 +1,299 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/infra/storage/po"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateMindMapFolder 创建文件夹
+func (m *mindMapPersistence) CreateMindMapFolder(ctx context.Context, folder *entity.MindMapFolder) error {
+	folderPO := CastMindMapFolderDO2PO(folder)
+	if err := m.db.WithContext(ctx).Create(folderPO).Error; err != nil {
+		return fmt.Errorf("create mindmap folder failed: %w", err)
+	}
+
+	// 回填创建/更新时间，便于上层直接返回
+	if folderPO.CreatedAt != nil {
+		folder.CreatedAt = *folderPO.CreatedAt
+	}
+	if folderPO.UpdatedAt != nil {
+		folder.UpdatedAt = *folderPO.UpdatedAt
+	}
+	return nil
+}
+
+// ListMindMapFolders 查询用户的全部文件夹（按名称排序，由调用方组装树结构）
+func (m *mindMapPersistence) ListMindMapFolders(ctx context.Context, userID string) ([]*entity.MindMapFolder, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("UserID is required")
+	}
+
+	var folderPOs []po.MindMapFolderPO
+	if err := m.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&folderPOs).Error; err != nil {
+		return nil, fmt.Errorf("list mindmap folders failed: %w", err)
+	}
+
+	folders := make([]*entity.MindMapFolder, 0, len(folderPOs))
+	for i := range folderPOs {
+		folders = append(folders, CastMindMapFolderPO2DO(&folderPOs[i]))
+	}
+	return folders, nil
+}
+
+// UpdateMindMapFolder 修改文件夹名称及父文件夹
+func (m *mindMapPersistence) UpdateMindMapFolder(ctx context.Context, folder *entity.MindMapFolder) error {
+	if folder.FolderID == "" || folder.UserID == "" {
+		return fmt.Errorf("FolderID and UserID are required")
+	}
+
+	result := m.db.WithContext(ctx).
+		Model(&po.MindMapFolderPO{}).
+		Where("folder_id = ? AND user_id = ?", folder.FolderID, folder.UserID).
+		Updates(map[string]interface{}{"name": folder.Name, "parent_id": folder.ParentID, "updated_at": time.Now()})
+	if result.Error != nil {
+		return fmt.Errorf("update mindmap folder failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repo.ErrMindMapFolderNotFound
+	}
+	return nil
+}
+
+// DeleteMindMapFolders 删除文件夹，其中的导图（含回收站中的）移出为未归档
+func (m *mindMapPersistence) DeleteMindMapFolders(ctx context.Context, folderIDs []string, userID string) (movedCount int, err error) {
+	if len(folderIDs) == 0 || userID == "" {
+		return 0, fmt.Errorf("FolderIDs and UserID are required")
+	}
+
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&po.MindMapPO{}).
+			Where("folder_id IN ? AND user_id = ?", folderIDs, userID).
+			UpdateColumn("folder_id", "")
+		if result.Error != nil {
+			return fmt.Errorf("move mindmaps out of folders failed: %w", result.Error)
+		}
+		movedCount = int(result.RowsAffected)
+
+		if err := tx.Where("folder_id IN ? AND user_id = ?", folderIDs, userID).
+			Delete(&po.MindMapFolderPO{}).Error; err != nil {
+			return fmt.Errorf("delete mindmap folders failed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return movedCount, nil
+}
+
+// MoveMindMapsToFolder 将用户自己的导图移动到文件夹（folderID 为空表示移出文件夹）
+func (m *mindMapPersistence) MoveMindMapsToFolder(ctx context.Context, mapIDs []string, userID, folderID string) (movedCount int, err error) {
+	if len(mapIDs) == 0 || userID == "" {
+		return 0, fmt.Errorf("MapIDs and UserID are required")
+	}
+
+	// 只修改归属字段，不改变导图版本与更新时间
+	result := m.db.WithContext(ctx).
+		Model(&po.MindMapPO{}).
+		Where("map_id IN ? AND user_id = ? AND is_deleted = 0", mapIDs, userID).
+		UpdateColumn("folder_id", folderID)
+	if result.Error != nil {
+		return 0, fmt.Errorf("move mindmaps to folder failed: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// CreateMindMapTag 创建标签
+func (m *mindMapPersistence) CreateMindMapTag(ctx context.Context, tag *entity.MindMapTag) error {
+	tagPO := CastMindMapTagDO2PO(tag)
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkMindMapTagNameAvailable(tx, tag.UserID, tag.Name, ""); err != nil {
+			return err
+		}
+		if err := tx.Create(tagPO).Error; err != nil {
+			return fmt.Errorf("create mindmap tag failed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 回填创建/更新时间，便于上层直接返回
+	if tagPO.CreatedAt != nil {
+		tag.CreatedAt = *tagPO.CreatedAt
+	}
+	if tagPO.UpdatedAt != nil {
+		tag.UpdatedAt = *tagPO.UpdatedAt
+	}
+	return nil
+}
+
+// ListMindMapTags 查询用户的全部标签（按名称排序）
+func (m *mindMapPersistence) ListMindMapTags(ctx context.Context, userID string) ([]*entity.MindMapTag, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("UserID is required")
+	}
+
+	var tagPOs []po.MindMapTagPO
+	if err := m.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&tagPOs).Error; err != nil {
+		return nil, fmt.Errorf("list mindmap tags failed: %w", err)
+	}
+
+	tags := make([]*entity.MindMapTag, 0, len(tagPOs))
+	for i := range tagPOs {
+		tags = append(tags, CastMindMapTagPO2DO(&tagPOs[i]))
+	}
+	return tags, nil
+}
+
+// UpdateMindMapTag 修改标签名称与颜色
+func (m *mindMapPersistence) UpdateMindMapTag(ctx context.Context, tag *entity.MindMapTag) error {
+	if tag.TagID == "" || tag.UserID == "" {
+		return fmt.Errorf("TagID and UserID are required")
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkMindMapTagNameAvailable(tx, tag.UserID, tag.Name, tag.TagID); err != nil {
+			return err
+		}
+		result := tx.Model(&po.MindMapTagPO{}).
+			Where("tag_id = ? AND user_id = ?", tag.TagID, tag.UserID).
+			Updates(map[string]interface{}{"name": tag.Name, "color": tag.Color, "updated_at": time.Now()})
+		if result.Error != nil {
+			return fmt.Errorf("update mindmap tag failed: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repo.ErrMindMapTagNotFound
+		}
+		return nil
+	})
+}
+
+// DeleteMindMapTag 删除标签及其与导图的关联
+func (m *mindMapPersistence) DeleteMindMapTag(ctx context.Context, tagID, userID string) error {
+	if tagID == "" || userID == "" {
+		return fmt.Errorf("TagID and UserID are required")
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("tag_id = ? AND user_id = ?", tagID, userID).Delete(&po.MindMapTagPO{})
+		if result.Error != nil {
+			return fmt.Errorf("delete mindmap tag failed: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repo.ErrMindMapTagNotFound
+		}
+		if err := tx.Where("tag_id = ?", tagID).Delete(&po.MindMapTagRelPO{}).Error; err != nil {
+			return fmt.Errorf("delete mindmap tag relations failed: %w", err)
+		}
+		return nil
+	})
+}
+
+// BatchGetMindMapTagsByIDs 批量查询指定tagIDs且属于指定用户的标签（用于权限验证）
+func (m *mindMapPersistence) BatchGetMindMapTagsByIDs(ctx context.Context, tagIDs []string, userID string) ([]*entity.MindMapTag, error) {
+	if len(tagIDs) == 0 || userID == "" {
+		return nil, fmt.Errorf("TagIDs and UserID are required")
+	}
+
+	var tagPOs []po.MindMapTagPO
+	if err := m.db.WithContext(ctx).
+		Where("tag_id IN ? AND user_id = ?", tagIDs, userID).
+		Find(&tagPOs).Error; err != nil {
+		return nil, fmt.Errorf("batch get mindmap tags failed: %w", err)
+	}
+
+	tags := make([]*entity.MindMapTag, 0, len(tagPOs))
+	for i := range tagPOs {
+		tags = append(tags, CastMindMapTagPO2DO(&tagPOs[i]))
+	}
+	return tags, nil
+}
+
+// AddMindMapTags 为导图批量添加标签，已存在的关联忽略
+func (m *mindMapPersistence) AddMindMapTags(ctx context.Context, mapIDs, tagIDs []string) (addedCount int, err error) {
+	if len(mapIDs) == 0 || len(tagIDs) == 0 {
+		return 0, fmt.Errorf("MapIDs and TagIDs are required")
+	}
+
+	rels := make([]*po.MindMapTagRelPO, 0, len(mapIDs)*len(tagIDs))
+	for _, mapID := range mapIDs {
+		for _, tagID := range tagIDs {
+			rels = append(rels, &po.MindMapTagRelPO{MapID: mapID, TagID: tagID})
+		}
+	}
+	result := m.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(rels, 500)
+	if result.Error != nil {
+		return 0, fmt.Errorf("add mindmap tags failed: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// RemoveMindMapTags 批量移除导图的标签
+func (m *mindMapPersistence) RemoveMindMapTags(ctx context.Context, mapIDs, tagIDs []string) (removedCount int, err error) {
+	if len(mapIDs) == 0 || len(tagIDs) == 0 {
+		return 0, fmt.Errorf("MapIDs and TagIDs are required")
+	}
+
+	result := m.db.WithContext(ctx).
+		Where("map_id IN ? AND tag_id IN ?", mapIDs, tagIDs).
+		Delete(&po.MindMapTagRelPO{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("remove mindmap tags failed: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// listMindMapTagIDs 查询导图上属于指定用户的标签ID，按导图ID分组
+func (m *mindMapPersistence) listMindMapTagIDs(ctx context.Context, mapIDs []string, userID string) (map[string][]string, error) {
+	tagIDs := make(map[string][]string, len(mapIDs))
+	if len(mapIDs) == 0 {
+		return tagIDs, nil
+	}
+
+	userTagIDs := m.db.Model(&po.MindMapTagPO{}).Select("tag_id").Where("user_id = ?", userID)
+	var rels []po.MindMapTagRelPO
+	if err := m.db.WithContext(ctx).
+		Where("map_id IN ? AND tag_id IN (?)", mapIDs, userTagIDs).
+		Order("id ASC").
+		Find(&rels).Error; err != nil {
+		return nil, fmt.Errorf("list mindmap tag relations failed: %w", err)
+	}
+	for _, rel := range rels {
+		tagIDs[rel.MapID] = append(tagIDs[rel.MapID], rel.TagID)
+	}
+	return tagIDs, nil
+}
+
+// checkMindMapTagNameAvailable 校验同一用户下标签名称未被其他标签占用
+func checkMindMapTagNameAvailable(tx *gorm.DB, userID, name, excludeTagID string) error {
+	var count int64
+	db := tx.Model(&po.MindMapTagPO{}).Where("user_id = ? AND name = ?", userID, name)
+	if excludeTagID != "" {
+		db = db.Where("tag_id <> ?", excludeTagID)
+	}
+	if err := db.Count(&count).Error; err != nil {
+		return fmt.Errorf("check mindmap tag name failed: %w", err)
+	}
+	if count > 0 {
+		return repo.ErrMindMapTagNameExists
+	}
+	return nil
+}
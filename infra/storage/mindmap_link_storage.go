@@ -0,0 +1,313 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/infra/storage/po"
+
+	"gorm.io/gorm"
+)
+
+// CreateMindMapNodeLink 创建节点链接
+func (m *mindMapPersistence) CreateMindMapNodeLink(ctx context.Context, link *entity.MindMapNodeLink) error {
+	linkPO := CastMindMapNodeLinkDO2PO(link)
+	if linkPO == nil || linkPO.LinkID == "" || linkPO.SourceMapID == "" || linkPO.TargetMapID == "" {
+		return fmt.Errorf("LinkID, SourceMapID and TargetMapID are required")
+	}
+
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&po.MindMapNodeLinkPO{}).
+			Where("source_map_id = ? AND source_node_uid = ? AND target_map_id = ? AND target_node_uid = ?",
+				linkPO.SourceMapID, linkPO.SourceNodeUID, linkPO.TargetMapID, linkPO.TargetNodeUID).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("check mindmap node link failed: %w", err)
+		}
+		if count > 0 {
+			return repo.ErrMindMapNodeLinkExists
+		}
+		if err := tx.Create(linkPO).Error; err != nil {
+			return fmt.Errorf("create mindmap node link failed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 回填创建时间，便于上层直接返回
+	if linkPO.CreatedAt != nil {
+		link.CreatedAt = *linkPO.CreatedAt
+	}
+	return nil
+}
+
+// GetMindMapNodeLink 获取节点链接（不校验权限）
+func (m *mindMapPersistence) GetMindMapNodeLink(ctx context.Context, linkID string) (*entity.MindMapNodeLink, error) {
+	if linkID == "" {
+		return nil, fmt.Errorf("LinkID is required")
+	}
+
+	var linkPO po.MindMapNodeLinkPO
+	if err := m.db.WithContext(ctx).Where("link_id = ?", linkID).First(&linkPO).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repo.ErrMindMapNodeLinkNotFound
+		}
+		return nil, fmt.Errorf("get mindmap node link failed: %w", err)
+	}
+	return CastMindMapNodeLinkPO2DO(&linkPO), nil
+}
+
+// DeleteMindMapNodeLink 删除节点链接（权限由服务层校验）
+func (m *mindMapPersistence) DeleteMindMapNodeLink(ctx context.Context, linkID string) error {
+	if linkID == "" {
+		return fmt.Errorf("LinkID is required")
+	}
+
+	result := m.db.WithContext(ctx).Where("link_id = ?", linkID).Delete(&po.MindMapNodeLinkPO{})
+	if result.Error != nil {
+		return fmt.Errorf("delete mindmap node link failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repo.ErrMindMapNodeLinkNotFound
+	}
+	return nil
+}
+
+// ListMindMapNodeLinks 查询导图（或其中某个节点）发出的链接（按创建时间正序），目标对用户不可见时不填充标题与文本
+func (m *mindMapPersistence) ListMindMapNodeLinks(ctx context.Context, query repo.MindMapNodeLinkQuery) ([]*entity.MindMapNodeLink, error) {
+	if query.UserID == "" || query.MapID == "" {
+		return nil, fmt.Errorf("UserID and MapID are required")
+	}
+
+	db := m.db.WithContext(ctx).Where("source_map_id = ?", query.MapID)
+	if query.NodeUID != "" {
+		db = db.Where("source_node_uid = ?", query.NodeUID)
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+	var linkPOs []po.MindMapNodeLinkPO
+	if err := db.Order("id ASC").Find(&linkPOs).Error; err != nil {
+		return nil, fmt.Errorf("list mindmap node links failed: %w", err)
+	}
+
+	links := make([]*entity.MindMapNodeLink, 0, len(linkPOs))
+	for i := range linkPOs {
+		links = append(links, CastMindMapNodeLinkPO2DO(&linkPOs[i]))
+	}
+	if len(links) == 0 {
+		return links, nil
+	}
+
+	targets := make([]mindMapNodeKey, 0, len(links))
+	for _, link := range links {
+		targets = append(targets, mindMapNodeKey{MapID: link.TargetMapID, NodeUID: link.TargetNodeUID})
+	}
+	titles, texts, err := m.getAccessibleMindMapNodeTexts(ctx, query.UserID, targets)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		link.TargetMapTitle = titles[link.TargetMapID]
+		link.TargetNodeText = texts[mindMapNodeKey{MapID: link.TargetMapID, NodeUID: link.TargetNodeUID}]
+	}
+	return links, nil
+}
+
+// ListMindMapBacklinks 查询指向导图（或其中某个节点）的链接（按创建时间倒序），只返回来源导图对用户可见的链接
+func (m *mindMapPersistence) ListMindMapBacklinks(ctx context.Context, query repo.MindMapNodeLinkQuery) ([]*entity.MindMapNodeLink, error) {
+	if query.UserID == "" || query.MapID == "" {
+		return nil, fmt.Errorf("UserID and MapID are required")
+	}
+
+	db := m.db.WithContext(ctx)
+	sharedMapIDs := db.Model(&po.MindMapCollaboratorPO{}).Select("map_id").Where("user_id = ?", query.UserID)
+	accessibleMapIDs := db.Model(&po.MindMapPO{}).Select("map_id").
+		Where("is_deleted = 0 AND (user_id = ? OR map_id IN (?))", query.UserID, sharedMapIDs)
+
+	linkQuery := db.Where("target_map_id = ? AND source_map_id IN (?)", query.MapID, accessibleMapIDs)
+	if query.NodeUID != "" {
+		linkQuery = linkQuery.Where("target_node_uid = ?", query.NodeUID)
+	}
+	if query.Limit > 0 {
+		linkQuery = linkQuery.Limit(query.Limit)
+	}
+	var linkPOs []po.MindMapNodeLinkPO
+	if err := linkQuery.Order("id DESC").Find(&linkPOs).Error; err != nil {
+		return nil, fmt.Errorf("list mindmap backlinks failed: %w", err)
+	}
+
+	links := make([]*entity.MindMapNodeLink, 0, len(linkPOs))
+	for i := range linkPOs {
+		links = append(links, CastMindMapNodeLinkPO2DO(&linkPOs[i]))
+	}
+	if len(links) == 0 {
+		return links, nil
+	}
+
+	sources := make([]mindMapNodeKey, 0, len(links))
+	for _, link := range links {
+		sources = append(sources, mindMapNodeKey{MapID: link.SourceMapID, NodeUID: link.SourceNodeUID})
+	}
+	titles, texts, err := m.getAccessibleMindMapNodeTexts(ctx, query.UserID, sources)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		link.SourceMapTitle = titles[link.SourceMapID]
+		link.SourceNodeText = texts[mindMapNodeKey{MapID: link.SourceMapID, NodeUID: link.SourceNodeUID}]
+	}
+	return links, nil
+}
+
+// mindMapNodeKey 导图内节点的定位键，NodeUID 为空表示整个导图
+type mindMapNodeKey struct {
+	MapID   string
+	NodeUID string
+}
+
+// getAccessibleMindMapNodeTexts 查询用户可访问且未删除的导图标题，以及其中节点的文本（取自检索文档）
+func (m *mindMapPersistence) getAccessibleMindMapNodeTexts(ctx context.Context, userID string, keys []mindMapNodeKey) (map[string]string, map[mindMapNodeKey]string, error) {
+	titles := make(map[string]string)
+	texts := make(map[mindMapNodeKey]string)
+
+	mapIDSet := make(map[string]struct{}, len(keys))
+	mapIDs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := mapIDSet[key.MapID]; !ok {
+			mapIDSet[key.MapID] = struct{}{}
+			mapIDs = append(mapIDs, key.MapID)
+		}
+	}
+
+	db := m.db.WithContext(ctx)
+	sharedMapIDs := db.Model(&po.MindMapCollaboratorPO{}).Select("map_id").Where("user_id = ?", userID)
+	var mindmapPOs []po.MindMapPO
+	if err := db.Select("map_id", "title").
+		Where("map_id IN ? AND is_deleted = 0 AND (user_id = ? OR map_id IN (?))", mapIDs, userID, sharedMapIDs).
+		Find(&mindmapPOs).Error; err != nil {
+		return nil, nil, fmt.Errorf("get linked mindmaps failed: %w", err)
+	}
+	for _, mindmapPO := range mindmapPOs {
+		titles[mindmapPO.MapID] = mindmapPO.Title
+	}
+
+	nodeKeys := make([][]interface{}, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := titles[key.MapID]; ok && key.NodeUID != "" {
+			nodeKeys = append(nodeKeys, []interface{}{key.MapID, key.NodeUID})
+		}
+	}
+	if len(nodeKeys) == 0 {
+		return titles, texts, nil
+	}
+	var docPOs []po.MindMapSearchDocPO
+	if err := db.Select("map_id", "node_uid", "text").
+		Where("(map_id, node_uid) IN ?", nodeKeys).
+		Find(&docPOs).Error; err != nil {
+		return nil, nil, fmt.Errorf("get linked node texts failed: %w", err)
+	}
+	for _, docPO := range docPOs {
+		texts[mindMapNodeKey{MapID: docPO.MapID, NodeUID: docPO.NodeUID}] = docPO.Text
+	}
+	return titles, texts, nil
+}
+
+// markMindMapNodeLinksBroken 将指向已删除导图的链接标记为失效，需在删除导图的同一事务内调用
+func markMindMapNodeLinksBroken(tx *gorm.DB, targetMapIDs []string) error {
+	if err := tx.Model(&po.MindMapNodeLinkPO{}).
+		Where("target_map_id IN ? AND broken_reason = ''", targetMapIDs).
+		Updates(map[string]interface{}{"broken_reason": entity.NodeLinkBrokenMapDeleted, "broken_at": time.Now()}).Error; err != nil {
+		return fmt.Errorf("mark mindmap node links broken failed: %w", err)
+	}
+	return nil
+}
+
+// restoreMindMapNodeLinks 导图从回收站恢复后，将因导图删除而失效的链接重新校验（目标节点可能已不存在）
+func restoreMindMapNodeLinks(tx *gorm.DB, mindmapPOs []po.MindMapPO) error {
+	mapIDs := make([]string, 0, len(mindmapPOs))
+	for _, mindmapPO := range mindmapPOs {
+		mapIDs = append(mapIDs, mindmapPO.MapID)
+	}
+	if err := tx.Model(&po.MindMapNodeLinkPO{}).
+		Where("target_map_id IN ? AND broken_reason = ?", mapIDs, entity.NodeLinkBrokenMapDeleted).
+		Updates(map[string]interface{}{"broken_reason": "", "broken_at": nil}).Error; err != nil {
+		return fmt.Errorf("restore mindmap node links failed: %w", err)
+	}
+	for i := range mindmapPOs {
+		if err := syncMindMapNodeLinks(tx, &mindmapPOs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncMindMapNodeLinks 导图节点变化后同步链接状态，需在写入导图的同一事务内调用：
+// 源节点已删除的出链随节点一并删除；目标节点已删除的入链标记为失效，节点重新出现（如回滚版本）时恢复
+func syncMindMapNodeLinks(tx *gorm.DB, mindmapPO *po.MindMapPO) error {
+	var data entity.MindMapData
+	if err := json.Unmarshal([]byte(mindmapPO.Data), &data); err != nil {
+		return fmt.Errorf("unmarshal mindmap data for node links failed: %w", err)
+	}
+	nodeUIDs := make(map[string]struct{})
+	data.Walk(func(node, _ *entity.MindMapData, _ int) bool {
+		if node.Data.UID != "" {
+			nodeUIDs[node.Data.UID] = struct{}{}
+		}
+		return true
+	})
+
+	var linkPOs []po.MindMapNodeLinkPO
+	if err := tx.Where("source_map_id = ? OR (target_map_id = ? AND target_node_uid <> '')", mindmapPO.MapID, mindmapPO.MapID).
+		Find(&linkPOs).Error; err != nil {
+		return fmt.Errorf("query mindmap node links failed: %w", err)
+	}
+
+	var orphanedLinkIDs, brokenLinkIDs, repairedLinkIDs []string
+	for _, linkPO := range linkPOs {
+		if linkPO.SourceMapID == mindmapPO.MapID {
+			if _, ok := nodeUIDs[linkPO.SourceNodeUID]; !ok {
+				orphanedLinkIDs = append(orphanedLinkIDs, linkPO.LinkID)
+				continue
+			}
+		}
+		if linkPO.TargetMapID != mindmapPO.MapID || linkPO.TargetNodeUID == "" {
+			continue
+		}
+		_, exists := nodeUIDs[linkPO.TargetNodeUID]
+		switch {
+		case !exists && linkPO.BrokenReason == "":
+			brokenLinkIDs = append(brokenLinkIDs, linkPO.LinkID)
+		case exists && linkPO.BrokenReason == entity.NodeLinkBrokenNodeDeleted:
+			repairedLinkIDs = append(repairedLinkIDs, linkPO.LinkID)
+		}
+	}
+
+	if len(orphanedLinkIDs) > 0 {
+		if err := tx.Where("link_id IN ?", orphanedLinkIDs).Delete(&po.MindMapNodeLinkPO{}).Error; err != nil {
+			return fmt.Errorf("delete orphaned mindmap node links failed: %w", err)
+		}
+	}
+	if len(brokenLinkIDs) > 0 {
+		if err := tx.Model(&po.MindMapNodeLinkPO{}).
+			Where("link_id IN ?", brokenLinkIDs).
+			Updates(map[string]interface{}{"broken_reason": entity.NodeLinkBrokenNodeDeleted, "broken_at": time.Now()}).Error; err != nil {
+			return fmt.Errorf("mark mindmap node links broken failed: %w", err)
+		}
+	}
+	if len(repairedLinkIDs) > 0 {
+		if err := tx.Model(&po.MindMapNodeLinkPO{}).
+			Where("link_id IN ?", repairedLinkIDs).
+			Updates(map[string]interface{}{"broken_reason": "", "broken_at": nil}).Error; err != nil {
+			return fmt.Errorf("repair mindmap node links failed: %w", err)
+		}
+	}
+	return nil
+}
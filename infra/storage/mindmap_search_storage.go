@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/infra/storage/po"
+	"forge/pkg/log/zlog"
+	"forge/pkg/tokenizer"
+
+	"gorm.io/gorm"
+)
+
+const (
+	searchIndexBatchSize    = 500 // 批量写入索引的每批行数
+	searchBackfillBatchSize = 100 // 补建索引时每批处理的导图数量
+)
+
+// SearchMindMapNodes 在用户可访问（自己创建或参与协作）的导图中检索同时包含全部词元的文档，按命中词频降序返回
+func (m *mindMapPersistence) SearchMindMapNodes(ctx context.Context, query repo.MindMapSearchQuery) (*repo.MindMapSearchCandidates, error) {
+	if query.UserID == "" || len(query.Tokens) == 0 {
+		return nil, fmt.Errorf("UserID and Tokens are required")
+	}
+
+	db := m.db.WithContext(ctx)
+	sharedMapIDs := db.Model(&po.MindMapCollaboratorPO{}).Select("map_id").Where("user_id = ?", query.UserID)
+	accessibleMapIDs := db.Model(&po.MindMapPO{}).Select("map_id").
+		Where("is_deleted = 0 AND (user_id = ? OR map_id IN (?))", query.UserID, sharedMapIDs)
+
+	// 倒排索引求交：文档需包含全部查询词元
+	var hits []struct {
+		MapID   string
+		NodeUID string
+	}
+	hitQuery := db.Model(&po.MindMapSearchTokenPO{}).
+		Select("map_id, node_uid").
+		Where("token IN ? AND map_id IN (?)", query.Tokens, accessibleMapIDs).
+		Group("map_id, node_uid").
+		Having("COUNT(DISTINCT token) = ?", len(query.Tokens)).
+		Order("SUM(freq) DESC")
+	if query.Limit > 0 {
+		hitQuery = hitQuery.Limit(query.Limit)
+	}
+	if err := hitQuery.Scan(&hits).Error; err != nil {
+		return nil, fmt.Errorf("search mindmap tokens failed: %w", err)
+	}
+
+	candidates := &repo.MindMapSearchCandidates{
+		Docs:    make([]*entity.MindMapNodeDoc, 0, len(hits)),
+		DocFreq: make(map[string]int64, len(query.Tokens)),
+	}
+	if len(hits) == 0 {
+		return candidates, nil
+	}
+
+	keys := make([][]interface{}, 0, len(hits))
+	for _, hit := range hits {
+		keys = append(keys, []interface{}{hit.MapID, hit.NodeUID})
+	}
+	var docPOs []po.MindMapSearchDocPO
+	if err := db.Where("(map_id, node_uid) IN ?", keys).Find(&docPOs).Error; err != nil {
+		return nil, fmt.Errorf("get mindmap search docs failed: %w", err)
+	}
+	for i := range docPOs {
+		doc, err := CastMindMapSearchDocPO2DO(&docPOs[i])
+		if err != nil {
+			zlog.CtxErrorf(ctx, "failed to cast search doc PO to DO for mapID %s node %s: %v", docPOs[i].MapID, docPOs[i].NodeUID, err)
+			continue
+		}
+		candidates.Docs = append(candidates.Docs, doc)
+	}
+
+	// 打分所需的统计信息
+	var freqs []struct {
+		Token string
+		Count int64
+	}
+	if err := db.Model(&po.MindMapSearchTokenPO{}).
+		Select("token, COUNT(*) AS count").
+		Where("token IN ?", query.Tokens).
+		Group("token").
+		Scan(&freqs).Error; err != nil {
+		return nil, fmt.Errorf("count mindmap token frequency failed: %w", err)
+	}
+	for _, freq := range freqs {
+		candidates.DocFreq[freq.Token] = freq.Count
+	}
+	if err := db.Model(&po.MindMapSearchDocPO{}).Count(&candidates.TotalDocs).Error; err != nil {
+		return nil, fmt.Errorf("count mindmap search docs failed: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// BatchGetMindMapSummaries 批量查询未删除导图的基本信息（不含Data，不校验权限）
+func (m *mindMapPersistence) BatchGetMindMapSummaries(ctx context.Context, mapIDs []string) ([]*entity.MindMap, error) {
+	if len(mapIDs) == 0 {
+		return nil, fmt.Errorf("MapIDs are required")
+	}
+
+	var mindmapPOs []po.MindMapPO
+	err := m.db.WithContext(ctx).
+		Omit("data").
+		Where("map_id IN ? AND is_deleted = 0", mapIDs).
+		Find(&mindmapPOs).Error
+	if err != nil {
+		return nil, fmt.Errorf("batch get mindmap summaries failed: %w", err)
+	}
+
+	mindmaps := make([]*entity.MindMap, 0, len(mindmapPOs))
+	for _, po := range mindmapPOs {
+		mindmaps = append(mindmaps, &entity.MindMap{
+			MapID:     po.MapID,
+			UserID:    po.UserID,
+			Title:     po.Title,
+			Desc:      po.Desc,
+			Layout:    po.Layout,
+			Version:   po.Version,
+			CreatedAt: derefTime(po.CreatedAt),
+			UpdatedAt: derefTime(po.UpdatedAt),
+		})
+	}
+
+	return mindmaps, nil
+}
+
+// rebuildMindMapSearchIndex 按导图当前存储状态重建其检索索引，需在写入导图的同一事务内调用
+func rebuildMindMapSearchIndex(tx *gorm.DB, mindmapPO *po.MindMapPO) error {
+	if err := deleteMindMapSearchIndex(tx, []string{mindmapPO.MapID}); err != nil {
+		return err
+	}
+
+	mindmap, err := CastMindMapPO2DO(mindmapPO)
+	if err != nil {
+		return fmt.Errorf("cast mindmap for search index failed: %w", err)
+	}
+
+	docPOs := make([]*po.MindMapSearchDocPO, 0)
+	tokenPOs := make([]*po.MindMapSearchTokenPO, 0)
+	for _, doc := range mindmap.BuildSearchDocs() {
+		docPO, err := CastMindMapSearchDocDO2PO(doc)
+		if err != nil {
+			return fmt.Errorf("cast search doc failed: %w", err)
+		}
+		docPOs = append(docPOs, docPO)
+
+		freqs := make(map[string]int)
+		for _, token := range tokenizer.Tokenize(doc.Text + " " + doc.Note) {
+			freqs[token]++
+		}
+		for token, freq := range freqs {
+			tokenPOs = append(tokenPOs, &po.MindMapSearchTokenPO{
+				Token:   token,
+				MapID:   doc.MapID,
+				NodeUID: doc.NodeUID,
+				Freq:    freq,
+			})
+		}
+	}
+
+	if err := tx.CreateInBatches(docPOs, searchIndexBatchSize).Error; err != nil {
+		return fmt.Errorf("create mindmap search docs failed: %w", err)
+	}
+	if len(tokenPOs) > 0 {
+		if err := tx.CreateInBatches(tokenPOs, searchIndexBatchSize).Error; err != nil {
+			return fmt.Errorf("create mindmap search tokens failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// deleteMindMapSearchIndex 删除导图的检索索引
+func deleteMindMapSearchIndex(tx *gorm.DB, mapIDs []string) error {
+	if err := tx.Where("map_id IN ?", mapIDs).Delete(&po.MindMapSearchTokenPO{}).Error; err != nil {
+		return fmt.Errorf("delete mindmap search tokens failed: %w", err)
+	}
+	if err := tx.Where("map_id IN ?", mapIDs).Delete(&po.MindMapSearchDocPO{}).Error; err != nil {
+		return fmt.Errorf("delete mindmap search docs failed: %w", err)
+	}
+	return nil
+}
+
+// backfillSearchIndex 为上线检索功能前创建、尚未建立索引的导图补建索引
+func (m *mindMapPersistence) backfillSearchIndex() {
+	ctx := context.Background()
+	indexedMapIDs := m.db.Model(&po.MindMapSearchDocPO{}).Select("map_id")
+
+	var lastID uint64
+	total := 0
+	for {
+		var mindmapPOs []po.MindMapPO
+		if err := m.db.WithContext(ctx).
+			Where("id > ? AND is_deleted = 0 AND map_id NOT IN (?)", lastID, indexedMapIDs).
+			Order("id ASC").
+			Limit(searchBackfillBatchSize).
+			Find(&mindmapPOs).Error; err != nil {
+			zlog.Errorf("检索索引补建失败: %v", err)
+			return
+		}
+		if len(mindmapPOs) == 0 {
+			break
+		}
+
+		for i := range mindmapPOs {
+			lastID = mindmapPOs[i].ID
+			err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				return rebuildMindMapSearchIndex(tx, &mindmapPOs[i])
+			})
+			if err != nil {
+				zlog.Errorf("导图 %s 检索索引补建失败: %v", mindmapPOs[i].MapID, err)
+				continue
+			}
+			total++
+		}
+	}
+
+	if total > 0 {
+		zlog.Infof("检索索引补建完成，共 %d 个导图", total)
+	}
+}
+
+func derefTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
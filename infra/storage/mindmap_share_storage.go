@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/infra/storage/po"
+
+	"gorm.io/gorm"
+)
+
+// CreateMindMapShare 创建分享链接
+func (m *mindMapPersistence) CreateMindMapShare(ctx context.Context, share *entity.MindMapShare) error {
+	sharePO := CastMindMapShareDO2PO(share)
+	if err := m.db.WithContext(ctx).Create(sharePO).Error; err != nil {
+		return fmt.Errorf("create mindmap share failed: %w", err)
+	}
+	if sharePO.CreatedAt != nil {
+		share.CreatedAt = *sharePO.CreatedAt
+	}
+	return nil
+}
+
+// ListMindMapShares 查询导图的全部分享链接（按创建时间倒序，包含已撤销的）
+func (m *mindMapPersistence) ListMindMapShares(ctx context.Context, mapID string, userID string) ([]*entity.MindMapShare, error) {
+	if mapID == "" || userID == "" {
+		return nil, fmt.Errorf("MapID and UserID are required")
+	}
+
+	var sharePOs []po.MindMapSharePO
+	if err := m.db.WithContext(ctx).
+		Where("map_id = ? AND user_id = ?", mapID, userID).
+		Order("created_at DESC").
+		Find(&sharePOs).Error; err != nil {
+		return nil, fmt.Errorf("list mindmap shares failed: %w", err)
+	}
+
+	shares := make([]*entity.MindMapShare, 0, len(sharePOs))
+	for i := range sharePOs {
+		shares = append(shares, CastMindMapSharePO2DO(&sharePOs[i]))
+	}
+	return shares, nil
+}
+
+// GetMindMapShareByToken 根据分享令牌查询分享链接
+func (m *mindMapPersistence) GetMindMapShareByToken(ctx context.Context, token string) (*entity.MindMapShare, error) {
+	if token == "" {
+		return nil, fmt.Errorf("Token is required")
+	}
+
+	var sharePO po.MindMapSharePO
+	if err := m.db.WithContext(ctx).Where("token = ?", token).First(&sharePO).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repo.ErrMindMapShareNotFound
+		}
+		return nil, fmt.Errorf("get mindmap share failed: %w", err)
+	}
+	return CastMindMapSharePO2DO(&sharePO), nil
+}
+
+// RevokeMindMapShare 撤销分享链接（保留记录用于统计）
+func (m *mindMapPersistence) RevokeMindMapShare(ctx context.Context, mapID, shareID, userID string) error {
+	if mapID == "" || shareID == "" || userID == "" {
+		return fmt.Errorf("MapID, ShareID and UserID are required")
+	}
+
+	result := m.db.WithContext(ctx).
+		Model(&po.MindMapSharePO{}).
+		Where("share_id = ? AND map_id = ? AND user_id = ? AND revoked_at IS NULL", shareID, mapID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("revoke mindmap share failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repo.ErrMindMapShareNotFound
+	}
+	return nil
+}
+
+// IncrMindMapShareHit 分享链接访问计数加一
+func (m *mindMapPersistence) IncrMindMapShareHit(ctx context.Context, shareID string) error {
+	result := m.db.WithContext(ctx).
+		Model(&po.MindMapSharePO{}).
+		Where("share_id = ?", shareID).
+		Updates(map[string]interface{}{
+			"hit_count":   gorm.Expr("hit_count + 1"),
+			"last_hit_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("incr mindmap share hit failed: %w", result.Error)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"forge/biz/entity"
 	"forge/biz/repo"
@@ -12,6 +13,7 @@ import (
 	"forge/pkg/log/zlog"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type mindMapPersistence struct {
@@ -23,31 +25,122 @@ var mmp *mindMapPersistence
 func InitMindMapStorage() {
 	db := database.ForgeDB()
 
-	// 自动迁移思维导图表
-	if err := db.AutoMigrate(&po.MindMapPO{}); err != nil {
+	// 自动迁移思维导图表、历史版本表、分享链接表、协作者表、全文检索索引表及文件夹/标签表
+	if err := db.AutoMigrate(&po.MindMapPO{}, &po.MindMapRevisionPO{}, &po.MindMapSharePO{}, &po.MindMapCollaboratorPO{},
+		&po.MindMapSearchDocPO{}, &po.MindMapSearchTokenPO{},
+		&po.MindMapFolderPO{}, &po.MindMapTagPO{}, &po.MindMapTagRelPO{},
+		&po.MindMapTemplatePO{}, &po.MindMapNodeLinkPO{}); err != nil {
 		panic(fmt.Sprintf("failed to auto migrate mindmap table: %v", err))
 	}
 
 	mmp = &mindMapPersistence{
 		db: db,
 	}
+
+	// 后台为历史导图补齐节点UID并补建检索索引（索引以节点UID区分文档，需在UID补齐之后进行）
+	go func() {
+		mmp.backfillNodeUIDs()
+		mmp.backfillSearchIndex()
+	}()
+}
+
+// backfillNodeUIDs 为上线节点UID前保存、数据中没有UID的导图补齐UID。
+// 属于数据迁移：不产生历史版本、不改变版本号与更新时间，仅在版本号未变化时写入，避免覆盖并发的修改
+func (m *mindMapPersistence) backfillNodeUIDs() {
+	ctx := context.Background()
+
+	var lastID uint64
+	total := 0
+	for {
+		var mindmapPOs []po.MindMapPO
+		if err := m.db.WithContext(ctx).
+			Where("id > ? AND is_deleted = 0 AND data NOT LIKE ?", lastID, `%"UID":%`).
+			Order("id ASC").
+			Limit(searchBackfillBatchSize).
+			Find(&mindmapPOs).Error; err != nil {
+			zlog.Errorf("节点UID补齐失败: %v", err)
+			return
+		}
+		if len(mindmapPOs) == 0 {
+			break
+		}
+
+		for i := range mindmapPOs {
+			mindmapPO := &mindmapPOs[i]
+			lastID = mindmapPO.ID
+
+			var data entity.MindMapData
+			if err := json.Unmarshal([]byte(mindmapPO.Data), &data); err != nil {
+				zlog.Errorf("导图 %s 节点UID补齐失败: %v", mindmapPO.MapID, err)
+				continue
+			}
+			changed, err := data.EnsureUIDs()
+			if err != nil {
+				zlog.Errorf("导图 %s 节点UID补齐失败: %v", mindmapPO.MapID, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			dataBytes, err := json.Marshal(data)
+			if err != nil {
+				zlog.Errorf("导图 %s 节点UID补齐失败: %v", mindmapPO.MapID, err)
+				continue
+			}
+
+			err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				result := tx.Model(&po.MindMapPO{}).
+					Where("map_id = ? AND version = ?", mindmapPO.MapID, mindmapPO.Version).
+					UpdateColumn("data", string(dataBytes))
+				if result.Error != nil {
+					return result.Error
+				}
+				// 期间导图已被修改，修改时已补齐UID
+				if result.RowsAffected == 0 {
+					return nil
+				}
+				mindmapPO.Data = string(dataBytes)
+				return rebuildMindMapSearchIndex(tx, mindmapPO)
+			})
+			if err != nil {
+				zlog.Errorf("导图 %s 节点UID补齐失败: %v", mindmapPO.MapID, err)
+				continue
+			}
+			total++
+		}
+	}
+
+	if total > 0 {
+		zlog.Infof("节点UID补齐完成，共 %d 个导图", total)
+	}
 }
 
 func GetMindMapPersistence() repo.IMindMapRepo {
 	return mmp
 }
 
-// CreateMindMap 创建思维导图
+// CreateMindMap 创建思维导图（同时写入第一个历史版本并建立检索索引）
 func (m *mindMapPersistence) CreateMindMap(ctx context.Context, mindmap *entity.MindMap) error {
 	mindmapPO, err := CastMindMapDO2PO(mindmap)
 	if err != nil {
 		return fmt.Errorf("convert mindmap to PO failed: %w", err)
 	}
-	if err := m.db.WithContext(ctx).Create(mindmapPO).Error; err != nil {
-		return fmt.Errorf("create mindmap failed: %w", err)
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(mindmapPO).Error; err != nil {
+			return fmt.Errorf("create mindmap failed: %w", err)
+		}
+		revisionPO := BuildMindMapRevisionPO(mindmapPO, mindmapPO.UserID, entity.RevisionSourceCreate)
+		if err := tx.Create(revisionPO).Error; err != nil {
+			return fmt.Errorf("create mindmap revision failed: %w", err)
+		}
+		return rebuildMindMapSearchIndex(tx, mindmapPO)
+	})
+	if err != nil {
+		return err
 	}
 
-	// 回填创建/更新时间，便于上层直接返回
+	// 回填版本号和创建/更新时间，便于上层直接返回
+	mindmap.Version = mindmapPO.Version
 	if mindmapPO.CreatedAt != nil {
 		mindmap.CreatedAt = *mindmapPO.CreatedAt
 	}
@@ -64,11 +157,10 @@ func (m *mindMapPersistence) GetMindMap(ctx context.Context, query repo.MindMapQ
 
 	db := m.db.WithContext(ctx).Where("is_deleted = 0")
 
-	// 必须有UserID
-	if query.UserID == "" {
-		return nil, fmt.Errorf("UserID is required")
+	// UserID不为空时校验归属；为空时由服务层完成权限校验
+	if query.UserID != "" {
+		db = db.Where("user_id = ?", query.UserID)
 	}
-	db = db.Where("user_id = ?", query.UserID)
 
 	// 必须有MapID（GetMindMap用于获取单个实体）
 	if query.MapID == "" {
@@ -97,7 +189,15 @@ func (m *mindMapPersistence) ListMindMaps(ctx context.Context, query repo.MindMa
 	if query.UserID == "" {
 		return nil, 0, fmt.Errorf("UserID is required")
 	}
-	db = db.Where("user_id = ?", query.UserID)
+	sharedMapIDs := m.db.Model(&po.MindMapCollaboratorPO{}).Select("map_id").Where("user_id = ?", query.UserID)
+	switch query.Scope {
+	case repo.MindMapScopeShared:
+		db = db.Where("map_id IN (?)", sharedMapIDs)
+	case repo.MindMapScopeAll:
+		db = db.Where("(user_id = ? OR map_id IN (?))", query.UserID, sharedMapIDs)
+	default:
+		db = db.Where("user_id = ?", query.UserID)
+	}
 
 	// 可选筛选条件
 	if query.Title != "" {
@@ -106,6 +206,21 @@ func (m *mindMapPersistence) ListMindMaps(ctx context.Context, query repo.MindMa
 	if query.Layout != "" {
 		db = db.Where("layout = ?", query.Layout)
 	}
+	switch query.FolderID {
+	case "":
+	case repo.MindMapFolderUnfiled:
+		db = db.Where("folder_id = ''")
+	default:
+		db = db.Where("folder_id = ?", query.FolderID)
+	}
+	if len(query.TagIDs) > 0 {
+		// 需同时包含全部标签
+		taggedMapIDs := m.db.Model(&po.MindMapTagRelPO{}).Select("map_id").
+			Where("tag_id IN ?", query.TagIDs).
+			Group("map_id").
+			Having("COUNT(DISTINCT tag_id) = ?", len(query.TagIDs))
+		db = db.Where("map_id IN (?)", taggedMapIDs)
+	}
 
 	// 统计总数（先统计，再应用排序和分页）
 	if err := db.Model(&po.MindMapPO{}).Count(&total).Error; err != nil {
@@ -136,13 +251,26 @@ func (m *mindMapPersistence) ListMindMaps(ctx context.Context, query repo.MindMa
 		mindmaps = append(mindmaps, mindmap)
 	}
 
+	// 填充标签
+	mapIDs := make([]string, 0, len(mindmaps))
+	for _, mindmap := range mindmaps {
+		mapIDs = append(mapIDs, mindmap.MapID)
+	}
+	tagIDs, err := m.listMindMapTagIDs(ctx, mapIDs, query.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, mindmap := range mindmaps {
+		mindmap.TagIDs = tagIDs[mindmap.MapID]
+	}
+
 	return mindmaps, total, nil
 }
 
-// UpdateMindMap 更新思维导图
-func (m *mindMapPersistence) UpdateMindMap(ctx context.Context, updateInfo *repo.MindMapUpdateInfo) error {
+// UpdateMindMap 更新思维导图（乐观锁校验 + 写入历史版本 + 更新检索索引，权限由服务层校验）
+func (m *mindMapPersistence) UpdateMindMap(ctx context.Context, updateInfo *repo.MindMapUpdateInfo) (newVersion int64, err error) {
 	if updateInfo.MapID == "" || updateInfo.UserID == "" {
-		return fmt.Errorf("MapID and UserID are required")
+		return 0, fmt.Errorf("MapID and UserID are required")
 	}
 
 	updates := make(map[string]interface{})
@@ -159,69 +287,200 @@ func (m *mindMapPersistence) UpdateMindMap(ctx context.Context, updateInfo *repo
 	if updateInfo.Data != nil {
 		dataBytes, err := json.Marshal(updateInfo.Data)
 		if err != nil {
-			return fmt.Errorf("marshal data failed: %w", err)
+			return 0, fmt.Errorf("marshal data failed: %w", err)
 		}
 		updates["data"] = string(dataBytes)
 	}
 
 	if len(updates) == 0 {
-		return nil // 没有需要更新的字段
+		return 0, nil // 没有需要更新的字段
+	}
+	updates["version"] = gorm.Expr("version + 1")
+
+	source := updateInfo.Source
+	if source == "" {
+		source = entity.RevisionSourceUpdate
+	}
+
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureCurrentMindMapRevision(tx, updateInfo.MapID); err != nil {
+			return err
+		}
+
+		db := tx.Model(&po.MindMapPO{}).
+			Where("map_id = ? AND is_deleted = 0", updateInfo.MapID)
+		if updateInfo.ExpectedVersion != nil {
+			db = db.Where("version = ?", *updateInfo.ExpectedVersion)
+		}
+
+		result := db.Updates(updates)
+		if result.Error != nil {
+			return fmt.Errorf("update mindmap failed: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			if updateInfo.ExpectedVersion == nil {
+				return repo.ErrMindMapNotFound
+			}
+			// 区分导图不存在与版本冲突
+			var count int64
+			if err := tx.Model(&po.MindMapPO{}).
+				Where("map_id = ? AND is_deleted = 0", updateInfo.MapID).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("check mindmap failed: %w", err)
+			}
+			if count == 0 {
+				return repo.ErrMindMapNotFound
+			}
+			return repo.ErrMindMapVersionConflict
+		}
+
+		// 读取更新后的完整状态，写入历史版本
+		var mindmapPO po.MindMapPO
+		if err := tx.Where("map_id = ?", updateInfo.MapID).First(&mindmapPO).Error; err != nil {
+			return fmt.Errorf("reload mindmap failed: %w", err)
+		}
+		revisionPO := BuildMindMapRevisionPO(&mindmapPO, updateInfo.UserID, source)
+		if err := tx.Create(revisionPO).Error; err != nil {
+			return fmt.Errorf("create mindmap revision failed: %w", err)
+		}
+
+		// 标题或节点变化时重建检索索引
+		if updateInfo.Title != nil || updateInfo.Data != nil {
+			if err := rebuildMindMapSearchIndex(tx, &mindmapPO); err != nil {
+				return err
+			}
+		}
+		// 节点变化时同步跨导图链接状态
+		if updateInfo.Data != nil {
+			if err := syncMindMapNodeLinks(tx, &mindmapPO); err != nil {
+				return err
+			}
+		}
+
+		newVersion = mindmapPO.Version
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	result := m.db.WithContext(ctx).
-		Model(&po.MindMapPO{}).
-		Where("map_id = ? AND user_id = ? AND is_deleted = 0", updateInfo.MapID, updateInfo.UserID).
-		Updates(updates)
+	return newVersion, nil
+}
 
-	if result.Error != nil {
-		return fmt.Errorf("update mindmap failed: %w", result.Error)
+// ensureCurrentMindMapRevision 导图当前版本缺少历史版本时（历史版本功能上线前创建的导图）先补写快照，避免更新后丢失修改前的内容
+func ensureCurrentMindMapRevision(tx *gorm.DB, mapID string) error {
+	var current po.MindMapPO
+	err := tx.Where("map_id = ? AND is_deleted = 0", mapID).First(&current).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil // 导图不存在由后续更新区分处理
+		}
+		return fmt.Errorf("load mindmap failed: %w", err)
 	}
 
-	if result.RowsAffected == 0 {
-		return repo.ErrMindMapNotFound
+	var count int64
+	if err := tx.Model(&po.MindMapRevisionPO{}).
+		Where("map_id = ? AND version = ?", mapID, current.Version).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("check mindmap revision failed: %w", err)
+	}
+	if count > 0 {
+		return nil
 	}
 
+	source := entity.RevisionSourceUpdate
+	if current.Version <= 1 {
+		source = entity.RevisionSourceCreate
+	}
+	// 并发补写时以先写入者为准
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(BuildMindMapRevisionPO(&current, current.UserID, source)).Error; err != nil {
+		return fmt.Errorf("create mindmap revision failed: %w", err)
+	}
+	return nil
+}
+
+// UpdateMindMapThumbnail 写入缩略图地址（不触发更新时间与版本号变化，旧版本的缩略图不覆盖新版本）
+func (m *mindMapPersistence) UpdateMindMapThumbnail(ctx context.Context, mapID, thumbnailURL string, version int64) error {
+	if mapID == "" || thumbnailURL == "" {
+		return fmt.Errorf("MapID and thumbnailURL are required")
+	}
+
+	err := m.db.WithContext(ctx).Model(&po.MindMapPO{}).
+		Where("map_id = ? AND thumbnail_version < ?", mapID, version).
+		UpdateColumns(map[string]interface{}{"thumbnail_url": thumbnailURL, "thumbnail_version": version}).Error
+	if err != nil {
+		return fmt.Errorf("update mindmap thumbnail failed: %w", err)
+	}
 	return nil
 }
 
-// DeleteMindMap 删除思维导图（软删除）
+// DeleteMindMap 删除思维导图（软删除，移入回收站）
 func (m *mindMapPersistence) DeleteMindMap(ctx context.Context, mapID string, userID string) error {
 	if mapID == "" || userID == "" {
 		return fmt.Errorf("MapID and UserID are required for deletion")
 	}
 
-	result := m.db.WithContext(ctx).
-		Model(&po.MindMapPO{}).
-		Where("map_id = ? AND user_id = ? AND is_deleted = 0", mapID, userID).
-		Update("is_deleted", 1)
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&po.MindMapPO{}).
+			Where("map_id = ? AND user_id = ? AND is_deleted = 0", mapID, userID).
+			Updates(map[string]interface{}{"is_deleted": 1, "deleted_at": time.Now()})
 
-	if result.Error != nil {
-		return fmt.Errorf("delete mindmap failed: %w", result.Error)
-	}
+		if result.Error != nil {
+			return fmt.Errorf("delete mindmap failed: %w", result.Error)
+		}
 
-	if result.RowsAffected == 0 {
-		return repo.ErrMindMapNotFound
-	}
+		if result.RowsAffected == 0 {
+			return repo.ErrMindMapNotFound
+		}
 
-	return nil
+		// 回收站中的导图不参与检索，恢复时重建索引
+		if err := deleteMindMapSearchIndex(tx, []string{mapID}); err != nil {
+			return err
+		}
+		// 指向该导图的链接标记为失效，恢复时重新校验
+		return markMindMapNodeLinksBroken(tx, []string{mapID})
+	})
 }
 
-// BatchDeleteMindMap 批量删除思维导图（软删除）
+// BatchDeleteMindMap 批量删除思维导图（软删除，移入回收站）
 func (m *mindMapPersistence) BatchDeleteMindMap(ctx context.Context, mapIDs []string, userID string) (deletedCount int, err error) {
 	if len(mapIDs) == 0 || userID == "" {
 		return 0, fmt.Errorf("MapIDs and UserID are required for batch deletion")
 	}
 
-	result := m.db.WithContext(ctx).
-		Model(&po.MindMapPO{}).
-		Where("map_id IN ? AND user_id = ? AND is_deleted = 0", mapIDs, userID).
-		Update("is_deleted", 1)
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deletedMapIDs []string
+		if err := tx.Model(&po.MindMapPO{}).
+			Where("map_id IN ? AND user_id = ? AND is_deleted = 0", mapIDs, userID).
+			Pluck("map_id", &deletedMapIDs).Error; err != nil {
+			return fmt.Errorf("query mindmaps for batch deletion failed: %w", err)
+		}
+		if len(deletedMapIDs) == 0 {
+			return nil
+		}
+
+		result := tx.Model(&po.MindMapPO{}).
+			Where("map_id IN ? AND is_deleted = 0", deletedMapIDs).
+			Updates(map[string]interface{}{"is_deleted": 1, "deleted_at": time.Now()})
+		if result.Error != nil {
+			return fmt.Errorf("batch delete mindmaps failed: %w", result.Error)
+		}
+		deletedCount = int(result.RowsAffected)
 
-	if result.Error != nil {
-		return 0, fmt.Errorf("batch delete mindmaps failed: %w", result.Error)
+		// 回收站中的导图不参与检索，恢复时重建索引
+		if err := deleteMindMapSearchIndex(tx, deletedMapIDs); err != nil {
+			return err
+		}
+		// 指向这些导图的链接标记为失效，恢复时重新校验
+		return markMindMapNodeLinksBroken(tx, deletedMapIDs)
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return int(result.RowsAffected), nil
+	return deletedCount, nil
 }
 
 // BatchGetMindMapsByIDs 批量查询指定mapIDs且属于指定用户的思维导图（用于权限验证）
@@ -252,3 +511,60 @@ func (m *mindMapPersistence) BatchGetMindMapsByIDs(ctx context.Context, mapIDs [
 
 	return mindmaps, nil
 }
+
+// ListMindMapRevisions 分页查询导图历史版本（按版本号倒序，不包含Data）
+func (m *mindMapPersistence) ListMindMapRevisions(ctx context.Context, mapID string, page, pageSize int) ([]*entity.MindMapRevision, int64, error) {
+	if mapID == "" {
+		return nil, 0, fmt.Errorf("MapID is required")
+	}
+
+	var revisionPOs []po.MindMapRevisionPO
+	var total int64
+
+	db := m.db.WithContext(ctx).Model(&po.MindMapRevisionPO{}).Where("map_id = ?", mapID)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count mindmap revisions failed: %w", err)
+	}
+
+	db = db.Omit("data").Order("version DESC")
+	if page > 0 && pageSize > 0 {
+		offset := (page - 1) * pageSize
+		db = db.Offset(offset).Limit(pageSize)
+	}
+
+	if err := db.Find(&revisionPOs).Error; err != nil {
+		return nil, 0, fmt.Errorf("list mindmap revisions failed: %w", err)
+	}
+
+	revisions := make([]*entity.MindMapRevision, 0, len(revisionPOs))
+	for _, po := range revisionPOs {
+		revision, err := CastMindMapRevisionPO2DO(&po)
+		if err != nil {
+			zlog.CtxErrorf(ctx, "failed to cast revision PO to DO for mapID %s version %d: %v", po.MapID, po.Version, err)
+			continue
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, total, nil
+}
+
+// GetMindMapRevision 获取导图指定版本的完整快照
+func (m *mindMapPersistence) GetMindMapRevision(ctx context.Context, mapID string, version int64) (*entity.MindMapRevision, error) {
+	if mapID == "" || version <= 0 {
+		return nil, fmt.Errorf("MapID and Version are required")
+	}
+
+	var revisionPO po.MindMapRevisionPO
+	if err := m.db.WithContext(ctx).
+		Where("map_id = ? AND version = ?", mapID, version).
+		First(&revisionPO).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repo.ErrMindMapRevisionNotFound
+		}
+		return nil, fmt.Errorf("get mindmap revision failed: %w", err)
+	}
+
+	return CastMindMapRevisionPO2DO(&revisionPO)
+}
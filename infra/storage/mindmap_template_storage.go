@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"forge/biz/entity"
+	"forge/biz/repo"
+	"forge/infra/storage/po"
+	"forge/pkg/log/zlog"
+
+	"gorm.io/gorm"
+)
+
+// CreateMindMapTemplate 保存用户模板
+func (m *mindMapPersistence) CreateMindMapTemplate(ctx context.Context, template *entity.MindMapTemplate) error {
+	templatePO, err := CastMindMapTemplateDO2PO(template)
+	if err != nil {
+		return err
+	}
+	if err := m.db.WithContext(ctx).Create(templatePO).Error; err != nil {
+		return fmt.Errorf("create mindmap template failed: %w", err)
+	}
+
+	// 回填创建/更新时间，便于上层直接返回
+	if templatePO.CreatedAt != nil {
+		template.CreatedAt = *templatePO.CreatedAt
+	}
+	if templatePO.UpdatedAt != nil {
+		template.UpdatedAt = *templatePO.UpdatedAt
+	}
+	return nil
+}
+
+// GetMindMapTemplate 获取用户自己的模板（包含Data）
+func (m *mindMapPersistence) GetMindMapTemplate(ctx context.Context, templateID, userID string) (*entity.MindMapTemplate, error) {
+	if templateID == "" || userID == "" {
+		return nil, fmt.Errorf("TemplateID and UserID are required")
+	}
+
+	var templatePO po.MindMapTemplatePO
+	err := m.db.WithContext(ctx).
+		Where("template_id = ? AND user_id = ?", templateID, userID).
+		First(&templatePO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repo.ErrMindMapTemplateNotFound
+		}
+		return nil, fmt.Errorf("get mindmap template failed: %w", err)
+	}
+
+	return CastMindMapTemplatePO2DO(&templatePO)
+}
+
+// ListMindMapTemplates 查询用户的全部模板（按创建时间倒序，不包含Data）
+func (m *mindMapPersistence) ListMindMapTemplates(ctx context.Context, userID string) ([]*entity.MindMapTemplate, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("UserID is required")
+	}
+
+	var templatePOs []po.MindMapTemplatePO
+	if err := m.db.WithContext(ctx).
+		Omit("data").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&templatePOs).Error; err != nil {
+		return nil, fmt.Errorf("list mindmap templates failed: %w", err)
+	}
+
+	templates := make([]*entity.MindMapTemplate, 0, len(templatePOs))
+	for i := range templatePOs {
+		template, err := CastMindMapTemplatePO2DO(&templatePOs[i])
+		if err != nil {
+			zlog.CtxErrorf(ctx, "failed to cast template PO to DO for templateID %s: %v", templatePOs[i].TemplateID, err)
+			continue
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+// CountMindMapTemplates 统计用户的模板数量
+func (m *mindMapPersistence) CountMindMapTemplates(ctx context.Context, userID string) (int64, error) {
+	if userID == "" {
+		return 0, fmt.Errorf("UserID is required")
+	}
+
+	var count int64
+	if err := m.db.WithContext(ctx).
+		Model(&po.MindMapTemplatePO{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count mindmap templates failed: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteMindMapTemplate 删除用户模板
+func (m *mindMapPersistence) DeleteMindMapTemplate(ctx context.Context, templateID, userID string) error {
+	if templateID == "" || userID == "" {
+		return fmt.Errorf("TemplateID and UserID are required")
+	}
+
+	result := m.db.WithContext(ctx).
+		Where("template_id = ? AND user_id = ?", templateID, userID).
+		Delete(&po.MindMapTemplatePO{})
+	if result.Error != nil {
+		return fmt.Errorf("delete mindmap template failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repo.ErrMindMapTemplateNotFound
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"forge/biz/entity"
+	"forge/infra/storage/po"
+	"forge/pkg/log/zlog"
+
+	"gorm.io/gorm"
+)
+
+// ListTrashedMindMaps 分页查询用户回收站中的导图（按删除时间倒序）
+func (m *mindMapPersistence) ListTrashedMindMaps(ctx context.Context, userID string, page, pageSize int) ([]*entity.MindMap, int64, error) {
+	if userID == "" {
+		return nil, 0, fmt.Errorf("UserID is required")
+	}
+
+	var mindmapPOs []po.MindMapPO
+	var total int64
+
+	db := m.db.WithContext(ctx).Model(&po.MindMapPO{}).Where("user_id = ? AND is_deleted = 1", userID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count trashed mindmaps failed: %w", err)
+	}
+
+	db = db.Order("deleted_at DESC")
+	if page > 0 && pageSize > 0 {
+		db = db.Offset((page - 1) * pageSize).Limit(pageSize)
+	}
+	if err := db.Find(&mindmapPOs).Error; err != nil {
+		return nil, 0, fmt.Errorf("list trashed mindmaps failed: %w", err)
+	}
+
+	mindmaps := make([]*entity.MindMap, 0, len(mindmapPOs))
+	for _, po := range mindmapPOs {
+		mindmap, err := CastMindMapPO2DO(&po)
+		if err != nil {
+			zlog.CtxErrorf(ctx, "failed to cast mindmap PO to DO for mapID %s: %v", po.MapID, err)
+			continue
+		}
+		mindmaps = append(mindmaps, mindmap)
+	}
+
+	return mindmaps, total, nil
+}
+
+// BatchGetTrashedMindMapsByIDs 批量查询指定mapIDs中位于该用户回收站的导图（用于权限验证）
+func (m *mindMapPersistence) BatchGetTrashedMindMapsByIDs(ctx context.Context, mapIDs []string, userID string) ([]*entity.MindMap, error) {
+	if len(mapIDs) == 0 || userID == "" {
+		return nil, fmt.Errorf("MapIDs and UserID are required")
+	}
+
+	var mindmapPOs []po.MindMapPO
+	err := m.db.WithContext(ctx).
+		Select("map_id", "user_id", "title", "layout", "deleted_at").
+		Where("map_id IN ? AND user_id = ? AND is_deleted = 1", mapIDs, userID).
+		Find(&mindmapPOs).Error
+	if err != nil {
+		return nil, fmt.Errorf("batch get trashed mindmaps failed: %w", err)
+	}
+
+	// 仅用于权限校验，不解析导图数据
+	mindmaps := make([]*entity.MindMap, 0, len(mindmapPOs))
+	for _, po := range mindmapPOs {
+		mindmaps = append(mindmaps, &entity.MindMap{
+			MapID:     po.MapID,
+			UserID:    po.UserID,
+			Title:     po.Title,
+			Layout:    po.Layout,
+			DeletedAt: po.DeletedAt,
+		})
+	}
+
+	return mindmaps, nil
+}
+
+// RestoreMindMaps 将回收站中的导图恢复并重建检索索引、恢复指向它们的链接
+func (m *mindMapPersistence) RestoreMindMaps(ctx context.Context, mapIDs []string, userID string) (restoredCount int, err error) {
+	if len(mapIDs) == 0 || userID == "" {
+		return 0, fmt.Errorf("MapIDs and UserID are required for restore")
+	}
+
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var mindmapPOs []po.MindMapPO
+		if err := tx.Where("map_id IN ? AND user_id = ? AND is_deleted = 1", mapIDs, userID).
+			Find(&mindmapPOs).Error; err != nil {
+			return fmt.Errorf("query trashed mindmaps failed: %w", err)
+		}
+		if len(mindmapPOs) == 0 {
+			return nil
+		}
+
+		restoredMapIDs := make([]string, 0, len(mindmapPOs))
+		for _, mindmapPO := range mindmapPOs {
+			restoredMapIDs = append(restoredMapIDs, mindmapPO.MapID)
+		}
+		result := tx.Model(&po.MindMapPO{}).
+			Where("map_id IN ? AND is_deleted = 1", restoredMapIDs).
+			Updates(map[string]interface{}{"is_deleted": 0, "deleted_at": nil})
+		if result.Error != nil {
+			return fmt.Errorf("restore mindmaps failed: %w", result.Error)
+		}
+		restoredCount = int(result.RowsAffected)
+
+		// 恢复后重新建立检索索引
+		for i := range mindmapPOs {
+			if err := rebuildMindMapSearchIndex(tx, &mindmapPOs[i]); err != nil {
+				return err
+			}
+		}
+		// 恢复因导图删除而失效的链接
+		return restoreMindMapNodeLinks(tx, mindmapPOs)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return restoredCount, nil
+}
+
+// PurgeMindMaps 彻底删除回收站中的导图及其关联数据（同一事务内完成）
+func (m *mindMapPersistence) PurgeMindMaps(ctx context.Context, mapIDs []string) (purgedCount int, err error) {
+	if len(mapIDs) == 0 {
+		return 0, fmt.Errorf("MapIDs are required for purge")
+	}
+
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 只清除仍在回收站中的导图，避免清除期间被恢复的导图丢失
+		var trashedMapIDs []string
+		if err := tx.Model(&po.MindMapPO{}).
+			Where("map_id IN ? AND is_deleted = 1", mapIDs).
+			Pluck("map_id", &trashedMapIDs).Error; err != nil {
+			return fmt.Errorf("query trashed mindmaps failed: %w", err)
+		}
+		if len(trashedMapIDs) == 0 {
+			return nil
+		}
+
+		related := []interface{}{
+			&po.MindMapRevisionPO{},
+			&po.MindMapSharePO{},
+			&po.MindMapCollaboratorPO{},
+			&po.ConversationPO{},
+			&po.MapProposalPO{},
+			&po.MindMapTagRelPO{},
+			&po.MindMapSearchTokenPO{},
+			&po.MindMapSearchDocPO{},
+		}
+		for _, model := range related {
+			if err := tx.Where("map_id IN ?", trashedMapIDs).Delete(model).Error; err != nil {
+				return fmt.Errorf("purge mindmap related data failed: %w", err)
+			}
+		}
+
+		// 从这些导图发出的链接随导图一并删除，指向它们的链接保持失效状态
+		if err := tx.Where("source_map_id IN ?", trashedMapIDs).Delete(&po.MindMapNodeLinkPO{}).Error; err != nil {
+			return fmt.Errorf("purge mindmap node links failed: %w", err)
+		}
+
+		result := tx.Where("map_id IN ? AND is_deleted = 1", trashedMapIDs).Delete(&po.MindMapPO{})
+		if result.Error != nil {
+			return fmt.Errorf("purge mindmaps failed: %w", result.Error)
+		}
+		purgedCount = int(result.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purgedCount, nil
+}
+
+// ListExpiredTrashedMindMapIDs 查询删除时间早于 deletedBefore 的回收站导图ID
+// 早期删除的数据没有 deleted_at，以最后更新时间代替
+func (m *mindMapPersistence) ListExpiredTrashedMindMapIDs(ctx context.Context, deletedBefore time.Time, limit int) ([]string, error) {
+	var mapIDs []string
+	err := m.db.WithContext(ctx).
+		Model(&po.MindMapPO{}).
+		Where("is_deleted = 1 AND (deleted_at < ? OR (deleted_at IS NULL AND updated_at < ?))", deletedBefore, deletedBefore).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("map_id", &mapIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("list expired trashed mindmaps failed: %w", err)
+	}
+	return mapIDs, nil
+}
@@ -7,15 +7,18 @@ import (
 )
 
 type ConversationPO struct {
-	ID             uint64         `gorm:"column:id;primary_key;autoIncrement"`
-	ConversationID string         `gorm:"column:conversation_id;unique"`
-	UserID         string         `gorm:"column:user_id;not null"`
-	MapID          string         `gorm:"column:map_id;not null"`
-	Title          string         `gorm:"column:title;not null"`
-	Text           string         `gorm:"column:text"`
-	Messages       datatypes.JSON `gorm:"column:messages;type:json"`
-	CreatedAt      time.Time      `gorm:"column:created_at"`
-	UpdatedAt      time.Time      `gorm:"column:updated_at"`
+	ID               uint64         `gorm:"column:id;primary_key;autoIncrement"`
+	ConversationID   string         `gorm:"column:conversation_id;unique"`
+	UserID           string         `gorm:"column:user_id;not null"`
+	MapID            string         `gorm:"column:map_id;not null"`
+	Title            string         `gorm:"column:title;not null"`
+	Text             string         `gorm:"column:text"`
+	Messages         datatypes.JSON `gorm:"column:messages;type:json"`
+	CurrentMessageID string         `gorm:"column:current_message_id;type:varchar(64)"`
+	Summary          string         `gorm:"column:summary;type:text"`
+	SummaryUntilID   string         `gorm:"column:summary_until_id;type:varchar(64)"`
+	CreatedAt        time.Time      `gorm:"column:created_at"`
+	UpdatedAt        time.Time      `gorm:"column:updated_at"`
 }
 
 func (ConversationPO) TableName() string {
@@ -34,3 +37,42 @@ func (m *ConversationPO) BeforeUpdate(tx *gorm.DB) error {
 	m.UpdatedAt = now
 	return nil
 }
+
+// MapProposalPO AI导图修改建议
+type MapProposalPO struct {
+	ID             uint64         `gorm:"column:id;primary_key;autoIncrement"`
+	ProposalID     string         `gorm:"column:proposal_id;unique"`
+	ConversationID string         `gorm:"column:conversation_id;index;not null"`
+	MapID          string         `gorm:"column:map_id;not null"`
+	UserID         string         `gorm:"column:user_id;not null"`
+	MessageID      string         `gorm:"column:message_id"`
+	ToolCallID     string         `gorm:"column:tool_call_id"`
+	ToolName       string         `gorm:"column:tool_name"`
+	Requirement    string         `gorm:"column:requirement;type:text"`
+	Data           datatypes.JSON `gorm:"column:data;type:json"`
+	BaseVersion    int64          `gorm:"column:base_version"`
+	Diff           datatypes.JSON `gorm:"column:diff;type:json"`
+	Status         string         `gorm:"column:status;not null"`
+	AppliedVersion int64          `gorm:"column:applied_version"`
+	RejectReason   string         `gorm:"column:reject_reason"`
+	ResolvedAt     *time.Time     `gorm:"column:resolved_at"`
+	CreatedAt      time.Time      `gorm:"column:created_at"`
+	UpdatedAt      time.Time      `gorm:"column:updated_at"`
+}
+
+func (MapProposalPO) TableName() string {
+	return "achobeta_forge_map_proposal"
+}
+
+func (m *MapProposalPO) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = now
+	m.UpdatedAt = now
+	return nil
+}
+
+func (m *MapProposalPO) BeforeUpdate(tx *gorm.DB) error {
+	now := time.Now()
+	m.UpdatedAt = now
+	return nil
+}
@@ -18,7 +18,12 @@ type MindMapPO struct {
 	CreatedAt *time.Time `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt *time.Time `gorm:"column:updated_at" json:"updated_at"`
 	IsDeleted int8       `gorm:"column:is_deleted;default:0" json:"is_deleted"`
-	// Version   int64   `gorm:"column:version" json:"version"` // TODO: 版本字段
+	DeletedAt *time.Time `gorm:"column:deleted_at;index" json:"deleted_at"`                           // 移入回收站的时间
+	Version   int64      `gorm:"column:version;default:1" json:"version"`                             // 乐观锁版本号
+	FolderID  string     `gorm:"column:folder_id;type:varchar(64);index;default:''" json:"folder_id"` // 所属文件夹，为空表示未归档
+
+	ThumbnailURL     string `gorm:"column:thumbnail_url;type:varchar(512);default:''" json:"thumbnail_url"` // 缩略图地址
+	ThumbnailVersion int64  `gorm:"column:thumbnail_version;default:0" json:"thumbnail_version"`            // 缩略图对应的导图版本号
 }
 
 func (MindMapPO) TableName() string {
@@ -29,6 +34,9 @@ func (m *MindMapPO) BeforeCreate(tx *gorm.DB) error {
 	now := time.Now()
 	m.CreatedAt = &now
 	m.UpdatedAt = &now
+	if m.Version == 0 {
+		m.Version = 1
+	}
 	return nil
 }
 
@@ -37,3 +45,239 @@ func (m *MindMapPO) BeforeUpdate(tx *gorm.DB) error {
 	m.UpdatedAt = &now
 	return nil
 }
+
+// MindMapRevisionPO 思维导图历史版本持久化对象 - 只插入不更新
+type MindMapRevisionPO struct {
+	ID        uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	MapID     string     `gorm:"column:map_id;type:varchar(64);uniqueIndex:idx_map_version" json:"map_id"`
+	Version   int64      `gorm:"column:version;uniqueIndex:idx_map_version" json:"version"`
+	UserID    string     `gorm:"column:user_id;type:varchar(64)" json:"user_id"` // 产生该版本的用户
+	Title     string     `gorm:"column:title;type:varchar(100)" json:"title"`
+	Desc      string     `gorm:"column:desc;type:varchar(500)" json:"desc"`
+	Data      string     `gorm:"column:data;type:json" json:"data"`
+	Layout    string     `gorm:"column:layout;type:varchar(50)" json:"layout"`
+	Source    string     `gorm:"column:source;type:varchar(32)" json:"source"`
+	CreatedAt *time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (MindMapRevisionPO) TableName() string {
+	return "achobeta_forge_mindmap_revision"
+}
+
+func (m *MindMapRevisionPO) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = &now
+	return nil
+}
+
+// MindMapSharePO 思维导图分享链接持久化对象
+type MindMapSharePO struct {
+	ID           uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	ShareID      string     `gorm:"column:share_id;type:varchar(64);uniqueIndex" json:"share_id"`
+	Token        string     `gorm:"column:token;type:varchar(64);uniqueIndex" json:"token"`
+	MapID        string     `gorm:"column:map_id;type:varchar(64);index" json:"map_id"`
+	UserID       string     `gorm:"column:user_id;type:varchar(64)" json:"user_id"`
+	PasswordHash string     `gorm:"column:password_hash;type:varchar(100)" json:"-"` // bcrypt哈希
+	ExpiresAt    *time.Time `gorm:"column:expires_at" json:"expires_at"`
+	RevokedAt    *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	HitCount     int64      `gorm:"column:hit_count;default:0" json:"hit_count"`
+	LastHitAt    *time.Time `gorm:"column:last_hit_at" json:"last_hit_at"`
+	CreatedAt    *time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (MindMapSharePO) TableName() string {
+	return "achobeta_forge_mindmap_share"
+}
+
+func (m *MindMapSharePO) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = &now
+	return nil
+}
+
+// MindMapCollaboratorPO 思维导图协作者持久化对象
+type MindMapCollaboratorPO struct {
+	ID        uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	MapID     string     `gorm:"column:map_id;type:varchar(64);uniqueIndex:idx_map_user" json:"map_id"`
+	UserID    string     `gorm:"column:user_id;type:varchar(64);uniqueIndex:idx_map_user;index" json:"user_id"`
+	Role      string     `gorm:"column:role;type:varchar(16)" json:"role"`
+	InvitedBy string     `gorm:"column:invited_by;type:varchar(64)" json:"invited_by"`
+	CreatedAt *time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt *time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (MindMapCollaboratorPO) TableName() string {
+	return "achobeta_forge_mindmap_collaborator"
+}
+
+func (m *MindMapCollaboratorPO) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = &now
+	m.UpdatedAt = &now
+	return nil
+}
+
+func (m *MindMapCollaboratorPO) BeforeUpdate(tx *gorm.DB) error {
+	now := time.Now()
+	m.UpdatedAt = &now
+	return nil
+}
+
+// MindMapSearchDocPO 全文检索文档持久化对象 - 每个导图标题或节点一条，随导图写入同步重建
+type MindMapSearchDocPO struct {
+	ID      uint64 `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	MapID   string `gorm:"column:map_id;type:varchar(64);uniqueIndex:idx_map_node" json:"map_id"`
+	NodeUID string `gorm:"column:node_uid;type:varchar(64);uniqueIndex:idx_map_node" json:"node_uid"` // 为空表示导图标题
+	Text    string `gorm:"column:text;type:text" json:"text"`
+	Note    string `gorm:"column:note;type:text" json:"note"`
+	Path    string `gorm:"column:path;type:json" json:"path"` // 根节点到该节点的文本路径，JSON数组
+	Depth   int    `gorm:"column:depth" json:"depth"`
+}
+
+func (MindMapSearchDocPO) TableName() string {
+	return "achobeta_forge_mindmap_search_doc"
+}
+
+// MindMapSearchTokenPO 全文检索倒排索引持久化对象 - 每个文档的每个词元一条
+type MindMapSearchTokenPO struct {
+	ID      uint64 `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Token   string `gorm:"column:token;type:varchar(64);index:idx_token_map" json:"token"`
+	MapID   string `gorm:"column:map_id;type:varchar(64);index:idx_token_map;index" json:"map_id"`
+	NodeUID string `gorm:"column:node_uid;type:varchar(64)" json:"node_uid"`
+	Freq    int    `gorm:"column:freq" json:"freq"` // 词元在文档中的出现次数
+}
+
+func (MindMapSearchTokenPO) TableName() string {
+	return "achobeta_forge_mindmap_search_token"
+}
+
+// MindMapFolderPO 思维导图文件夹持久化对象
+type MindMapFolderPO struct {
+	ID        uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	FolderID  string     `gorm:"column:folder_id;type:varchar(64);uniqueIndex" json:"folder_id"`
+	UserID    string     `gorm:"column:user_id;type:varchar(64);index" json:"user_id"`
+	ParentID  string     `gorm:"column:parent_id;type:varchar(64);index;default:''" json:"parent_id"` // 为空表示顶层文件夹
+	Name      string     `gorm:"column:name;type:varchar(50)" json:"name"`
+	CreatedAt *time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt *time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (MindMapFolderPO) TableName() string {
+	return "achobeta_forge_mindmap_folder"
+}
+
+func (m *MindMapFolderPO) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = &now
+	m.UpdatedAt = &now
+	return nil
+}
+
+func (m *MindMapFolderPO) BeforeUpdate(tx *gorm.DB) error {
+	now := time.Now()
+	m.UpdatedAt = &now
+	return nil
+}
+
+// MindMapTagPO 思维导图标签持久化对象
+type MindMapTagPO struct {
+	ID        uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	TagID     string     `gorm:"column:tag_id;type:varchar(64);uniqueIndex" json:"tag_id"`
+	UserID    string     `gorm:"column:user_id;type:varchar(64);uniqueIndex:idx_user_name" json:"user_id"`
+	Name      string     `gorm:"column:name;type:varchar(30);uniqueIndex:idx_user_name" json:"name"`
+	Color     string     `gorm:"column:color;type:varchar(16)" json:"color"`
+	CreatedAt *time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt *time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (MindMapTagPO) TableName() string {
+	return "achobeta_forge_mindmap_tag"
+}
+
+func (m *MindMapTagPO) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = &now
+	m.UpdatedAt = &now
+	return nil
+}
+
+func (m *MindMapTagPO) BeforeUpdate(tx *gorm.DB) error {
+	now := time.Now()
+	m.UpdatedAt = &now
+	return nil
+}
+
+// MindMapTagRelPO 思维导图与标签的关联持久化对象
+type MindMapTagRelPO struct {
+	ID        uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	MapID     string     `gorm:"column:map_id;type:varchar(64);uniqueIndex:idx_map_tag" json:"map_id"`
+	TagID     string     `gorm:"column:tag_id;type:varchar(64);uniqueIndex:idx_map_tag;index" json:"tag_id"`
+	CreatedAt *time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (MindMapTagRelPO) TableName() string {
+	return "achobeta_forge_mindmap_tag_rel"
+}
+
+func (m *MindMapTagRelPO) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = &now
+	return nil
+}
+
+// MindMapTemplatePO 用户保存的思维导图模板持久化对象（内置模板不落库）
+type MindMapTemplatePO struct {
+	ID         uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	TemplateID string     `gorm:"column:template_id;type:varchar(64);uniqueIndex" json:"template_id"`
+	UserID     string     `gorm:"column:user_id;type:varchar(64);index" json:"user_id"`
+	Name       string     `gorm:"column:name;type:varchar(50)" json:"name"`
+	Desc       string     `gorm:"column:desc;type:varchar(500)" json:"desc"`
+	Category   string     `gorm:"column:category;type:varchar(32)" json:"category"`
+	Layout     string     `gorm:"column:layout;type:varchar(50)" json:"layout"`
+	Data       string     `gorm:"column:data;type:json" json:"data"`
+	CreatedAt  *time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  *time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (MindMapTemplatePO) TableName() string {
+	return "achobeta_forge_mindmap_template"
+}
+
+func (m *MindMapTemplatePO) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = &now
+	m.UpdatedAt = &now
+	return nil
+}
+
+func (m *MindMapTemplatePO) BeforeUpdate(tx *gorm.DB) error {
+	now := time.Now()
+	m.UpdatedAt = &now
+	return nil
+}
+
+// MindMapNodeLinkPO 跨导图节点链接持久化对象
+type MindMapNodeLinkPO struct {
+	ID            uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	LinkID        string     `gorm:"column:link_id;type:varchar(64);uniqueIndex" json:"link_id"`
+	SourceMapID   string     `gorm:"column:source_map_id;type:varchar(64);uniqueIndex:idx_source_target" json:"source_map_id"`
+	SourceNodeUID string     `gorm:"column:source_node_uid;type:varchar(64);uniqueIndex:idx_source_target" json:"source_node_uid"`
+	TargetMapID   string     `gorm:"column:target_map_id;type:varchar(64);uniqueIndex:idx_source_target;index:idx_target" json:"target_map_id"`
+	TargetNodeUID string     `gorm:"column:target_node_uid;type:varchar(64);uniqueIndex:idx_source_target;index:idx_target;default:''" json:"target_node_uid"` // 为空表示指向整个导图
+	Type          string     `gorm:"column:type;type:varchar(32)" json:"type"`
+	Label         string     `gorm:"column:label;type:varchar(100)" json:"label"`
+	UserID        string     `gorm:"column:user_id;type:varchar(64)" json:"user_id"`
+	BrokenReason  string     `gorm:"column:broken_reason;type:varchar(32);default:''" json:"broken_reason"` // 为空表示链接有效
+	BrokenAt      *time.Time `gorm:"column:broken_at" json:"broken_at"`
+	CreatedAt     *time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (MindMapNodeLinkPO) TableName() string {
+	return "achobeta_forge_mindmap_node_link"
+}
+
+func (m *MindMapNodeLinkPO) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = &now
+	return nil
+}
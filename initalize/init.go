@@ -17,10 +17,12 @@ import (
 	"forge/infra/notification"
 	"forge/infra/oauth"
 	"forge/infra/storage"
+	"forge/interface/collab"
 	"forge/interface/handler"
 	"forge/interface/router"
 	"forge/pkg/log"
 	"forge/pkg/queue"
+	"time"
 
 	"github.com/unidoc/unioffice/v2/common/license"
 	pdfLicense "github.com/unidoc/unipdf/v4/common/license"
@@ -69,12 +71,26 @@ func Init() {
 	cosConfig := configs.Config().GetCOSConfig()
 	cosService := cos.NewCOSService(cosConfig)
 
-	mms := mindmapservice.NewMindMapServiceImpl(storage.GetMindMapPersistence())
+	// 依赖注入: 创建ai客户端（导图模板填充与ai对话共用）
+	aiChatClient := eino.NewAiChatClient()
+
+	mms := mindmapservice.NewMindMapServiceImpl(storage.GetMindMapPersistence(), storage.GetUserPersistence(), aiChatClient, cosService)
+
+	// 启动回收站后台清理任务
+	trashConfig := configs.Config().GetTrashConfig()
+	retentionDays := trashConfig.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = 30
+	}
+	purgeInterval := trashConfig.PurgeIntervalMinutes
+	if purgeInterval <= 0 {
+		purgeInterval = 60
+	}
+	mms.StartTrashPurger(time.Duration(retentionDays)*24*time.Hour, time.Duration(purgeInterval)*time.Minute)
 	cs := cosservice.NewCOSServiceImpl(cosService, cosConfig)
 
 	// 依赖注入: 创建ai服务实例
-	aiConfig := configs.Config().GetAiChatConfig()
-	acs := aichatservice.NewAiChatService(storage.GetAiChatPersistence(), eino.NewAiChatClient(aiConfig.ApiKey, aiConfig.ModelName))
+	acs := aichatservice.NewAiChatService(storage.GetAiChatPersistence(), storage.GetMindMapPersistence(), aiChatClient, mms)
 
 	// 依赖注入: 创建generation服务实例
 	gs := generationservice.NewGenerationService(storage.GetGenerationPersistence(), storage.GetAiChatPersistence(), storage.GetMindMapPersistence())
@@ -86,6 +102,9 @@ func Init() {
 
 	handler.MustInitHandler(us, mms, cs, acs, gs)
 
+	// 启动导图实时协同连接中心（订阅跨实例协同事件）
+	collab.InitHub()
+
 	//从配置文件中读取解析文件apikey
 	uniOfficeConfig := configs.Config().GetUniOfficeConfig()
 	license.SetMeteredKey(uniOfficeConfig.MeteredKey)
@@ -4,6 +4,8 @@ import (
 	"forge/biz/entity"
 	"forge/biz/types"
 	"forge/interface/def"
+
+	"github.com/bytedance/gg/gslice"
 )
 
 func CastProcessUserMessageReq2Params(req *def.ProcessUserMessageRequest) *types.ProcessUserMessageParams {
@@ -15,6 +17,19 @@ func CastProcessUserMessageReq2Params(req *def.ProcessUserMessageRequest) *types
 		ConversationID: req.ConversationID,
 		Message:        req.Content,
 		MapData:        req.MapData,
+		EditMessageID:  req.EditMessageID,
+		Regenerate:     req.Regenerate,
+	}
+}
+
+func CastStreamChunk2Resp(chunk types.StreamChunk) *def.StreamEventData {
+	return &def.StreamEventData{
+		Content:    chunk.Content,
+		ToolCallID: chunk.ToolCallID,
+		ToolName:   chunk.ToolName,
+		Arguments:  chunk.Arguments,
+		NewMapJson: chunk.NewMapJson,
+		ProposalID: chunk.ProposalID,
 	}
 }
 
@@ -76,6 +91,17 @@ func CastGetConversationReq2Params(req *def.GetConversationRequest) *types.GetCo
 	}
 }
 
+func CastSwitchMessageBranchReq2Params(req *def.SwitchMessageBranchRequest) *types.SwitchMessageBranchParams {
+	if req == nil {
+		return nil
+	}
+
+	return &types.SwitchMessageBranchParams{
+		ConversationID: req.ConversationID,
+		MessageID:      req.MessageID,
+	}
+}
+
 func CastUpdateConversationTitleReq2Params(req *def.UpdateConversationTitleRequest) *types.UpdateConversationTitleParams {
 	if req == nil {
 		return nil
@@ -119,3 +145,42 @@ func CastExportQualityDataReq2Params(req *def.ExportQualityDataRequest) *types.E
 		Limit:     req.Limit,
 	}
 }
+
+// CastResolveMapProposalReq2Params 转换处理修改建议请求参数
+func CastResolveMapProposalReq2Params(req *def.ResolveMapProposalRequest) *types.ResolveMapProposalParams {
+	if req == nil {
+		return nil
+	}
+	return &types.ResolveMapProposalParams{
+		ProposalID: req.ProposalID,
+		Reason:     req.Reason,
+	}
+}
+
+// CastMapProposalDO2Resp 修改建议实体转响应数据
+func CastMapProposalDO2Resp(proposal *entity.MapProposal) *def.MapProposalData {
+	if proposal == nil {
+		return nil
+	}
+	return &def.MapProposalData{
+		ProposalID:     proposal.ProposalID,
+		ConversationID: proposal.ConversationID,
+		MapID:          proposal.MapID,
+		MessageID:      proposal.MessageID,
+		ToolName:       proposal.ToolName,
+		Requirement:    proposal.Requirement,
+		Root:           CastMindMapDataDO2DTO(proposal.Data),
+		BaseVersion:    proposal.BaseVersion,
+		Diff:           CastMindMapDiffDO2DTO(proposal.Diff),
+		Status:         proposal.Status,
+		AppliedVersion: proposal.AppliedVersion,
+		RejectReason:   proposal.RejectReason,
+		ResolvedAt:     proposal.ResolvedAt,
+		CreatedAt:      proposal.CreatedAt,
+	}
+}
+
+// CastMapProposalDOs2Resp 修改建议实体列表转响应数据
+func CastMapProposalDOs2Resp(proposals []*entity.MapProposal) []*def.MapProposalData {
+	return gslice.Map(proposals, CastMapProposalDO2Resp)
+}
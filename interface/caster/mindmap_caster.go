@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"forge/biz/entity"
+	"forge/biz/mindmapservice/layout"
 	"forge/biz/types"
 	"forge/interface/def"
 
@@ -32,9 +33,10 @@ func CastUpdateMindMapReq2Params(req *def.UpdateMindMapReq) *types.UpdateMindMap
 	}
 
 	params := &types.UpdateMindMapParams{
-		Title:  req.Title,
-		Desc:   req.Desc,
-		Layout: req.Layout,
+		Title:           req.Title,
+		Desc:            req.Desc,
+		Layout:          req.Layout,
+		ExpectedVersion: req.Version,
 	}
 
 	// 处理Root字段的转换
@@ -54,11 +56,281 @@ func CastListMindMapsReq2Params(req *def.ListMindMapsReq) *types.ListMindMapsPar
 	return &types.ListMindMapsParams{
 		Title:    req.Title,
 		Layout:   req.Layout,
+		Scope:    req.Scope,
+		FolderID: req.FolderID,
+		TagIDs:   req.TagIDs,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+}
+
+// CastCreateMindMapFolderReq2Params DTO -> Service 层参数表单转换
+func CastCreateMindMapFolderReq2Params(req *def.CreateMindMapFolderReq) *types.CreateMindMapFolderParams {
+	if req == nil {
+		return nil
+	}
+	return &types.CreateMindMapFolderParams{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	}
+}
+
+// CastUpdateMindMapFolderReq2Params DTO -> Service 层参数表单转换
+func CastUpdateMindMapFolderReq2Params(req *def.UpdateMindMapFolderReq) *types.UpdateMindMapFolderParams {
+	if req == nil {
+		return nil
+	}
+	return &types.UpdateMindMapFolderParams{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	}
+}
+
+// CastCreateMindMapTagReq2Params DTO -> Service 层参数表单转换
+func CastCreateMindMapTagReq2Params(req *def.CreateMindMapTagReq) *types.CreateMindMapTagParams {
+	if req == nil {
+		return nil
+	}
+	return &types.CreateMindMapTagParams{
+		Name:  req.Name,
+		Color: req.Color,
+	}
+}
+
+// CastUpdateMindMapTagReq2Params DTO -> Service 层参数表单转换
+func CastUpdateMindMapTagReq2Params(req *def.UpdateMindMapTagReq) *types.UpdateMindMapTagParams {
+	if req == nil {
+		return nil
+	}
+	return &types.UpdateMindMapTagParams{
+		Name:  req.Name,
+		Color: req.Color,
+	}
+}
+
+// CastSaveMindMapAsTemplateReq2Params DTO -> Service 层参数表单转换
+func CastSaveMindMapAsTemplateReq2Params(req *def.SaveMindMapAsTemplateReq) *types.SaveMindMapAsTemplateParams {
+	if req == nil {
+		return nil
+	}
+	return &types.SaveMindMapAsTemplateParams{
+		MapID: req.MapID,
+		Name:  req.Name,
+		Desc:  req.Desc,
+	}
+}
+
+// CastCreateMindMapFromTemplateReq2Params DTO -> Service 层参数表单转换
+func CastCreateMindMapFromTemplateReq2Params(req *def.CreateMindMapFromTemplateReq) *types.CreateMindMapFromTemplateParams {
+	if req == nil {
+		return nil
+	}
+	return &types.CreateMindMapFromTemplateParams{
+		Title:    req.Title,
+		Desc:     req.Desc,
+		Layout:   req.Layout,
+		FillText: req.FillText,
+	}
+}
+
+// CastCreateMindMapNodeLinkReq2Params DTO -> Service 层参数表单转换
+func CastCreateMindMapNodeLinkReq2Params(req *def.CreateMindMapNodeLinkReq) *types.CreateMindMapNodeLinkParams {
+	if req == nil {
+		return nil
+	}
+	return &types.CreateMindMapNodeLinkParams{
+		SourceNodeID: req.SourceNodeID,
+		TargetMapID:  req.TargetMapID,
+		TargetNodeID: req.TargetNodeID,
+		Type:         req.Type,
+		Label:        req.Label,
+	}
+}
+
+// CastDuplicateMindMapReq2Params DTO -> Service 层参数表单转换
+func CastDuplicateMindMapReq2Params(req *def.DuplicateMindMapReq) *types.DuplicateMindMapParams {
+	if req == nil {
+		return nil
+	}
+	return &types.DuplicateMindMapParams{
+		Title: req.Title,
+	}
+}
+
+// CastExtractMindMapSubtreeReq2Params DTO -> Service 层参数表单转换
+func CastExtractMindMapSubtreeReq2Params(req *def.ExtractMindMapSubtreeReq) *types.ExtractMindMapSubtreeParams {
+	if req == nil {
+		return nil
+	}
+	return &types.ExtractMindMapSubtreeParams{
+		NodeID:          req.NodeID,
+		Title:           req.Title,
+		ReplaceWithLink: req.ReplaceWithLink,
+		ExpectedVersion: req.Version,
+	}
+}
+
+// CastGraftMindMapReq2Params DTO -> Service 层参数表单转换
+func CastGraftMindMapReq2Params(req *def.GraftMindMapReq) *types.GraftMindMapParams {
+	if req == nil {
+		return nil
+	}
+	return &types.GraftMindMapParams{
+		SourceMapID:     req.SourceMapID,
+		ParentNodeID:    req.ParentNodeID,
+		Index:           req.Index,
+		ExpectedVersion: req.Version,
+	}
+}
+
+// CastSearchMindMapsReq2Params DTO -> Service 层参数表单转换
+func CastSearchMindMapsReq2Params(req *def.SearchMindMapsReq) *types.SearchMindMapsParams {
+	if req == nil {
+		return nil
+	}
+	return &types.SearchMindMapsParams{
+		Query:    req.Query,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+}
+
+// CastMindMapSearchResults2DTOs 检索结果转DTO
+func CastMindMapSearchResults2DTOs(results []*types.MindMapSearchResult) []*def.MindMapSearchResultDTO {
+	dtos := make([]*def.MindMapSearchResultDTO, 0, len(results))
+	for _, result := range results {
+		hits := make([]*def.MindMapSearchHitDTO, 0, len(result.Hits))
+		for _, hit := range result.Hits {
+			hits = append(hits, &def.MindMapSearchHitDTO{
+				NodeID:  hit.NodeUID,
+				Path:    hit.Path,
+				Field:   hit.Field,
+				Snippet: hit.Snippet,
+			})
+		}
+		dtos = append(dtos, &def.MindMapSearchResultDTO{
+			MapID:          result.MindMap.MapID,
+			Title:          result.MindMap.Title,
+			Desc:           result.MindMap.Desc,
+			Layout:         result.MindMap.Layout,
+			UpdatedAt:      formatTime(result.MindMap.UpdatedAt),
+			Score:          result.Score,
+			TitleHighlight: result.TitleSnippet,
+			HitCount:       result.HitCount,
+			Hits:           hits,
+		})
+	}
+	return dtos
+}
+
+// CastListTrashedMindMapsReq2Params DTO -> Service 层参数表单转换
+func CastListTrashedMindMapsReq2Params(req *def.ListTrashedMindMapsReq) *types.ListTrashedMindMapsParams {
+	if req == nil {
+		return nil
+	}
+	return &types.ListTrashedMindMapsParams{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+}
+
+// CastPatchMindMapReq2Params DTO -> Service 层参数表单转换
+func CastPatchMindMapReq2Params(req *def.PatchMindMapReq) *types.PatchMindMapParams {
+	if req == nil {
+		return nil
+	}
+	return &types.PatchMindMapParams{
+		Operations:      gslice.Map(req.Operations, CastNodeOperationDTO2Params),
+		ExpectedVersion: req.Version,
+	}
+}
+
+// CastNodeOperationDTO2Params 节点操作DTO转服务层参数
+func CastNodeOperationDTO2Params(op def.NodeOperationDTO) types.NodeOperation {
+	operation := types.NodeOperation{
+		Op:       op.Op,
+		NodeID:   op.NodeID,
+		ParentID: op.ParentID,
+		Index:    op.Index,
+		Text:     op.Text,
+	}
+	if op.Node != nil {
+		node := CastMindMapDataDTO2DO(*op.Node)
+		operation.Node = &node
+	}
+	return operation
+}
+
+// CastCollabClientMessage2Params 协同编辑操作消息 -> Service 层参数转换
+func CastCollabClientMessage2Params(sessionID string, msg *def.CollabClientMessage) *types.CommitCollabOperationsParams {
+	if msg == nil {
+		return nil
+	}
+	return &types.CommitCollabOperationsParams{
+		SessionID:   sessionID,
+		BaseVersion: msg.BaseVersion,
+		Operations:  gslice.Map(msg.Operations, CastNodeOperationDTO2Params),
+	}
+}
+
+// CastImportMindMapReq2Params DTO -> Service 层参数表单转换
+func CastImportMindMapReq2Params(req *def.ImportMindMapReq) *types.ImportMindMapParams {
+	if req == nil {
+		return nil
+	}
+	return &types.ImportMindMapParams{
+		File:   req.File,
+		Title:  req.Title,
+		Desc:   req.Desc,
+		Layout: req.Layout,
+	}
+}
+
+// CastListMindMapRevisionsReq2Params DTO -> Service 层参数表单转换
+func CastListMindMapRevisionsReq2Params(req *def.ListMindMapRevisionsReq) *types.ListMindMapRevisionsParams {
+	if req == nil {
+		return nil
+	}
+	return &types.ListMindMapRevisionsParams{
 		Page:     req.Page,
 		PageSize: req.PageSize,
 	}
 }
 
+// CastRestoreMindMapRevisionReq2Params DTO -> Service 层参数表单转换
+func CastRestoreMindMapRevisionReq2Params(version int64, req *def.RestoreMindMapRevisionReq) *types.RestoreMindMapRevisionParams {
+	if req == nil {
+		return nil
+	}
+	return &types.RestoreMindMapRevisionParams{
+		Version:         version,
+		ExpectedVersion: req.Version,
+	}
+}
+
+// CastCreateMindMapShareReq2Params DTO -> Service 层参数表单转换
+func CastCreateMindMapShareReq2Params(req *def.CreateMindMapShareReq) *types.CreateMindMapShareParams {
+	if req == nil {
+		return nil
+	}
+	return &types.CreateMindMapShareParams{
+		ExpiresAt: req.ExpiresAt,
+		Password:  req.Password,
+	}
+}
+
+// CastAddMindMapCollaboratorReq2Params DTO -> Service 层参数表单转换
+func CastAddMindMapCollaboratorReq2Params(req *def.AddMindMapCollaboratorReq) *types.AddMindMapCollaboratorParams {
+	if req == nil {
+		return nil
+	}
+	return &types.AddMindMapCollaboratorParams{
+		AccountType: req.AccountType,
+		Account:     req.Account,
+		Role:        req.Role,
+	}
+}
+
 // Entity -> DTO 转换
 
 // CastMindMapDO2DTO 实体转DTO
@@ -73,8 +345,15 @@ func CastMindMapDO2DTO(mindmap *entity.MindMap) *def.MindMapDTO {
 		Desc:      mindmap.Desc,
 		Layout:    mindmap.Layout,
 		Root:      CastMindMapDataDO2DTO(mindmap.Data),
+		Version:   mindmap.Version,
 		CreatedAt: formatTime(mindmap.CreatedAt),
 		UpdatedAt: formatTime(mindmap.UpdatedAt),
+		DeletedAt: formatTimePtr(mindmap.DeletedAt),
+		FolderID:  mindmap.FolderID,
+		TagIDs:    mindmap.TagIDs,
+
+		ThumbnailURL:     mindmap.ThumbnailURL,
+		ThumbnailVersion: mindmap.ThumbnailVersion,
 	}
 }
 
@@ -83,6 +362,179 @@ func CastMindMapDOs2DTOs(mindmaps []*entity.MindMap) []*def.MindMapDTO {
 	return gslice.Map(mindmaps, CastMindMapDO2DTO)
 }
 
+// CastMindMapRevisionDO2DTO 历史版本实体转DTO（包含完整数据）
+func CastMindMapRevisionDO2DTO(revision *entity.MindMapRevision) *def.MindMapRevisionDTO {
+	if revision == nil {
+		return nil
+	}
+	dto := CastMindMapRevisionDO2SummaryDTO(revision)
+	root := CastMindMapDataDO2DTO(revision.Data)
+	dto.Root = &root
+	return dto
+}
+
+// CastMindMapRevisionDO2SummaryDTO 历史版本实体转摘要DTO（不含数据，用于列表）
+func CastMindMapRevisionDO2SummaryDTO(revision *entity.MindMapRevision) *def.MindMapRevisionDTO {
+	if revision == nil {
+		return nil
+	}
+	return &def.MindMapRevisionDTO{
+		MapID:     revision.MapID,
+		Version:   revision.Version,
+		UserID:    revision.UserID,
+		Title:     revision.Title,
+		Desc:      revision.Desc,
+		Layout:    revision.Layout,
+		Source:    revision.Source,
+		CreatedAt: formatTime(revision.CreatedAt),
+	}
+}
+
+// CastMindMapRevisionDOs2SummaryDTOs 历史版本实体列表转摘要DTO列表
+func CastMindMapRevisionDOs2SummaryDTOs(revisions []*entity.MindMapRevision) []*def.MindMapRevisionDTO {
+	return gslice.Map(revisions, CastMindMapRevisionDO2SummaryDTO)
+}
+
+// CastMindMapShareDO2DTO 分享链接实体转DTO
+func CastMindMapShareDO2DTO(share *entity.MindMapShare) *def.MindMapShareDTO {
+	if share == nil {
+		return nil
+	}
+	return &def.MindMapShareDTO{
+		ShareID:     share.ShareID,
+		Token:       share.Token,
+		MapID:       share.MapID,
+		HasPassword: share.HasPassword(),
+		Expired:     share.IsExpired(time.Now()),
+		Revoked:     share.IsRevoked(),
+		HitCount:    share.HitCount,
+		ExpiresAt:   formatTimePtr(share.ExpiresAt),
+		RevokedAt:   formatTimePtr(share.RevokedAt),
+		LastHitAt:   formatTimePtr(share.LastHitAt),
+		CreatedAt:   formatTime(share.CreatedAt),
+	}
+}
+
+// CastMindMapShareDOs2DTOs 分享链接实体列表转DTO列表
+func CastMindMapShareDOs2DTOs(shares []*entity.MindMapShare) []*def.MindMapShareDTO {
+	return gslice.Map(shares, CastMindMapShareDO2DTO)
+}
+
+// CastMindMapDO2SharedDTO 实体转分享只读DTO
+func CastMindMapDO2SharedDTO(mindmap *entity.MindMap) *def.SharedMindMapDTO {
+	if mindmap == nil {
+		return nil
+	}
+	return &def.SharedMindMapDTO{
+		MapID:     mindmap.MapID,
+		Title:     mindmap.Title,
+		Desc:      mindmap.Desc,
+		Layout:    mindmap.Layout,
+		Root:      CastMindMapDataDO2DTO(mindmap.Data),
+		UpdatedAt: formatTime(mindmap.UpdatedAt),
+	}
+}
+
+// CastMindMapCollaboratorDO2DTO 协作者实体转DTO
+func CastMindMapCollaboratorDO2DTO(collaborator *entity.MindMapCollaborator) *def.MindMapCollaboratorDTO {
+	if collaborator == nil {
+		return nil
+	}
+	return &def.MindMapCollaboratorDTO{
+		UserID:    collaborator.UserID,
+		UserName:  collaborator.UserName,
+		Avatar:    collaborator.Avatar,
+		Role:      collaborator.Role,
+		InvitedBy: collaborator.InvitedBy,
+		CreatedAt: formatTime(collaborator.CreatedAt),
+		UpdatedAt: formatTime(collaborator.UpdatedAt),
+	}
+}
+
+// CastMindMapCollaboratorDOs2DTOs 协作者实体列表转DTO列表
+func CastMindMapCollaboratorDOs2DTOs(collaborators []*entity.MindMapCollaborator) []*def.MindMapCollaboratorDTO {
+	return gslice.Map(collaborators, CastMindMapCollaboratorDO2DTO)
+}
+
+// CastNodeOperationParams2DTO 服务层节点操作转DTO
+func CastNodeOperationParams2DTO(op types.NodeOperation) def.NodeOperationDTO {
+	dto := def.NodeOperationDTO{
+		Op:       op.Op,
+		NodeID:   op.NodeID,
+		ParentID: op.ParentID,
+		Index:    op.Index,
+		Text:     op.Text,
+	}
+	if op.Node != nil {
+		node := CastMindMapDataDO2DTO(*op.Node)
+		dto.Node = &node
+	}
+	return dto
+}
+
+// CastCollabMember2DTO 协同在线成员转DTO
+func CastCollabMember2DTO(member *types.CollabMember) *def.CollabMemberDTO {
+	if member == nil {
+		return nil
+	}
+	return &def.CollabMemberDTO{
+		SessionID:      member.SessionID,
+		UserID:         member.UserID,
+		UserName:       member.UserName,
+		Avatar:         member.Avatar,
+		Role:           member.Role,
+		SelectedNodeID: member.SelectedNodeID,
+	}
+}
+
+// CastCollabSession2InitMessage 协同会话转初始状态消息
+func CastCollabSession2InitMessage(session *types.CollabSession) *def.CollabServerMessage {
+	if session == nil {
+		return nil
+	}
+	msg := &def.CollabServerMessage{
+		Type:      def.CollabMsgInit,
+		SessionID: session.SessionID,
+		Role:      session.Role,
+		MindMap:   CastMindMapDO2DTO(session.MindMap),
+		Members:   gslice.Map(session.Members, CastCollabMember2DTO),
+	}
+	if session.MindMap != nil {
+		msg.Version = session.MindMap.Version
+	}
+	return msg
+}
+
+// CastCollabCommitResult2AckMessage 协同操作提交结果转确认消息
+func CastCollabCommitResult2AckMessage(clientSeq int64, result *types.CollabCommitResult) *def.CollabServerMessage {
+	if result == nil {
+		return nil
+	}
+	return &def.CollabServerMessage{
+		Type:           def.CollabMsgAck,
+		ClientSeq:      clientSeq,
+		Version:        result.Version,
+		Operations:     gslice.Map(result.Operations, CastNodeOperationParams2DTO),
+		AddedNodeIDs:   result.AddedNodeIDs,
+		SkippedIndexes: result.SkippedIndexes,
+	}
+}
+
+// CastCollabEvent2Message 协同事件转广播消息
+func CastCollabEvent2Message(event *types.CollabEvent) *def.CollabServerMessage {
+	if event == nil {
+		return nil
+	}
+	return &def.CollabServerMessage{
+		Type:       event.Type,
+		SessionID:  event.SessionID,
+		UserID:     event.UserID,
+		Version:    event.Version,
+		Member:     CastCollabMember2DTO(event.Member),
+		Operations: gslice.Map(event.Operations, CastNodeOperationParams2DTO),
+	}
+}
+
 // CastMindMapDataDO2DTO 思维导图数据实体转DTO
 func CastMindMapDataDO2DTO(data entity.MindMapData) def.MindMapData {
 	return def.MindMapData{
@@ -101,15 +553,34 @@ func CastMindMapDataDTO2DO(data def.MindMapData) entity.MindMapData {
 
 // CastNodeDataDO2DTO 节点数据实体转DTO
 func CastNodeDataDO2DTO(data entity.NodeData) def.NodeData {
+	expand := !data.Collapsed
 	return def.NodeData{
-		Text: data.Text,
+		UID:            data.UID,
+		Text:           data.Text,
+		Note:           data.Note,
+		Hyperlink:      data.Hyperlink,
+		HyperlinkTitle: data.HyperlinkTitle,
+		Color:          data.Color,
+		Icon:           data.Icon,
+		Tag:            data.Tags,
+		Expand:         &expand,
+		IsActive:       data.IsActive,
 	}
 }
 
 // CastNodeDataDTO2DO 节点数据DTO转实体
 func CastNodeDataDTO2DO(data def.NodeData) entity.NodeData {
 	return entity.NodeData{
-		Text: data.Text,
+		UID:            data.UID,
+		Text:           data.Text,
+		Note:           data.Note,
+		Hyperlink:      data.Hyperlink,
+		HyperlinkTitle: data.HyperlinkTitle,
+		Color:          data.Color,
+		Icon:           data.Icon,
+		Tags:           data.Tag,
+		Collapsed:      data.Expand != nil && !*data.Expand,
+		IsActive:       data.IsActive,
 	}
 }
 
@@ -120,3 +591,227 @@ func formatTime(t time.Time) string {
 	}
 	return t.Format(time.RFC3339)
 }
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatTime(*t)
+}
+
+// CastMindMapFolderDO2DTO 文件夹实体转DTO
+func CastMindMapFolderDO2DTO(folder *entity.MindMapFolder) *def.MindMapFolderDTO {
+	if folder == nil {
+		return nil
+	}
+	return &def.MindMapFolderDTO{
+		FolderID:  folder.FolderID,
+		ParentID:  folder.ParentID,
+		Name:      folder.Name,
+		CreatedAt: formatTime(folder.CreatedAt),
+		UpdatedAt: formatTime(folder.UpdatedAt),
+	}
+}
+
+// CastMindMapFolderDOs2DTOs 文件夹实体列表转DTO列表
+func CastMindMapFolderDOs2DTOs(folders []*entity.MindMapFolder) []*def.MindMapFolderDTO {
+	return gslice.Map(folders, CastMindMapFolderDO2DTO)
+}
+
+// CastMindMapTagDO2DTO 标签实体转DTO
+func CastMindMapTagDO2DTO(tag *entity.MindMapTag) *def.MindMapTagDTO {
+	if tag == nil {
+		return nil
+	}
+	return &def.MindMapTagDTO{
+		TagID:     tag.TagID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		CreatedAt: formatTime(tag.CreatedAt),
+		UpdatedAt: formatTime(tag.UpdatedAt),
+	}
+}
+
+// CastMindMapTagDOs2DTOs 标签实体列表转DTO列表
+func CastMindMapTagDOs2DTOs(tags []*entity.MindMapTag) []*def.MindMapTagDTO {
+	return gslice.Map(tags, CastMindMapTagDO2DTO)
+}
+
+// CastMindMapTemplateDO2DTO 模板实体转DTO（包含完整数据）
+func CastMindMapTemplateDO2DTO(template *entity.MindMapTemplate) *def.MindMapTemplateDTO {
+	if template == nil {
+		return nil
+	}
+	dto := CastMindMapTemplateDO2SummaryDTO(template)
+	root := CastMindMapDataDO2DTO(template.Data)
+	dto.Root = &root
+	return dto
+}
+
+// CastMindMapTemplateDO2SummaryDTO 模板实体转摘要DTO（不含数据，用于列表）
+func CastMindMapTemplateDO2SummaryDTO(template *entity.MindMapTemplate) *def.MindMapTemplateDTO {
+	if template == nil {
+		return nil
+	}
+	return &def.MindMapTemplateDTO{
+		TemplateID: template.TemplateID,
+		Name:       template.Name,
+		Desc:       template.Desc,
+		Category:   template.Category,
+		Layout:     template.Layout,
+		BuiltIn:    template.BuiltIn,
+		CreatedAt:  formatTime(template.CreatedAt),
+		UpdatedAt:  formatTime(template.UpdatedAt),
+	}
+}
+
+// CastMindMapTemplateDOs2SummaryDTOs 模板实体列表转摘要DTO列表
+func CastMindMapTemplateDOs2SummaryDTOs(templates []*entity.MindMapTemplate) []*def.MindMapTemplateDTO {
+	return gslice.Map(templates, CastMindMapTemplateDO2SummaryDTO)
+}
+
+// CastMindMapNodeLinkDO2DTO 节点链接实体转DTO
+func CastMindMapNodeLinkDO2DTO(link *entity.MindMapNodeLink) *def.MindMapNodeLinkDTO {
+	if link == nil {
+		return nil
+	}
+	dto := &def.MindMapNodeLinkDTO{
+		LinkID:         link.LinkID,
+		SourceMapID:    link.SourceMapID,
+		SourceNodeID:   link.SourceNodeUID,
+		SourceMapTitle: link.SourceMapTitle,
+		SourceNodeText: link.SourceNodeText,
+		TargetMapID:    link.TargetMapID,
+		TargetNodeID:   link.TargetNodeUID,
+		TargetMapTitle: link.TargetMapTitle,
+		TargetNodeText: link.TargetNodeText,
+		Type:           link.Type,
+		Label:          link.Label,
+		Broken:         link.IsBroken(),
+		BrokenReason:   link.BrokenReason,
+		CreatedAt:      formatTime(link.CreatedAt),
+	}
+	if link.BrokenAt != nil {
+		dto.BrokenAt = formatTime(*link.BrokenAt)
+	}
+	return dto
+}
+
+// CastMindMapNodeLinkDOs2DTOs 节点链接实体列表转DTO列表
+func CastMindMapNodeLinkDOs2DTOs(links []*entity.MindMapNodeLink) []*def.MindMapNodeLinkDTO {
+	return gslice.Map(links, CastMindMapNodeLinkDO2DTO)
+}
+
+// CastMindMapDiffDO2DTO 结构差异实体转DTO
+func CastMindMapDiffDO2DTO(diff *entity.MindMapDiff) *def.DiffMindMapResp {
+	if diff == nil {
+		return nil
+	}
+	changes := make([]*def.MindMapNodeChangeDTO, 0, len(diff.Changes))
+	for _, change := range diff.Changes {
+		changes = append(changes, &def.MindMapNodeChangeDTO{
+			Type:         change.Type,
+			OldUID:       change.OldUID,
+			NewUID:       change.NewUID,
+			OldText:      change.OldText,
+			NewText:      change.NewText,
+			OldParentUID: change.OldParentUID,
+			NewParentUID: change.NewParentUID,
+			OldIndex:     change.OldIndex,
+			NewIndex:     change.NewIndex,
+			Path:         change.Path,
+			MatchedBy:    change.MatchedBy,
+			Similarity:   change.Similarity,
+		})
+	}
+	return &def.DiffMindMapResp{
+		FromVersion: diff.FromVersion,
+		ToVersion:   diff.ToVersion,
+		HasChanges:  diff.HasChanges(),
+		Changes:     changes,
+		Stats: def.MindMapDiffStatsDTO{
+			Added:     diff.Stats.Added,
+			Removed:   diff.Stats.Removed,
+			Moved:     diff.Stats.Moved,
+			Renamed:   diff.Stats.Renamed,
+			Reordered: diff.Stats.Reordered,
+			Unchanged: diff.Stats.Unchanged,
+		},
+	}
+}
+
+// CastMindMapLintReportDO2DTO 检查报告实体转DTO
+func CastMindMapLintReportDO2DTO(report *entity.MindMapLintReport) *def.LintMindMapResp {
+	if report == nil {
+		return nil
+	}
+	issues := make([]*def.MindMapLintIssueDTO, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		issues = append(issues, &def.MindMapLintIssueDTO{
+			Rule:     issue.Rule,
+			Severity: issue.Severity,
+			Message:  issue.Message,
+			NodeID:   issue.NodeUID,
+			Path:     issue.Path,
+		})
+	}
+	qualityIssues := report.QualityIssues
+	if qualityIssues == nil {
+		qualityIssues = []string{}
+	}
+	return &def.LintMindMapResp{
+		MapID:         report.MapID,
+		Version:       report.Version,
+		FormatScore:   report.FormatScore,
+		ContentScore:  report.ContentScore,
+		OverallScore:  report.OverallScore,
+		QualityIssues: qualityIssues,
+		NodeCount:     report.NodeCount,
+		MaxDepth:      report.MaxDepth,
+		AvgTextLength: report.AvgTextLength,
+		Issues:        issues,
+	}
+}
+
+// CastLayoutResult2DTO 排版结果转DTO
+func CastLayoutResult2DTO(result *layout.Result, version int64) *def.ComputeMindMapLayoutResp {
+	if result == nil {
+		return nil
+	}
+	nodes := make([]*def.LayoutNodeDTO, 0, len(result.Nodes))
+	for _, n := range result.Nodes {
+		nodes = append(nodes, &def.LayoutNodeDTO{
+			NodeID:      n.Node.Data.UID,
+			Depth:       n.Depth,
+			Box:         castLayoutRect(n.Rect),
+			Lines:       n.Lines,
+			FontSize:    n.FontSize,
+			Folded:      n.Folded,
+			HiddenCount: n.Hidden,
+		})
+	}
+	edges := make([]*def.LayoutEdgeDTO, 0, len(result.Edges))
+	for _, e := range result.Edges {
+		points := make([]def.LayoutPointDTO, 0, len(e.Points))
+		for _, p := range e.Points {
+			points = append(points, def.LayoutPointDTO{X: p.X, Y: p.Y})
+		}
+		edges = append(edges, &def.LayoutEdgeDTO{
+			FromNodeID: e.FromUID,
+			ToNodeID:   e.ToUID,
+			Points:     points,
+			Curved:     e.Curved,
+		})
+	}
+	return &def.ComputeMindMapLayoutResp{
+		Version: version,
+		Layout:  result.Layout,
+		Bounds:  castLayoutRect(result.Bounds),
+		Nodes:   nodes,
+		Edges:   edges,
+	}
+}
+
+func castLayoutRect(r layout.Rect) def.LayoutRectDTO {
+	return def.LayoutRectDTO{X: r.X, Y: r.Y, Width: r.W, Height: r.H}
+}
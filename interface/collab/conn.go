@@ -0,0 +1,106 @@
+package collab
+
+import (
+	"sync"
+	"time"
+
+	"forge/interface/def"
+	"forge/pkg/log/zlog"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second  // 单条消息写超时
+	pongWait       = 60 * time.Second  // 等待客户端pong的超时时间
+	pingPeriod     = pongWait * 9 / 10 // 服务端发送ping的周期，需小于pongWait
+	maxMessageSize = 1 << 20           // 单条客户端消息最大字节数
+	sendBufferSize = 256               // 发送队列长度，写满说明客户端消费过慢
+)
+
+// Conn 单个协同编辑WebSocket连接，所有写操作经由发送队列在 WritePump 中串行执行
+type Conn struct {
+	ws        *websocket.Conn
+	mapID     string
+	sessionID string
+	send      chan *def.CollabServerMessage
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewConn(ws *websocket.Conn, mapID, sessionID string) *Conn {
+	ws.SetReadLimit(maxMessageSize)
+	_ = ws.SetReadDeadline(time.Now().Add(pongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	return &Conn{
+		ws:        ws,
+		mapID:     mapID,
+		sessionID: sessionID,
+		send:      make(chan *def.CollabServerMessage, sendBufferSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// Done 连接关闭时关闭的通道
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// ReadMessage 读取一条客户端消息（阻塞），收到任何消息都会顺延读超时
+func (c *Conn) ReadMessage(msg *def.CollabClientMessage) error {
+	if err := c.ws.ReadJSON(msg); err != nil {
+		return err
+	}
+	return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+}
+
+// Send 将消息放入发送队列；队列已满时断开连接，由客户端重连后重新同步
+func (c *Conn) Send(msg *def.CollabServerMessage) {
+	if msg == nil {
+		return
+	}
+	select {
+	case <-c.done:
+	case c.send <- msg:
+	default:
+		zlog.Warnf("collab send buffer full, closing connection, mapID: %s, sessionID: %s", c.mapID, c.sessionID)
+		c.Close()
+	}
+}
+
+// WritePump 串行写出发送队列中的消息并定期发送ping，直到连接关闭
+func (c *Conn) WritePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.Close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteJSON(msg); err != nil {
+				zlog.Warnf("collab write failed, mapID: %s, sessionID: %s, err: %v", c.mapID, c.sessionID, err)
+				return
+			}
+		case <-ticker.C:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Close 关闭连接，可重复调用
+func (c *Conn) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.ws.Close()
+	})
+}
@@ -0,0 +1,79 @@
+package collab
+
+import (
+	"context"
+	"sync"
+
+	"forge/interface/def"
+	"forge/interface/handler"
+	"forge/pkg/log/zlog"
+)
+
+// Hub 管理本实例上的协同编辑连接（按导图分组）
+// 各实例通过服务层订阅同一组协同事件（Redis发布订阅），再分发给本实例上的连接，从而支持多实例部署
+type Hub struct {
+	mu    sync.RWMutex
+	rooms map[string]map[*Conn]struct{}
+}
+
+var (
+	hub     *Hub
+	hubOnce sync.Once
+)
+
+// InitHub 初始化协同编辑连接中心并开始订阅协同事件
+func InitHub() {
+	hubOnce.Do(func() {
+		hub = &Hub{
+			rooms: make(map[string]map[*Conn]struct{}),
+		}
+		go handler.GetHandler().SubscribeMindMapCollabEvents(context.Background(), hub.dispatch)
+		zlog.Infof("mindmap collab hub started")
+	})
+}
+
+// GetHub 获取协同编辑连接中心
+func GetHub() *Hub {
+	return hub
+}
+
+// Register 将连接加入所属导图的房间
+func (h *Hub) Register(conn *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rooms[conn.mapID] == nil {
+		h.rooms[conn.mapID] = make(map[*Conn]struct{})
+	}
+	h.rooms[conn.mapID][conn] = struct{}{}
+}
+
+// Unregister 将连接移出房间
+func (h *Hub) Unregister(conn *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	room := h.rooms[conn.mapID]
+	if room == nil {
+		return
+	}
+	delete(room, conn)
+	if len(room) == 0 {
+		delete(h.rooms, conn.mapID)
+	}
+}
+
+// dispatch 将事件分发给本实例上订阅该导图的连接，事件来源会话自身不再重复接收
+func (h *Hub) dispatch(mapID string, msg *def.CollabServerMessage) {
+	h.mu.RLock()
+	conns := make([]*Conn, 0, len(h.rooms[mapID]))
+	for conn := range h.rooms[mapID] {
+		if msg.SessionID != "" && msg.SessionID == conn.sessionID {
+			continue
+		}
+		conns = append(conns, conn)
+	}
+	h.mu.RUnlock()
+
+	for _, conn := range conns {
+		conn.Send(msg)
+	}
+}
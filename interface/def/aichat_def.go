@@ -9,16 +9,30 @@ import (
 // 请求体
 type ProcessUserMessageRequest struct {
 	ConversationID string `json:"conversation_id" binding:"required"`
-	Content        string `json:"content" binding:"required"`
+	Content        string `json:"content"` // regenerate 为 true 时可为空
 	MapData        string `json:"map_data"`
+	EditMessageID  string `json:"edit_message_id"` // 编辑该用户消息并重新生成，原消息保留为兄弟分支
+	Regenerate     bool   `json:"regenerate"`      // 重新生成当前分支最后一轮回复
 }
 
 type ProcessUserMessageResponse struct {
 	NewMapJson string `json:"new_map_json"`
+	ProposalID string `json:"proposal_id,omitempty"` // new_map_json 对应的待处理修改建议
 	Content    string `json:"content"`
 	Success    bool   `json:"success"`
 }
 
+// 流式对话的SSE事件数据，事件类型见 types.StreamEvent* 常量
+type StreamEventData struct {
+	Content    string `json:"content,omitempty"`
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	ToolName   string `json:"tool_name,omitempty"`
+	Arguments  string `json:"arguments,omitempty"`
+	NewMapJson string `json:"new_map_json,omitempty"`
+	ProposalID string `json:"proposal_id,omitempty"`
+	Message    string `json:"message,omitempty"` // error 事件的错误信息
+}
+
 type SaveNewConversationRequest struct {
 	Title   string `json:"title" binding:"required"`
 	MapID   string `json:"map_id" binding:"required"`
@@ -59,10 +73,21 @@ type GetConversationRequest struct {
 }
 
 type GetConversationResponse struct {
-	Title          string            `json:"title"`
-	Messages       []*entity.Message `json:"messages"`
-	ConversationID string            `json:"conversation_id"`
-	Success        bool              `json:"success"`
+	Title            string            `json:"title"`
+	Messages         []*entity.Message `json:"messages"`           // 所有分支的消息，按 parent_id 组成树
+	CurrentMessageID string            `json:"current_message_id"` // 当前分支最后一条消息
+	ConversationID   string            `json:"conversation_id"`
+	Success          bool              `json:"success"`
+}
+
+type SwitchMessageBranchRequest struct {
+	ConversationID string `json:"conversation_id" binding:"required"`
+	MessageID      string `json:"message_id" binding:"required"`
+}
+
+type SwitchMessageBranchResponse struct {
+	CurrentMessageID string `json:"current_message_id"`
+	Success          bool   `json:"success"`
 }
 
 type UpdateConversationTitleRequest struct {
@@ -122,3 +147,40 @@ type TriggerQualityAssessmentResponse struct {
 	ErrorCount     int    `json:"error_count"`
 	Message        string `json:"message,omitempty"`
 }
+
+// 导图修改建议相关定义
+type MapProposalData struct {
+	ProposalID     string           `json:"proposal_id"`
+	ConversationID string           `json:"conversation_id"`
+	MapID          string           `json:"map_id"`
+	MessageID      string           `json:"message_id"`
+	ToolName       string           `json:"tool_name"`
+	Requirement    string           `json:"requirement"`
+	Root           MindMapData      `json:"root"`
+	BaseVersion    int64            `json:"base_version"`
+	Diff           *DiffMindMapResp `json:"diff"`
+	Status         string           `json:"status"` // pending/accepted/rejected/superseded
+	AppliedVersion int64            `json:"applied_version,omitempty"`
+	RejectReason   string           `json:"reject_reason,omitempty"`
+	ResolvedAt     *time.Time       `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+type GetMapProposalListRequest struct {
+	ConversationID string `json:"conversation_id" binding:"required"`
+}
+
+type GetMapProposalListResponse struct {
+	List    []*MapProposalData `json:"list"`
+	Success bool               `json:"success"`
+}
+
+type ResolveMapProposalRequest struct {
+	ProposalID string `json:"proposal_id" binding:"required"`
+	Reason     string `json:"reason"` // 拒绝原因，可选
+}
+
+type ResolveMapProposalResponse struct {
+	Proposal *MapProposalData `json:"proposal"`
+	Success  bool             `json:"success"`
+}
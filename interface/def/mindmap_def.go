@@ -1,19 +1,27 @@
 package def
 
+import (
+	"mime/multipart"
+	"time"
+)
+
 // 创建请求
 type CreateMindMapReq struct {
 	Title  string      `json:"title" binding:"required,max=100"`
 	Desc   string      `json:"desc" binding:"max=500"`
-	Layout string      `json:"layout" binding:"required"`
+	Layout string      `json:"layout" binding:"required"` // mindMap / logicalStructure / organizationStructure / timeline / fishbone
 	Root   MindMapData `json:"root" binding:"required"`
 }
 
 // 列表查询请求
 type ListMindMapsReq struct {
-	Title    string `form:"title"`
-	Layout   string `form:"layout"`
-	Page     int    `form:"page,default=1"`
-	PageSize int    `form:"page_size,default=20"`
+	Title    string   `form:"title"`
+	Layout   string   `form:"layout"`
+	Scope    string   `form:"scope" binding:"omitempty,oneof=owned shared all"` // 不传只返回自己创建的导图
+	FolderID string   `form:"folder_id"`                                        // 文件夹ID，unfiled 表示未归档的导图
+	TagIDs   []string `form:"tag_ids" binding:"max=20"`                         // 标签ID（可重复传入），导图需包含全部标签
+	Page     int      `form:"page,default=1"`
+	PageSize int      `form:"page_size,default=20"`
 }
 
 // 更新请求
@@ -22,6 +30,8 @@ type UpdateMindMapReq struct {
 	Desc   *string      `json:"desc,omitempty" binding:"omitempty,max=500"`
 	Layout *string      `json:"layout,omitempty"`
 	Root   *MindMapData `json:"root,omitempty"`
+
+	Version *int64 `json:"version,omitempty"` // 客户端持有的版本号，传入时开启乐观锁校验
 }
 
 // 思维导图DTO
@@ -32,14 +42,29 @@ type MindMapDTO struct {
 	Desc      string      `json:"desc"`
 	Layout    string      `json:"layout"`
 	Root      MindMapData `json:"root"`
+	Version   int64       `json:"version"`
 	CreatedAt string      `json:"createdAt,omitempty"`
 	UpdatedAt string      `json:"updatedAt,omitempty"`
+	DeletedAt string      `json:"deletedAt,omitempty"` // 仅回收站列表返回
+	FolderID  string      `json:"folderId,omitempty"`
+	TagIDs    []string    `json:"tagIds,omitempty"` // 仅列表返回
+
+	ThumbnailURL     string `json:"thumbnailUrl,omitempty"`     // 缩略图地址，保存后异步生成
+	ThumbnailVersion int64  `json:"thumbnailVersion,omitempty"` // 缩略图对应的版本号，小于version时为旧图
 }
 
 // 节点数据DTO
 type NodeData struct {
-	Text string `json:"text"`
-	// 可扩展其他节点属性，如颜色、图标等
+	UID            string   `json:"uid,omitempty"`
+	Text           string   `json:"text"`
+	Note           string   `json:"note,omitempty"`           // 备注
+	Hyperlink      string   `json:"hyperlink,omitempty"`      // 超链接地址
+	HyperlinkTitle string   `json:"hyperlinkTitle,omitempty"` // 超链接标题
+	Color          string   `json:"color,omitempty"`          // 文字颜色
+	Icon           []string `json:"icon,omitempty"`           // 图标列表
+	Tag            []string `json:"tag,omitempty"`            // 标签列表
+	Expand         *bool    `json:"expand,omitempty"`         // 是否展开子节点，不传默认展开
+	IsActive       bool     `json:"isActive,omitempty"`       // 是否处于激活（选中）状态
 }
 
 // 思维导图数据DTO - 递归树结构
@@ -65,7 +90,8 @@ type ListMindMapsResp struct {
 }
 
 type UpdateMindMapResp struct {
-	Success bool `json:"success"`
+	Success bool  `json:"success"`
+	Version int64 `json:"version"`
 }
 
 type DeleteMindMapResp struct {
@@ -84,3 +110,650 @@ type BatchDeleteMindMapResp struct {
 	FailedCount  int      `json:"failedCount"`
 	FailedMapIDs []string `json:"failedMapIds,omitempty"`
 }
+
+// 文件夹DTO
+type MindMapFolderDTO struct {
+	FolderID  string `json:"folderId"`
+	ParentID  string `json:"parentId"` // 为空表示顶层文件夹
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+type ListMindMapFoldersResp struct {
+	List []*MindMapFolderDTO `json:"list"` // 扁平列表，按 parentId 组装树结构
+}
+
+// 创建文件夹请求
+type CreateMindMapFolderReq struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	ParentID string `json:"parentId"` // 为空表示顶层文件夹
+}
+
+type CreateMindMapFolderResp struct {
+	*MindMapFolderDTO
+}
+
+// 修改文件夹请求（重命名或移动）
+type UpdateMindMapFolderReq struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,max=50"`
+	ParentID *string `json:"parentId,omitempty"` // 空字符串表示移动到顶层
+}
+
+type UpdateMindMapFolderResp struct {
+	*MindMapFolderDTO
+}
+
+type DeleteMindMapFolderResp struct {
+	Success    bool `json:"success"`
+	MovedCount int  `json:"movedCount"` // 移出为未归档的导图数量
+}
+
+// 批量移动导图到文件夹请求
+type MoveMindMapsReq struct {
+	MapIDs   []string `json:"mapIds" binding:"required,min=1,max=100,dive,required"`
+	FolderID string   `json:"folderId"` // 为空表示移出文件夹
+}
+
+type MoveMindMapsResp struct {
+	Success      bool     `json:"success"`
+	MovedCount   int      `json:"movedCount"`
+	FailedCount  int      `json:"failedCount"`
+	FailedMapIDs []string `json:"failedMapIds,omitempty"`
+}
+
+// 标签DTO
+type MindMapTagDTO struct {
+	TagID     string `json:"tagId"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+type ListMindMapTagsResp struct {
+	List []*MindMapTagDTO `json:"list"`
+}
+
+// 创建标签请求
+type CreateMindMapTagReq struct {
+	Name  string `json:"name" binding:"required,max=30"`
+	Color string `json:"color" binding:"omitempty,max=16"`
+}
+
+type CreateMindMapTagResp struct {
+	*MindMapTagDTO
+}
+
+// 修改标签请求
+type UpdateMindMapTagReq struct {
+	Name  *string `json:"name,omitempty" binding:"omitempty,max=30"`
+	Color *string `json:"color,omitempty" binding:"omitempty,max=16"`
+}
+
+type UpdateMindMapTagResp struct {
+	*MindMapTagDTO
+}
+
+type DeleteMindMapTagResp struct {
+	Success bool `json:"success"`
+}
+
+// 批量添加/移除标签请求
+type BatchTagMindMapsReq struct {
+	MapIDs []string `json:"mapIds" binding:"required,min=1,max=100,dive,required"`
+	TagIDs []string `json:"tagIds" binding:"required,min=1,max=20,dive,required"`
+}
+
+type BatchTagMindMapsResp struct {
+	Success       bool     `json:"success"`
+	AffectedCount int      `json:"affectedCount"` // 新增或移除的关联数量
+	FailedCount   int      `json:"failedCount"`
+	FailedMapIDs  []string `json:"failedMapIds,omitempty"`
+}
+
+// 模板DTO
+type MindMapTemplateDTO struct {
+	TemplateID string       `json:"templateId"`
+	Name       string       `json:"name"`
+	Desc       string       `json:"desc"`
+	Category   string       `json:"category"` // analysis / reading / project / study / custom
+	Layout     string       `json:"layout"`
+	BuiltIn    bool         `json:"builtIn"`
+	Root       *MindMapData `json:"root,omitempty"` // 列表中不返回；占位节点文本形如 {{提示语}}
+	CreatedAt  string       `json:"createdAt,omitempty"`
+	UpdatedAt  string       `json:"updatedAt,omitempty"`
+}
+
+type ListMindMapTemplatesResp struct {
+	List []*MindMapTemplateDTO `json:"list"` // 内置模板在前，用户模板按创建时间倒序
+}
+
+type GetMindMapTemplateResp struct {
+	*MindMapTemplateDTO
+}
+
+// 将导图保存为模板请求
+type SaveMindMapAsTemplateReq struct {
+	MapID string `json:"mapId" binding:"required"`
+	Name  string `json:"name" binding:"max=50"` // 为空时使用导图标题
+	Desc  string `json:"desc" binding:"max=500"`
+}
+
+type SaveMindMapAsTemplateResp struct {
+	*MindMapTemplateDTO
+}
+
+type DeleteMindMapTemplateResp struct {
+	Success bool `json:"success"`
+}
+
+// 基于模板创建导图请求
+type CreateMindMapFromTemplateReq struct {
+	Title    string `json:"title" binding:"max=100"` // 为空时使用根节点文本
+	Desc     string `json:"desc" binding:"max=500"`
+	Layout   string `json:"layout"`                      // 为空时使用模板布局
+	FillText string `json:"fillText" binding:"max=5000"` // 传入时由AI根据该文本填充占位节点
+}
+
+type CreateMindMapFromTemplateResp struct {
+	*MindMapDTO
+}
+
+// 导图检查问题DTO
+type MindMapLintIssueDTO struct {
+	Rule     string   `json:"rule"`     // empty_text / placeholder_text / duplicate_sibling / long_text / too_deep / too_shallow / unbalanced_branch
+	Severity string   `json:"severity"` // error / warning / info
+	Message  string   `json:"message"`
+	NodeID   string   `json:"nodeId,omitempty"`
+	Path     []string `json:"path"` // 根节点到问题节点的文本路径
+}
+
+type LintMindMapResp struct {
+	MapID         string                 `json:"mapId"`
+	Version       int64                  `json:"version"`
+	FormatScore   float64                `json:"formatScore"`
+	ContentScore  float64                `json:"contentScore"`
+	OverallScore  float64                `json:"overallScore"`
+	QualityIssues []string               `json:"qualityIssues"` // 整体质量问题
+	NodeCount     int                    `json:"nodeCount"`
+	MaxDepth      int                    `json:"maxDepth"`
+	AvgTextLength float64                `json:"avgTextLength"`
+	Issues        []*MindMapLintIssueDTO `json:"issues"` // 按节点先序排列
+}
+
+// 全文检索请求
+type SearchMindMapsReq struct {
+	Query    string `form:"q" binding:"required,max=100"`
+	Page     int    `form:"page,default=1"`
+	PageSize int    `form:"page_size,default=20"`
+}
+
+type SearchMindMapsResp struct {
+	List     []*MindMapSearchResultDTO `json:"list"`
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"page_size"`
+}
+
+// 单个导图的检索结果DTO
+type MindMapSearchResultDTO struct {
+	MapID          string                 `json:"mapId"`
+	Title          string                 `json:"title"`
+	Desc           string                 `json:"desc"`
+	Layout         string                 `json:"layout"`
+	UpdatedAt      string                 `json:"updatedAt,omitempty"`
+	Score          float64                `json:"score"`
+	TitleHighlight string                 `json:"titleHighlight,omitempty"` // 标题命中时的高亮标题
+	HitCount       int                    `json:"hitCount"`                 // 命中节点总数，hits 只返回相关度最高的若干个
+	Hits           []*MindMapSearchHitDTO `json:"hits"`
+}
+
+// 命中节点DTO，snippet 中命中部分以 <em></em> 包裹，其余内容已做HTML转义
+type MindMapSearchHitDTO struct {
+	NodeID  string   `json:"nodeId"`
+	Path    []string `json:"path"`  // 从根节点到命中节点的文本路径
+	Field   string   `json:"field"` // text / note
+	Snippet string   `json:"snippet"`
+}
+
+// 回收站列表请求
+type ListTrashedMindMapsReq struct {
+	Page     int `form:"page,default=1"`
+	PageSize int `form:"page_size,default=20"`
+}
+
+type ListTrashedMindMapsResp struct {
+	List     []*MindMapDTO `json:"list"`
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+}
+
+// 从回收站恢复请求
+type RestoreMindMapReq struct {
+	MapIDs []string `json:"mapIds" binding:"required,min=1,max=100,dive,required"`
+}
+
+type RestoreMindMapResp struct {
+	Success       bool     `json:"success"`
+	RestoredCount int      `json:"restoredCount"`
+	FailedCount   int      `json:"failedCount"`
+	FailedMapIDs  []string `json:"failedMapIds,omitempty"`
+}
+
+// 彻底删除请求（仅能删除回收站中的导图，不可恢复）
+type PurgeMindMapReq struct {
+	MapIDs []string `json:"mapIds" binding:"required,min=1,max=100,dive,required"`
+}
+
+type PurgeMindMapResp struct {
+	Success      bool     `json:"success"`
+	PurgedCount  int      `json:"purgedCount"`
+	FailedCount  int      `json:"failedCount"`
+	FailedMapIDs []string `json:"failedMapIds,omitempty"`
+}
+
+// 节点级增量更新请求 - 操作按顺序原子执行
+type PatchMindMapReq struct {
+	Version    *int64             `json:"version,omitempty"` // 客户端持有的版本号，传入时开启乐观锁校验
+	Operations []NodeOperationDTO `json:"operations" binding:"required,min=1,max=500,dive"`
+}
+
+// 节点操作DTO
+type NodeOperationDTO struct {
+	Op       string       `json:"op" binding:"required,oneof=add_child delete move reorder edit_text"`
+	NodeID   string       `json:"nodeId,omitempty"`   // 目标节点UID（delete/move/reorder/edit_text）
+	ParentID string       `json:"parentId,omitempty"` // 父节点UID（add_child/move）
+	Index    *int         `json:"index,omitempty"`    // 在兄弟节点中的位置，不传表示追加到末尾
+	Text     *string      `json:"text,omitempty"`     // 节点文本（add_child/edit_text）
+	Node     *MindMapData `json:"node,omitempty"`     // 要添加的子树（add_child，可选）
+}
+
+type PatchMindMapResp struct {
+	Success      bool     `json:"success"`
+	Version      int64    `json:"version"`
+	AddedNodeIDs []string `json:"addedNodeIds"` // 按顺序返回 add_child 新增节点的UID
+}
+
+// 复制导图请求
+type DuplicateMindMapReq struct {
+	Title string `json:"title" binding:"max=100"` // 为空时使用原标题加“副本”后缀
+}
+
+type DuplicateMindMapResp struct {
+	*MindMapDTO
+}
+
+// 提取子树为新导图请求
+type ExtractMindMapSubtreeReq struct {
+	NodeID          string `json:"nodeId" binding:"required"`
+	Title           string `json:"title" binding:"max=100"` // 为空时使用节点文本
+	ReplaceWithLink bool   `json:"replaceWithLink"`         // 是否在原导图中将子树替换为指向新导图的链接节点（需要编辑权限）
+	Version         *int64 `json:"version,omitempty"`       // 替换时客户端持有的原导图版本号，传入时开启乐观锁校验
+}
+
+type ExtractMindMapSubtreeResp struct {
+	MindMap       *MindMapDTO `json:"mindmap"`                 // 新导图
+	SourceVersion int64       `json:"sourceVersion,omitempty"` // 替换后原导图的版本号
+	LinkNodeID    string      `json:"linkNodeId,omitempty"`    // 原导图中链接节点的UID，超链接为 mindmap://{mapId}
+}
+
+// 嫁接导图请求：将另一导图整体作为子树插入
+type GraftMindMapReq struct {
+	SourceMapID  string `json:"sourceMapId" binding:"required"`
+	ParentNodeID string `json:"parentNodeId" binding:"required"`
+	Index        *int   `json:"index,omitempty" binding:"omitempty,min=0"` // 为空表示追加到末尾
+	Version      *int64 `json:"version,omitempty"`                         // 客户端持有的版本号，传入时开启乐观锁校验
+}
+
+type GraftMindMapResp struct {
+	Success    bool   `json:"success"`
+	Version    int64  `json:"version"`
+	RootNodeID string `json:"rootNodeId"` // 插入子树根节点的UID
+}
+
+// 节点链接DTO
+type MindMapNodeLinkDTO struct {
+	LinkID         string `json:"linkId"`
+	SourceMapID    string `json:"sourceMapId"`
+	SourceNodeID   string `json:"sourceNodeId"`
+	SourceMapTitle string `json:"sourceMapTitle,omitempty"` // 对当前用户不可见时为空
+	SourceNodeText string `json:"sourceNodeText,omitempty"`
+	TargetMapID    string `json:"targetMapId"`
+	TargetNodeID   string `json:"targetNodeId,omitempty"` // 为空表示指向整个导图
+	TargetMapTitle string `json:"targetMapTitle,omitempty"`
+	TargetNodeText string `json:"targetNodeText,omitempty"`
+	Type           string `json:"type"` // reference / related / depends_on / derived_from
+	Label          string `json:"label,omitempty"`
+	Broken         bool   `json:"broken"`                 // 目标已删除时为 true，链接保留但不可跳转
+	BrokenReason   string `json:"brokenReason,omitempty"` // map_deleted / node_deleted
+	BrokenAt       string `json:"brokenAt,omitempty"`
+	CreatedAt      string `json:"createdAt"`
+}
+
+// 创建节点链接请求
+type CreateMindMapNodeLinkReq struct {
+	SourceNodeID string `json:"sourceNodeId" binding:"required"`
+	TargetMapID  string `json:"targetMapId" binding:"required"`
+	TargetNodeID string `json:"targetNodeId"`                                                             // 为空表示指向整个导图
+	Type         string `json:"type" binding:"omitempty,oneof=reference related depends_on derived_from"` // 为空时为 reference
+	Label        string `json:"label" binding:"max=100"`
+}
+
+type CreateMindMapNodeLinkResp struct {
+	*MindMapNodeLinkDTO
+}
+
+// 查询节点链接（出链/反链）请求
+type ListMindMapNodeLinksReq struct {
+	NodeID string `form:"node_id"` // 为空表示整个导图
+}
+
+type ListMindMapNodeLinksResp struct {
+	List []*MindMapNodeLinkDTO `json:"list"`
+}
+
+type DeleteMindMapNodeLinkResp struct {
+	Success bool `json:"success"`
+}
+
+// 计算排版请求
+type ComputeMindMapLayoutReq struct {
+	Layout string `form:"layout"` // mindMap / logicalStructure / organizationStructure / timeline / fishbone，为空时使用导图自身的布局
+}
+
+// 排版矩形，坐标以画布左上角为原点（单位：px）
+type LayoutRectDTO struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+type LayoutPointDTO struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// 节点排版结果
+type LayoutNodeDTO struct {
+	NodeID      string        `json:"nodeId"`
+	Depth       int           `json:"depth"`
+	Box         LayoutRectDTO `json:"box"`
+	Lines       []string      `json:"lines"` // 按宽度换行后的文本
+	FontSize    float64       `json:"fontSize"`
+	Folded      bool          `json:"folded,omitempty"`      // 折叠节点的子节点不参与排版
+	HiddenCount int           `json:"hiddenCount,omitempty"` // 折叠隐藏的子孙节点数
+}
+
+// 连线排版结果
+type LayoutEdgeDTO struct {
+	FromNodeID string           `json:"fromNodeId"`
+	ToNodeID   string           `json:"toNodeId,omitempty"` // 为空表示鱼骨图主干等非父子连线
+	Points     []LayoutPointDTO `json:"points"`
+	Curved     bool             `json:"curved"` // 为 true 时 points 为三次贝塞尔曲线的4个控制点，否则为折线
+}
+
+type ComputeMindMapLayoutResp struct {
+	Version int64            `json:"version"` // 排版所基于的导图版本
+	Layout  string           `json:"layout"`  // 实际使用的布局
+	Bounds  LayoutRectDTO    `json:"bounds"`  // 画布范围（含边距）
+	Nodes   []*LayoutNodeDTO `json:"nodes"`   // 先序遍历顺序
+	Edges   []*LayoutEdgeDTO `json:"edges"`
+}
+
+// 导出请求
+type ExportMindMapReq struct {
+	Format string `form:"format" binding:"required"` // markdown / opml / freemind / svg / pdf / docx / pptx
+}
+
+// 导出结果（以文件流返回，不做JSON序列化）
+type ExportMindMapResp struct {
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Content     []byte `json:"-"`
+}
+
+// 导入请求（multipart/form-data）
+type ImportMindMapReq struct {
+	File   *multipart.FileHeader `json:"-" form:"file"`                     // 支持 .md/.opml/.mm/.xmind
+	Title  string                `form:"title" binding:"omitempty,max=100"` // 不传时使用文件中的标题
+	Desc   string                `form:"desc" binding:"max=500"`
+	Layout string                `form:"layout"`
+}
+
+type ImportMindMapResp struct {
+	*MindMapDTO
+}
+
+// 历史版本DTO
+type MindMapRevisionDTO struct {
+	MapID     string       `json:"mapId"`
+	Version   int64        `json:"version"`
+	UserID    string       `json:"userId"`
+	Title     string       `json:"title"`
+	Desc      string       `json:"desc"`
+	Layout    string       `json:"layout"`
+	Source    string       `json:"source"`
+	Root      *MindMapData `json:"root,omitempty"` // 列表中不返回
+	CreatedAt string       `json:"createdAt,omitempty"`
+}
+
+// 历史版本列表请求
+type ListMindMapRevisionsReq struct {
+	Page     int `form:"page,default=1"`
+	PageSize int `form:"page_size,default=20"`
+}
+
+type ListMindMapRevisionsResp struct {
+	List     []*MindMapRevisionDTO `json:"list"`
+	Total    int64                 `json:"total"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+}
+
+type GetMindMapRevisionResp struct {
+	*MindMapRevisionDTO
+}
+
+// 恢复历史版本请求
+type RestoreMindMapRevisionReq struct {
+	Version *int64 `json:"version,omitempty"` // 客户端持有的当前版本号，传入时开启乐观锁校验
+}
+
+type RestoreMindMapRevisionResp struct {
+	Success bool  `json:"success"`
+	Version int64 `json:"version"`
+}
+
+// 版本差异请求：比较同一导图的两个版本
+type DiffMindMapRevisionsReq struct {
+	From int64 `form:"from" binding:"min=0"` // 起始版本号，0表示当前版本
+	To   int64 `form:"to" binding:"min=0"`   // 目标版本号，0表示当前版本
+}
+
+// 版本差异请求：比较导图版本与任意节点树（如AI生成的导图）
+type DiffMindMapWithDataReq struct {
+	FromVersion int64        `json:"fromVersion" binding:"min=0"` // 起始版本号，0表示当前版本
+	Root        *MindMapData `json:"root" binding:"required"`
+}
+
+// 节点变更DTO
+type MindMapNodeChangeDTO struct {
+	Type         string   `json:"type"` // added / removed / moved / renamed / reordered
+	OldUID       string   `json:"oldUid,omitempty"`
+	NewUID       string   `json:"newUid,omitempty"`
+	OldText      string   `json:"oldText,omitempty"`
+	NewText      string   `json:"newText,omitempty"`
+	OldParentUID string   `json:"oldParentUid,omitempty"`
+	NewParentUID string   `json:"newParentUid,omitempty"`
+	OldIndex     int      `json:"oldIndex"`            // 新增节点为 -1
+	NewIndex     int      `json:"newIndex"`            // 删除节点为 -1
+	Path         []string `json:"path"`                // 根节点到该节点的文本路径，删除节点为原路径
+	MatchedBy    string   `json:"matchedBy,omitempty"` // uid / text
+	Similarity   float64  `json:"similarity,omitempty"`
+}
+
+type MindMapDiffStatsDTO struct {
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Moved     int `json:"moved"`
+	Renamed   int `json:"renamed"`
+	Reordered int `json:"reordered"`
+	Unchanged int `json:"unchanged"`
+}
+
+type DiffMindMapResp struct {
+	FromVersion int64                   `json:"fromVersion"`
+	ToVersion   int64                   `json:"toVersion,omitempty"` // 与任意数据比较时为空
+	HasChanges  bool                    `json:"hasChanges"`
+	Changes     []*MindMapNodeChangeDTO `json:"changes"`
+	Stats       MindMapDiffStatsDTO     `json:"stats"`
+}
+
+// 创建分享链接请求
+type CreateMindMapShareReq struct {
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`                                 // 过期时间（RFC3339），不传表示永久有效
+	Password  string     `json:"password,omitempty" binding:"omitempty,min=4,max=32"` // 访问密码，不传表示无需密码
+}
+
+// 分享链接DTO
+type MindMapShareDTO struct {
+	ShareID     string `json:"shareId"`
+	Token       string `json:"token"`
+	MapID       string `json:"mapId"`
+	HasPassword bool   `json:"hasPassword"`
+	Expired     bool   `json:"expired"`
+	Revoked     bool   `json:"revoked"`
+	HitCount    int64  `json:"hitCount"`
+	ExpiresAt   string `json:"expiresAt,omitempty"`
+	RevokedAt   string `json:"revokedAt,omitempty"`
+	LastHitAt   string `json:"lastHitAt,omitempty"`
+	CreatedAt   string `json:"createdAt,omitempty"`
+}
+
+type CreateMindMapShareResp struct {
+	*MindMapShareDTO
+}
+
+type ListMindMapSharesResp struct {
+	List []*MindMapShareDTO `json:"list"`
+}
+
+type RevokeMindMapShareResp struct {
+	Success bool `json:"success"`
+}
+
+// 访问分享请求
+type GetSharedMindMapReq struct {
+	Password string `header:"X-Share-Password"` // 分享设置了密码时必传，通过请求头传递避免被访问日志记录
+}
+
+// 分享导图DTO - 只读视图，不暴露所有者信息
+type SharedMindMapDTO struct {
+	MapID     string      `json:"mapId"`
+	Title     string      `json:"title"`
+	Desc      string      `json:"desc"`
+	Layout    string      `json:"layout"`
+	Root      MindMapData `json:"root"`
+	UpdatedAt string      `json:"updatedAt,omitempty"`
+}
+
+type GetSharedMindMapResp struct {
+	*SharedMindMapDTO
+}
+
+// 协作者DTO
+type MindMapCollaboratorDTO struct {
+	UserID    string `json:"userId"`
+	UserName  string `json:"userName"`
+	Avatar    string `json:"avatar"`
+	Role      string `json:"role"` // owner / editor / commenter / viewer
+	InvitedBy string `json:"invitedBy,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+type ListMindMapCollaboratorsResp struct {
+	List []*MindMapCollaboratorDTO `json:"list"` // 所有者排在首位
+}
+
+// 邀请协作者请求
+type AddMindMapCollaboratorReq struct {
+	Account     string `json:"account" binding:"required"`                            // 被邀请人手机号或邮箱
+	AccountType string `json:"account_type" binding:"required,oneof=phone email"`     // 账号类型：phone（手机号）或 email（邮箱）
+	Role        string `json:"role" binding:"required,oneof=viewer commenter editor"` // 协作角色
+}
+
+type AddMindMapCollaboratorResp struct {
+	*MindMapCollaboratorDTO
+}
+
+// 修改协作者角色请求
+type UpdateMindMapCollaboratorReq struct {
+	Role string `json:"role" binding:"required,oneof=viewer commenter editor"`
+}
+
+type UpdateMindMapCollaboratorResp struct {
+	Success bool `json:"success"`
+}
+
+type RemoveMindMapCollaboratorResp struct {
+	Success bool `json:"success"`
+}
+
+// 实时协同编辑消息类型（WebSocket，JSON文本帧）
+const (
+	// 客户端 -> 服务端
+	CollabMsgOp     = "op"     // 提交节点操作（服务端也以该类型广播他人的操作）
+	CollabMsgSelect = "select" // 更新选中节点（服务端也以该类型广播他人的选中变化）
+	CollabMsgPing   = "ping"   // 应用层心跳
+
+	// 服务端 -> 客户端
+	CollabMsgInit  = "init"  // 连接建立后的初始状态
+	CollabMsgAck   = "ack"   // 自己提交的操作已生效
+	CollabMsgReset = "reset" // 导图被整体覆盖（全量更新/恢复版本），需重新拉取
+	CollabMsgJoin  = "join"  // 成员加入
+	CollabMsgLeave = "leave" // 成员离开
+	CollabMsgPong  = "pong"
+	CollabMsgError = "error"
+)
+
+// 协同编辑客户端消息
+type CollabClientMessage struct {
+	Type        string             `json:"type"`
+	ClientSeq   int64              `json:"clientSeq,omitempty"`   // 客户端自增序号，在 ack/error 中原样带回
+	BaseVersion int64              `json:"baseVersion,omitempty"` // op：生成这批操作时本地导图的版本号
+	Operations  []NodeOperationDTO `json:"operations,omitempty"`  // op：与 PATCH 接口的操作格式一致
+	NodeID      *string            `json:"nodeId,omitempty"`      // select：选中节点UID，空字符串表示取消选中
+}
+
+// 协同编辑服务端消息
+// op/ack/reset 均携带生效后的版本号，客户端应按版本号顺序应用；ack 与他人的 op 到达顺序可能交错
+type CollabServerMessage struct {
+	Type           string             `json:"type"`
+	ClientSeq      int64              `json:"clientSeq,omitempty"`      // ack/error/pong
+	SessionID      string             `json:"sessionId,omitempty"`      // init 为自己的会话ID，其余为事件来源会话
+	UserID         string             `json:"userId,omitempty"`         // 事件来源用户
+	Role           string             `json:"role,omitempty"`           // init：自己在导图上的角色
+	Version        int64              `json:"version,omitempty"`        // init/op/ack/reset
+	MindMap        *MindMapDTO        `json:"mindMap,omitempty"`        // init
+	Members        []*CollabMemberDTO `json:"members,omitempty"`        // init：当前在线成员
+	Member         *CollabMemberDTO   `json:"member,omitempty"`         // join/leave/select
+	Operations     []NodeOperationDTO `json:"operations,omitempty"`     // op/ack：已变换到最新版本的操作
+	AddedNodeIDs   []string           `json:"addedNodeIds,omitempty"`   // ack
+	SkippedIndexes []int              `json:"skippedIndexes,omitempty"` // ack：因并发冲突被丢弃的操作下标
+	Code           int                `json:"code,omitempty"`           // error
+	Message        string             `json:"message,omitempty"`        // error
+}
+
+// 协同编辑在线成员DTO
+type CollabMemberDTO struct {
+	SessionID      string `json:"sessionId"`
+	UserID         string `json:"userId"`
+	UserName       string `json:"userName"`
+	Avatar         string `json:"avatar"`
+	Role           string `json:"role"`
+	SelectedNodeID string `json:"selectedNodeId,omitempty"`
+}
@@ -31,6 +31,7 @@ func (h *Handler) SendMessage(ctx context.Context, req *def.ProcessUserMessageRe
 	resp = &def.ProcessUserMessageResponse{
 		Content:    aiMsg.Content,
 		NewMapJson: aiMsg.NewMapJson,
+		ProposalID: aiMsg.ProposalID,
 		Success:    true,
 	}
 
@@ -62,14 +63,11 @@ func (h *Handler) SendMessageStream(ctx context.Context, req *def.ProcessUserMes
 }
 
 func (h *Handler) sendSSEChunk(writer *outputPort.GinSSEWriter, chunk types.StreamChunk) error {
-	err := writer.WriteChunk(chunk)
-
-	return err
+	return writer.WriteEvent(chunk.Event, caster.CastStreamChunk2Resp(chunk))
 }
 
 func (h *Handler) sendSSEError(writer *outputPort.GinSSEWriter, err error) {
-	fmt.Fprintf(writer.Ctx.Writer, "data: %s\n\n", err.Error())
-	writer.Ctx.Writer.Flush()
+	_ = writer.WriteEvent(types.StreamEventError, &def.StreamEventData{Message: err.Error()})
 }
 
 func (h *Handler) SaveNewConversation(ctx context.Context, req *def.SaveNewConversationRequest) (*def.SaveNewConversationResponse, error) {
@@ -128,12 +126,35 @@ func (h *Handler) GetConversation(ctx context.Context, req *def.GetConversationR
 	}
 
 	resp := &def.GetConversationResponse{
-		Success:        true,
-		Title:          conversation.Title,
-		Messages:       conversation.Messages,
-		ConversationID: conversation.ConversationID,
+		Success:          true,
+		Title:            conversation.Title,
+		Messages:         conversation.Messages,
+		CurrentMessageID: conversation.CurrentMessageID,
+		ConversationID:   conversation.ConversationID,
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) SwitchMessageBranch(ctx context.Context, req *def.SwitchMessageBranchRequest) (resp *def.SwitchMessageBranchResponse, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.switch_message_branch", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.switch_message_branch", req, resp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, resp, err)
+	}()
+
+	params := caster.CastSwitchMessageBranchReq2Params(req)
+
+	conversation, err := h.AiChatService.SwitchMessageBranch(ctx, params)
+	if err != nil {
+		return nil, err
 	}
 
+	resp = &def.SwitchMessageBranchResponse{
+		CurrentMessageID: conversation.CurrentMessageID,
+		Success:          true,
+	}
 	return resp, nil
 }
 
@@ -237,3 +258,66 @@ func (h *Handler) TriggerQualityAssessment(ctx context.Context, req *def.Trigger
 
 	return resp, nil
 }
+
+// GetMapProposalList 获取某会话的导图修改建议
+func (h *Handler) GetMapProposalList(ctx context.Context, req *def.GetMapProposalListRequest) (resp *def.GetMapProposalListResponse, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.get_map_proposal_list", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.get_map_proposal_list", req, resp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, resp, err)
+	}()
+
+	proposals, err := h.AiChatService.ListMapProposals(ctx, &types.ListMapProposalsParams{ConversationID: req.ConversationID})
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &def.GetMapProposalListResponse{
+		List:    caster.CastMapProposalDOs2Resp(proposals),
+		Success: true,
+	}
+	return resp, nil
+}
+
+// AcceptMapProposal 采纳导图修改建议
+func (h *Handler) AcceptMapProposal(ctx context.Context, req *def.ResolveMapProposalRequest) (resp *def.ResolveMapProposalResponse, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.accept_map_proposal", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.accept_map_proposal", req, resp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, resp, err)
+	}()
+
+	proposal, err := h.AiChatService.AcceptMapProposal(ctx, caster.CastResolveMapProposalReq2Params(req))
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &def.ResolveMapProposalResponse{
+		Proposal: caster.CastMapProposalDO2Resp(proposal),
+		Success:  true,
+	}
+	return resp, nil
+}
+
+// RejectMapProposal 拒绝导图修改建议
+func (h *Handler) RejectMapProposal(ctx context.Context, req *def.ResolveMapProposalRequest) (resp *def.ResolveMapProposalResponse, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.reject_map_proposal", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.reject_map_proposal", req, resp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, resp, err)
+	}()
+
+	proposal, err := h.AiChatService.RejectMapProposal(ctx, caster.CastResolveMapProposalReq2Params(req))
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &def.ResolveMapProposalResponse{
+		Proposal: caster.CastMapProposalDO2Resp(proposal),
+		Success:  true,
+	}
+	return resp, nil
+}
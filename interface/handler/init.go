@@ -33,6 +33,56 @@ type IHandler interface {
 	UpdateMindMap(ctx context.Context, mapID string, req *def.UpdateMindMapReq) (rsp *def.UpdateMindMapResp, err error)
 	DeleteMindMap(ctx context.Context, mapID string) (rsp *def.DeleteMindMapResp, err error)
 	BatchDeleteMindMap(ctx context.Context, req *def.BatchDeleteMindMapReq) (rsp *def.BatchDeleteMindMapResp, err error)
+	DuplicateMindMap(ctx context.Context, mapID string, req *def.DuplicateMindMapReq) (rsp *def.DuplicateMindMapResp, err error)
+	ExtractMindMapSubtree(ctx context.Context, mapID string, req *def.ExtractMindMapSubtreeReq) (rsp *def.ExtractMindMapSubtreeResp, err error)
+	GraftMindMap(ctx context.Context, mapID string, req *def.GraftMindMapReq) (rsp *def.GraftMindMapResp, err error)
+	ListMindMapFolders(ctx context.Context) (rsp *def.ListMindMapFoldersResp, err error)
+	CreateMindMapFolder(ctx context.Context, req *def.CreateMindMapFolderReq) (rsp *def.CreateMindMapFolderResp, err error)
+	UpdateMindMapFolder(ctx context.Context, folderID string, req *def.UpdateMindMapFolderReq) (rsp *def.UpdateMindMapFolderResp, err error)
+	DeleteMindMapFolder(ctx context.Context, folderID string) (rsp *def.DeleteMindMapFolderResp, err error)
+	MoveMindMaps(ctx context.Context, req *def.MoveMindMapsReq) (rsp *def.MoveMindMapsResp, err error)
+	ListMindMapTags(ctx context.Context) (rsp *def.ListMindMapTagsResp, err error)
+	CreateMindMapTag(ctx context.Context, req *def.CreateMindMapTagReq) (rsp *def.CreateMindMapTagResp, err error)
+	UpdateMindMapTag(ctx context.Context, tagID string, req *def.UpdateMindMapTagReq) (rsp *def.UpdateMindMapTagResp, err error)
+	DeleteMindMapTag(ctx context.Context, tagID string) (rsp *def.DeleteMindMapTagResp, err error)
+	TagMindMaps(ctx context.Context, req *def.BatchTagMindMapsReq) (rsp *def.BatchTagMindMapsResp, err error)
+	UntagMindMaps(ctx context.Context, req *def.BatchTagMindMapsReq) (rsp *def.BatchTagMindMapsResp, err error)
+	ListMindMapTemplates(ctx context.Context) (rsp *def.ListMindMapTemplatesResp, err error)
+	GetMindMapTemplate(ctx context.Context, templateID string) (rsp *def.GetMindMapTemplateResp, err error)
+	SaveMindMapAsTemplate(ctx context.Context, req *def.SaveMindMapAsTemplateReq) (rsp *def.SaveMindMapAsTemplateResp, err error)
+	DeleteMindMapTemplate(ctx context.Context, templateID string) (rsp *def.DeleteMindMapTemplateResp, err error)
+	CreateMindMapFromTemplate(ctx context.Context, templateID string, req *def.CreateMindMapFromTemplateReq) (rsp *def.CreateMindMapFromTemplateResp, err error)
+	CreateMindMapNodeLink(ctx context.Context, mapID string, req *def.CreateMindMapNodeLinkReq) (rsp *def.CreateMindMapNodeLinkResp, err error)
+	ListMindMapNodeLinks(ctx context.Context, mapID string, req *def.ListMindMapNodeLinksReq) (rsp *def.ListMindMapNodeLinksResp, err error)
+	DeleteMindMapNodeLink(ctx context.Context, mapID, linkID string) (rsp *def.DeleteMindMapNodeLinkResp, err error)
+	ListMindMapBacklinks(ctx context.Context, mapID string, req *def.ListMindMapNodeLinksReq) (rsp *def.ListMindMapNodeLinksResp, err error)
+	LintMindMap(ctx context.Context, mapID string) (rsp *def.LintMindMapResp, err error)
+	SearchMindMaps(ctx context.Context, req *def.SearchMindMapsReq) (rsp *def.SearchMindMapsResp, err error)
+	ListTrashedMindMaps(ctx context.Context, req *def.ListTrashedMindMapsReq) (rsp *def.ListTrashedMindMapsResp, err error)
+	RestoreMindMap(ctx context.Context, req *def.RestoreMindMapReq) (rsp *def.RestoreMindMapResp, err error)
+	PurgeMindMap(ctx context.Context, req *def.PurgeMindMapReq) (rsp *def.PurgeMindMapResp, err error)
+	PatchMindMap(ctx context.Context, mapID string, req *def.PatchMindMapReq) (rsp *def.PatchMindMapResp, err error)
+	ExportMindMap(ctx context.Context, mapID string, req *def.ExportMindMapReq) (rsp *def.ExportMindMapResp, err error)
+	ComputeMindMapLayout(ctx context.Context, mapID string, req *def.ComputeMindMapLayoutReq) (rsp *def.ComputeMindMapLayoutResp, err error)
+	ImportMindMap(ctx context.Context, req *def.ImportMindMapReq) (rsp *def.ImportMindMapResp, err error)
+	ListMindMapRevisions(ctx context.Context, mapID string, req *def.ListMindMapRevisionsReq) (rsp *def.ListMindMapRevisionsResp, err error)
+	GetMindMapRevision(ctx context.Context, mapID string, version int64) (rsp *def.GetMindMapRevisionResp, err error)
+	RestoreMindMapRevision(ctx context.Context, mapID string, version int64, req *def.RestoreMindMapRevisionReq) (rsp *def.RestoreMindMapRevisionResp, err error)
+	DiffMindMapRevisions(ctx context.Context, mapID string, req *def.DiffMindMapRevisionsReq) (rsp *def.DiffMindMapResp, err error)
+	DiffMindMapWithData(ctx context.Context, mapID string, req *def.DiffMindMapWithDataReq) (rsp *def.DiffMindMapResp, err error)
+	CreateMindMapShare(ctx context.Context, mapID string, req *def.CreateMindMapShareReq) (rsp *def.CreateMindMapShareResp, err error)
+	ListMindMapShares(ctx context.Context, mapID string) (rsp *def.ListMindMapSharesResp, err error)
+	RevokeMindMapShare(ctx context.Context, mapID, shareID string) (rsp *def.RevokeMindMapShareResp, err error)
+	GetSharedMindMap(ctx context.Context, token string, req *def.GetSharedMindMapReq) (rsp *def.GetSharedMindMapResp, err error)
+	ListMindMapCollaborators(ctx context.Context, mapID string) (rsp *def.ListMindMapCollaboratorsResp, err error)
+	AddMindMapCollaborator(ctx context.Context, mapID string, req *def.AddMindMapCollaboratorReq) (rsp *def.AddMindMapCollaboratorResp, err error)
+	UpdateMindMapCollaborator(ctx context.Context, mapID, userID string, req *def.UpdateMindMapCollaboratorReq) (rsp *def.UpdateMindMapCollaboratorResp, err error)
+	RemoveMindMapCollaborator(ctx context.Context, mapID, userID string) (rsp *def.RemoveMindMapCollaboratorResp, err error)
+	JoinMindMapCollab(ctx context.Context, mapID string) (rsp *def.CollabServerMessage, err error)
+	LeaveMindMapCollab(ctx context.Context, mapID, sessionID string) (err error)
+	UpdateMindMapCollabPresence(ctx context.Context, mapID, sessionID string, nodeID *string) (err error)
+	CommitMindMapCollabOps(ctx context.Context, mapID, sessionID string, req *def.CollabClientMessage) (rsp *def.CollabServerMessage, err error)
+	SubscribeMindMapCollabEvents(ctx context.Context, fn func(mapID string, msg *def.CollabServerMessage))
 
 	// COS: OSS凭证相关接口
 	GetOSSCredentials(ctx context.Context, req *def.GetOSSCredentialsReq) (rsp *def.GetOSSCredentialsResp, err error)
@@ -44,6 +94,7 @@ type IHandler interface {
 	GetConversationList(ctx context.Context, req *def.GetConversationListRequest) (*def.GetConversationListResponse, error)
 	DelConversation(ctx context.Context, req *def.DelConversationRequest) (*def.DelConversationResponse, error)
 	GetConversation(ctx context.Context, req *def.GetConversationRequest) (*def.GetConversationResponse, error)
+	SwitchMessageBranch(ctx context.Context, req *def.SwitchMessageBranchRequest) (*def.SwitchMessageBranchResponse, error)
 	UpdateConversationTitle(ctx context.Context, req *def.UpdateConversationTitleRequest) (*def.UpdateConversationTitleResponse, error)
 	GenerateMindMap(ctx context.Context, req *def.GenerateMindMapRequest) (*def.GenerateMindMapResponse, error)
 
@@ -52,6 +103,11 @@ type IHandler interface {
 	ExportQualityData(ctx context.Context, req *def.ExportQualityDataRequest) (*def.ExportQualityDataResponse, error)
 	TriggerQualityAssessment(ctx context.Context, req *def.TriggerQualityAssessmentRequest) (*def.TriggerQualityAssessmentResponse, error)
 
+	// 导图修改建议
+	GetMapProposalList(ctx context.Context, req *def.GetMapProposalListRequest) (*def.GetMapProposalListResponse, error)
+	AcceptMapProposal(ctx context.Context, req *def.ResolveMapProposalRequest) (*def.ResolveMapProposalResponse, error)
+	RejectMapProposal(ctx context.Context, req *def.ResolveMapProposalRequest) (*def.ResolveMapProposalResponse, error)
+
 	// Generation: 批量生成相关接口
 	GenerateMindMapPro(ctx context.Context, req *def.GenerateMindMapProReq) (rsp *def.GenerateMindMapProResp, err error)
 	GetGenerationBatch(ctx context.Context, batchID string) (rsp *def.GetGenerationBatchResp, err error)
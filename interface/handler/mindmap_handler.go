@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 
+	"forge/biz/types"
 	"forge/constant"
 	"forge/interface/caster"
 	"forge/interface/def"
@@ -94,7 +95,7 @@ func (h *Handler) UpdateMindMap(ctx context.Context, mapID string, req *def.Upda
 	params := caster.CastUpdateMindMapReq2Params(req)
 
 	// 调用服务层更新思维导图
-	err = h.MindMapService.UpdateMindMap(ctx, mapID, params)
+	version, err := h.MindMapService.UpdateMindMap(ctx, mapID, params)
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +103,97 @@ func (h *Handler) UpdateMindMap(ctx context.Context, mapID string, req *def.Upda
 	// 组装响应
 	rsp = &def.UpdateMindMapResp{
 		Success: true,
+		Version: version,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) PatchMindMap(ctx context.Context, mapID string, req *def.PatchMindMapReq) (rsp *def.PatchMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.patch_mindmap", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.patch_mindmap", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastPatchMindMapReq2Params(req)
+
+	// 调用服务层执行节点操作
+	version, addedNodeIDs, err := h.MindMapService.PatchMindMap(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.PatchMindMapResp{
+		Success:      true,
+		Version:      version,
+		AddedNodeIDs: addedNodeIDs,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ComputeMindMapLayout(ctx context.Context, mapID string, req *def.ComputeMindMapLayoutReq) (rsp *def.ComputeMindMapLayoutResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.compute_mindmap_layout", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.compute_mindmap_layout", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	result, version, err := h.MindMapService.ComputeMindMapLayout(ctx, mapID, req.Layout)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = caster.CastLayoutResult2DTO(result, version)
+	return rsp, nil
+}
+
+func (h *Handler) ExportMindMap(ctx context.Context, mapID string, req *def.ExportMindMapReq) (rsp *def.ExportMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.export_mindmap", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.export_mindmap", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	// 调用服务层导出思维导图
+	file, err := h.MindMapService.ExportMindMap(ctx, mapID, req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.ExportMindMapResp{
+		FileName:    file.FileName,
+		ContentType: file.ContentType,
+		Content:     file.Content,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ImportMindMap(ctx context.Context, req *def.ImportMindMapReq) (rsp *def.ImportMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.import_mindmap", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.import_mindmap", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastImportMindMapReq2Params(req)
+
+	// 调用服务层导入思维导图
+	mindmap, err := h.MindMapService.ImportMindMap(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.ImportMindMapResp{
+		MindMapDTO: caster.CastMindMapDO2DTO(mindmap),
 	}
 	return rsp, nil
 }
@@ -150,3 +242,947 @@ func (h *Handler) BatchDeleteMindMap(ctx context.Context, req *def.BatchDeleteMi
 	}
 	return rsp, nil
 }
+
+func (h *Handler) DuplicateMindMap(ctx context.Context, mapID string, req *def.DuplicateMindMapReq) (rsp *def.DuplicateMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.duplicate_mindmap", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.duplicate_mindmap", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastDuplicateMindMapReq2Params(req)
+
+	mindmap, err := h.MindMapService.DuplicateMindMap(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.DuplicateMindMapResp{
+		MindMapDTO: caster.CastMindMapDO2DTO(mindmap),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ExtractMindMapSubtree(ctx context.Context, mapID string, req *def.ExtractMindMapSubtreeReq) (rsp *def.ExtractMindMapSubtreeResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.extract_mindmap_subtree", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.extract_mindmap_subtree", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastExtractMindMapSubtreeReq2Params(req)
+
+	result, err := h.MindMapService.ExtractMindMapSubtree(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.ExtractMindMapSubtreeResp{
+		MindMap:       caster.CastMindMapDO2DTO(result.MindMap),
+		SourceVersion: result.SourceVersion,
+		LinkNodeID:    result.LinkNodeID,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) GraftMindMap(ctx context.Context, mapID string, req *def.GraftMindMapReq) (rsp *def.GraftMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.graft_mindmap", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.graft_mindmap", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastGraftMindMapReq2Params(req)
+
+	newVersion, rootNodeID, err := h.MindMapService.GraftMindMap(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.GraftMindMapResp{
+		Success:    true,
+		Version:    newVersion,
+		RootNodeID: rootNodeID,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) CreateMindMapNodeLink(ctx context.Context, mapID string, req *def.CreateMindMapNodeLinkReq) (rsp *def.CreateMindMapNodeLinkResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.create_mindmap_node_link", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.create_mindmap_node_link", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastCreateMindMapNodeLinkReq2Params(req)
+
+	link, err := h.MindMapService.CreateMindMapNodeLink(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.CreateMindMapNodeLinkResp{
+		MindMapNodeLinkDTO: caster.CastMindMapNodeLinkDO2DTO(link),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ListMindMapNodeLinks(ctx context.Context, mapID string, req *def.ListMindMapNodeLinksReq) (rsp *def.ListMindMapNodeLinksResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.list_mindmap_node_links", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.list_mindmap_node_links", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	links, err := h.MindMapService.ListMindMapNodeLinks(ctx, mapID, req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.ListMindMapNodeLinksResp{
+		List: caster.CastMindMapNodeLinkDOs2DTOs(links),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) DeleteMindMapNodeLink(ctx context.Context, mapID, linkID string) (rsp *def.DeleteMindMapNodeLinkResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.delete_mindmap_node_link", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.delete_mindmap_node_link", map[string]interface{}{"mapID": mapID, "linkID": linkID}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "linkID": linkID}, rsp, err)
+	}()
+
+	if err = h.MindMapService.DeleteMindMapNodeLink(ctx, mapID, linkID); err != nil {
+		return nil, err
+	}
+
+	rsp = &def.DeleteMindMapNodeLinkResp{
+		Success: true,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ListMindMapBacklinks(ctx context.Context, mapID string, req *def.ListMindMapNodeLinksReq) (rsp *def.ListMindMapNodeLinksResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.list_mindmap_backlinks", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.list_mindmap_backlinks", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	links, err := h.MindMapService.ListMindMapBacklinks(ctx, mapID, req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.ListMindMapNodeLinksResp{
+		List: caster.CastMindMapNodeLinkDOs2DTOs(links),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) LintMindMap(ctx context.Context, mapID string) (rsp *def.LintMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.lint_mindmap", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.lint_mindmap", map[string]interface{}{"mapID": mapID}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID}, rsp, err)
+	}()
+
+	report, err := h.MindMapService.LintMindMap(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = caster.CastMindMapLintReportDO2DTO(report)
+	return rsp, nil
+}
+
+func (h *Handler) ListMindMapFolders(ctx context.Context) (rsp *def.ListMindMapFoldersResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.list_mindmap_folders", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.list_mindmap_folders", nil, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, nil, rsp, err)
+	}()
+
+	folders, err := h.MindMapService.ListMindMapFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.ListMindMapFoldersResp{
+		List: caster.CastMindMapFolderDOs2DTOs(folders),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) CreateMindMapFolder(ctx context.Context, req *def.CreateMindMapFolderReq) (rsp *def.CreateMindMapFolderResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.create_mindmap_folder", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.create_mindmap_folder", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastCreateMindMapFolderReq2Params(req)
+
+	folder, err := h.MindMapService.CreateMindMapFolder(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.CreateMindMapFolderResp{
+		MindMapFolderDTO: caster.CastMindMapFolderDO2DTO(folder),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) UpdateMindMapFolder(ctx context.Context, folderID string, req *def.UpdateMindMapFolderReq) (rsp *def.UpdateMindMapFolderResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.update_mindmap_folder", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.update_mindmap_folder", map[string]interface{}{"folderID": folderID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"folderID": folderID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastUpdateMindMapFolderReq2Params(req)
+
+	folder, err := h.MindMapService.UpdateMindMapFolder(ctx, folderID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.UpdateMindMapFolderResp{
+		MindMapFolderDTO: caster.CastMindMapFolderDO2DTO(folder),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) DeleteMindMapFolder(ctx context.Context, folderID string) (rsp *def.DeleteMindMapFolderResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.delete_mindmap_folder", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.delete_mindmap_folder", folderID, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, folderID, rsp, err)
+	}()
+
+	movedCount, err := h.MindMapService.DeleteMindMapFolder(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.DeleteMindMapFolderResp{
+		Success:    true,
+		MovedCount: movedCount,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) MoveMindMaps(ctx context.Context, req *def.MoveMindMapsReq) (rsp *def.MoveMindMapsResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.move_mindmaps", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.move_mindmaps", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	movedCount, failedMapIDs, err := h.MindMapService.MoveMindMapsToFolder(ctx, req.MapIDs, req.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.MoveMindMapsResp{
+		Success:      len(failedMapIDs) == 0,
+		MovedCount:   movedCount,
+		FailedCount:  len(failedMapIDs),
+		FailedMapIDs: failedMapIDs,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ListMindMapTags(ctx context.Context) (rsp *def.ListMindMapTagsResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.list_mindmap_tags", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.list_mindmap_tags", nil, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, nil, rsp, err)
+	}()
+
+	tags, err := h.MindMapService.ListMindMapTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.ListMindMapTagsResp{
+		List: caster.CastMindMapTagDOs2DTOs(tags),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) CreateMindMapTag(ctx context.Context, req *def.CreateMindMapTagReq) (rsp *def.CreateMindMapTagResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.create_mindmap_tag", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.create_mindmap_tag", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastCreateMindMapTagReq2Params(req)
+
+	tag, err := h.MindMapService.CreateMindMapTag(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.CreateMindMapTagResp{
+		MindMapTagDTO: caster.CastMindMapTagDO2DTO(tag),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) UpdateMindMapTag(ctx context.Context, tagID string, req *def.UpdateMindMapTagReq) (rsp *def.UpdateMindMapTagResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.update_mindmap_tag", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.update_mindmap_tag", map[string]interface{}{"tagID": tagID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"tagID": tagID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastUpdateMindMapTagReq2Params(req)
+
+	tag, err := h.MindMapService.UpdateMindMapTag(ctx, tagID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.UpdateMindMapTagResp{
+		MindMapTagDTO: caster.CastMindMapTagDO2DTO(tag),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) DeleteMindMapTag(ctx context.Context, tagID string) (rsp *def.DeleteMindMapTagResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.delete_mindmap_tag", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.delete_mindmap_tag", tagID, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, tagID, rsp, err)
+	}()
+
+	if err = h.MindMapService.DeleteMindMapTag(ctx, tagID); err != nil {
+		return nil, err
+	}
+
+	rsp = &def.DeleteMindMapTagResp{
+		Success: true,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) TagMindMaps(ctx context.Context, req *def.BatchTagMindMapsReq) (rsp *def.BatchTagMindMapsResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.tag_mindmaps", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.tag_mindmaps", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	affectedCount, failedMapIDs, err := h.MindMapService.TagMindMaps(ctx, req.MapIDs, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.BatchTagMindMapsResp{
+		Success:       len(failedMapIDs) == 0,
+		AffectedCount: affectedCount,
+		FailedCount:   len(failedMapIDs),
+		FailedMapIDs:  failedMapIDs,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) UntagMindMaps(ctx context.Context, req *def.BatchTagMindMapsReq) (rsp *def.BatchTagMindMapsResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.untag_mindmaps", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.untag_mindmaps", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	affectedCount, failedMapIDs, err := h.MindMapService.UntagMindMaps(ctx, req.MapIDs, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.BatchTagMindMapsResp{
+		Success:       len(failedMapIDs) == 0,
+		AffectedCount: affectedCount,
+		FailedCount:   len(failedMapIDs),
+		FailedMapIDs:  failedMapIDs,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ListMindMapTemplates(ctx context.Context) (rsp *def.ListMindMapTemplatesResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.list_mindmap_templates", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.list_mindmap_templates", nil, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, nil, rsp, err)
+	}()
+
+	templates, err := h.MindMapService.ListMindMapTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.ListMindMapTemplatesResp{
+		List: caster.CastMindMapTemplateDOs2SummaryDTOs(templates),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) GetMindMapTemplate(ctx context.Context, templateID string) (rsp *def.GetMindMapTemplateResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.get_mindmap_template", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.get_mindmap_template", templateID, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, templateID, rsp, err)
+	}()
+
+	template, err := h.MindMapService.GetMindMapTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.GetMindMapTemplateResp{
+		MindMapTemplateDTO: caster.CastMindMapTemplateDO2DTO(template),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) SaveMindMapAsTemplate(ctx context.Context, req *def.SaveMindMapAsTemplateReq) (rsp *def.SaveMindMapAsTemplateResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.save_mindmap_as_template", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.save_mindmap_as_template", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastSaveMindMapAsTemplateReq2Params(req)
+
+	template, err := h.MindMapService.SaveMindMapAsTemplate(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.SaveMindMapAsTemplateResp{
+		MindMapTemplateDTO: caster.CastMindMapTemplateDO2DTO(template),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) DeleteMindMapTemplate(ctx context.Context, templateID string) (rsp *def.DeleteMindMapTemplateResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.delete_mindmap_template", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.delete_mindmap_template", templateID, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, templateID, rsp, err)
+	}()
+
+	if err = h.MindMapService.DeleteMindMapTemplate(ctx, templateID); err != nil {
+		return nil, err
+	}
+
+	rsp = &def.DeleteMindMapTemplateResp{
+		Success: true,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) CreateMindMapFromTemplate(ctx context.Context, templateID string, req *def.CreateMindMapFromTemplateReq) (rsp *def.CreateMindMapFromTemplateResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.create_mindmap_from_template", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.create_mindmap_from_template", map[string]interface{}{"templateID": templateID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"templateID": templateID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastCreateMindMapFromTemplateReq2Params(req)
+
+	mindmap, err := h.MindMapService.CreateMindMapFromTemplate(ctx, templateID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &def.CreateMindMapFromTemplateResp{
+		MindMapDTO: caster.CastMindMapDO2DTO(mindmap),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) SearchMindMaps(ctx context.Context, req *def.SearchMindMapsReq) (rsp *def.SearchMindMapsResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.search_mindmaps", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.search_mindmaps", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastSearchMindMapsReq2Params(req)
+
+	// 调用服务层全文检索
+	results, total, err := h.MindMapService.SearchMindMaps(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.SearchMindMapsResp{
+		List:     caster.CastMindMapSearchResults2DTOs(results),
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ListTrashedMindMaps(ctx context.Context, req *def.ListTrashedMindMapsReq) (rsp *def.ListTrashedMindMapsResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.list_trashed_mindmaps", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.list_trashed_mindmaps", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastListTrashedMindMapsReq2Params(req)
+
+	// 调用服务层获取回收站列表
+	mindmaps, total, err := h.MindMapService.ListTrashedMindMaps(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.ListTrashedMindMapsResp{
+		List:     caster.CastMindMapDOs2DTOs(mindmaps),
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) RestoreMindMap(ctx context.Context, req *def.RestoreMindMapReq) (rsp *def.RestoreMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.restore_mindmap", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.restore_mindmap", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	// 调用服务层从回收站恢复
+	restoredCount, failedMapIDs, err := h.MindMapService.RestoreMindMaps(ctx, req.MapIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.RestoreMindMapResp{
+		Success:       len(failedMapIDs) == 0 && restoredCount > 0,
+		RestoredCount: restoredCount,
+		FailedCount:   len(failedMapIDs),
+		FailedMapIDs:  failedMapIDs,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) PurgeMindMap(ctx context.Context, req *def.PurgeMindMapReq) (rsp *def.PurgeMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.purge_mindmap", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.purge_mindmap", req, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, req, rsp, err)
+	}()
+
+	// 调用服务层彻底删除
+	purgedCount, failedMapIDs, err := h.MindMapService.PurgeMindMaps(ctx, req.MapIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.PurgeMindMapResp{
+		Success:      len(failedMapIDs) == 0 && purgedCount > 0,
+		PurgedCount:  purgedCount,
+		FailedCount:  len(failedMapIDs),
+		FailedMapIDs: failedMapIDs,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ListMindMapRevisions(ctx context.Context, mapID string, req *def.ListMindMapRevisionsReq) (rsp *def.ListMindMapRevisionsResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.list_mindmap_revisions", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.list_mindmap_revisions", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastListMindMapRevisionsReq2Params(req)
+
+	// 调用服务层获取历史版本列表
+	revisions, total, err := h.MindMapService.ListMindMapRevisions(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.ListMindMapRevisionsResp{
+		List:     caster.CastMindMapRevisionDOs2SummaryDTOs(revisions),
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) GetMindMapRevision(ctx context.Context, mapID string, version int64) (rsp *def.GetMindMapRevisionResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.get_mindmap_revision", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.get_mindmap_revision", map[string]interface{}{"mapID": mapID, "version": version}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "version": version}, rsp, err)
+	}()
+
+	// 调用服务层获取历史版本
+	revision, err := h.MindMapService.GetMindMapRevision(ctx, mapID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.GetMindMapRevisionResp{
+		MindMapRevisionDTO: caster.CastMindMapRevisionDO2DTO(revision),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) RestoreMindMapRevision(ctx context.Context, mapID string, version int64, req *def.RestoreMindMapRevisionReq) (rsp *def.RestoreMindMapRevisionResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.restore_mindmap_revision", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.restore_mindmap_revision", map[string]interface{}{"mapID": mapID, "version": version, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "version": version, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastRestoreMindMapRevisionReq2Params(version, req)
+
+	// 调用服务层恢复历史版本
+	newVersion, err := h.MindMapService.RestoreMindMapRevision(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.RestoreMindMapRevisionResp{
+		Success: true,
+		Version: newVersion,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) DiffMindMapRevisions(ctx context.Context, mapID string, req *def.DiffMindMapRevisionsReq) (rsp *def.DiffMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.diff_mindmap_revisions", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.diff_mindmap_revisions", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	diff, err := h.MindMapService.DiffMindMapRevisions(ctx, mapID, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = caster.CastMindMapDiffDO2DTO(diff)
+	return rsp, nil
+}
+
+func (h *Handler) DiffMindMapWithData(ctx context.Context, mapID string, req *def.DiffMindMapWithDataReq) (rsp *def.DiffMindMapResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.diff_mindmap_with_data", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.diff_mindmap_with_data", map[string]interface{}{"mapID": mapID, "fromVersion": req.FromVersion}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "fromVersion": req.FromVersion}, rsp, err)
+	}()
+
+	// DTO -> 领域对象转换
+	data := caster.CastMindMapDataDTO2DO(*req.Root)
+
+	diff, err := h.MindMapService.DiffMindMapWithData(ctx, mapID, req.FromVersion, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = caster.CastMindMapDiffDO2DTO(diff)
+	return rsp, nil
+}
+
+func (h *Handler) CreateMindMapShare(ctx context.Context, mapID string, req *def.CreateMindMapShareReq) (rsp *def.CreateMindMapShareResp, err error) {
+	// 链路追踪（不记录访问密码）
+	ctx, sp := loop.GetNewSpan(ctx, "handler.create_mindmap_share", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.create_mindmap_share", map[string]interface{}{"mapID": mapID, "expiresAt": req.ExpiresAt}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "expiresAt": req.ExpiresAt}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastCreateMindMapShareReq2Params(req)
+
+	// 调用服务层创建分享链接
+	share, err := h.MindMapService.CreateMindMapShare(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.CreateMindMapShareResp{
+		MindMapShareDTO: caster.CastMindMapShareDO2DTO(share),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ListMindMapShares(ctx context.Context, mapID string) (rsp *def.ListMindMapSharesResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.list_mindmap_shares", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.list_mindmap_shares", mapID, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, mapID, rsp, err)
+	}()
+
+	// 调用服务层获取分享链接列表
+	shares, err := h.MindMapService.ListMindMapShares(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.ListMindMapSharesResp{
+		List: caster.CastMindMapShareDOs2DTOs(shares),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) RevokeMindMapShare(ctx context.Context, mapID, shareID string) (rsp *def.RevokeMindMapShareResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.revoke_mindmap_share", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.revoke_mindmap_share", map[string]interface{}{"mapID": mapID, "shareID": shareID}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "shareID": shareID}, rsp, err)
+	}()
+
+	// 调用服务层撤销分享链接
+	if err = h.MindMapService.RevokeMindMapShare(ctx, mapID, shareID); err != nil {
+		return nil, err
+	}
+
+	rsp = &def.RevokeMindMapShareResp{
+		Success: true,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) GetSharedMindMap(ctx context.Context, token string, req *def.GetSharedMindMapReq) (rsp *def.GetSharedMindMapResp, err error) {
+	// 链路追踪（不记录访问密码）
+	ctx, sp := loop.GetNewSpan(ctx, "handler.get_shared_mindmap", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.get_shared_mindmap", token, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, token, rsp, err)
+	}()
+
+	// 调用服务层通过令牌读取导图
+	mindMap, err := h.MindMapService.GetSharedMindMap(ctx, token, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.GetSharedMindMapResp{
+		SharedMindMapDTO: caster.CastMindMapDO2SharedDTO(mindMap),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) ListMindMapCollaborators(ctx context.Context, mapID string) (rsp *def.ListMindMapCollaboratorsResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.list_mindmap_collaborators", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.list_mindmap_collaborators", mapID, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, mapID, rsp, err)
+	}()
+
+	// 调用服务层获取协作者列表
+	collaborators, err := h.MindMapService.ListMindMapCollaborators(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.ListMindMapCollaboratorsResp{
+		List: caster.CastMindMapCollaboratorDOs2DTOs(collaborators),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) AddMindMapCollaborator(ctx context.Context, mapID string, req *def.AddMindMapCollaboratorReq) (rsp *def.AddMindMapCollaboratorResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.add_mindmap_collaborator", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.add_mindmap_collaborator", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+	}()
+
+	// DTO -> Service 层参数转换
+	params := caster.CastAddMindMapCollaboratorReq2Params(req)
+
+	// 调用服务层邀请协作者
+	collaborator, err := h.MindMapService.AddMindMapCollaborator(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装响应
+	rsp = &def.AddMindMapCollaboratorResp{
+		MindMapCollaboratorDTO: caster.CastMindMapCollaboratorDO2DTO(collaborator),
+	}
+	return rsp, nil
+}
+
+func (h *Handler) UpdateMindMapCollaborator(ctx context.Context, mapID, userID string, req *def.UpdateMindMapCollaboratorReq) (rsp *def.UpdateMindMapCollaboratorResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.update_mindmap_collaborator", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.update_mindmap_collaborator", map[string]interface{}{"mapID": mapID, "userID": userID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "userID": userID, "req": req}, rsp, err)
+	}()
+
+	// 调用服务层修改协作者角色
+	if err = h.MindMapService.UpdateMindMapCollaboratorRole(ctx, mapID, userID, req.Role); err != nil {
+		return nil, err
+	}
+
+	rsp = &def.UpdateMindMapCollaboratorResp{
+		Success: true,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) RemoveMindMapCollaborator(ctx context.Context, mapID, userID string) (rsp *def.RemoveMindMapCollaboratorResp, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.remove_mindmap_collaborator", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.remove_mindmap_collaborator", map[string]interface{}{"mapID": mapID, "userID": userID}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "userID": userID}, rsp, err)
+	}()
+
+	// 调用服务层移除协作者
+	if err = h.MindMapService.RemoveMindMapCollaborator(ctx, mapID, userID); err != nil {
+		return nil, err
+	}
+
+	rsp = &def.RemoveMindMapCollaboratorResp{
+		Success: true,
+	}
+	return rsp, nil
+}
+
+func (h *Handler) JoinMindMapCollab(ctx context.Context, mapID string) (rsp *def.CollabServerMessage, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.join_mindmap_collab", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.join_mindmap_collab", mapID, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, mapID, rsp, err)
+	}()
+
+	// 调用服务层加入协同编辑
+	session, err := h.MindMapService.JoinCollabSession(ctx, mapID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装初始状态消息
+	rsp = caster.CastCollabSession2InitMessage(session)
+	return rsp, nil
+}
+
+func (h *Handler) LeaveMindMapCollab(ctx context.Context, mapID, sessionID string) (err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.leave_mindmap_collab", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.leave_mindmap_collab", map[string]interface{}{"mapID": mapID, "sessionID": sessionID}, nil, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "sessionID": sessionID}, nil, err)
+	}()
+
+	return h.MindMapService.LeaveCollabSession(ctx, mapID, sessionID)
+}
+
+func (h *Handler) UpdateMindMapCollabPresence(ctx context.Context, mapID, sessionID string, nodeID *string) (err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.update_mindmap_collab_presence", constant.LoopSpanType_Handle)
+	defer func() {
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "sessionID": sessionID, "nodeID": nodeID}, nil, err)
+	}()
+
+	return h.MindMapService.UpdateCollabPresence(ctx, mapID, sessionID, nodeID)
+}
+
+func (h *Handler) CommitMindMapCollabOps(ctx context.Context, mapID, sessionID string, req *def.CollabClientMessage) (rsp *def.CollabServerMessage, err error) {
+	// 链路追踪
+	ctx, sp := loop.GetNewSpan(ctx, "handler.commit_mindmap_collab_ops", constant.LoopSpanType_Handle)
+	defer func() {
+		zlog.CtxAllInOne(ctx, "handler.commit_mindmap_collab_ops", map[string]interface{}{"mapID": mapID, "sessionID": sessionID, "req": req}, rsp, err)
+		loop.SetSpanAllInOne(ctx, sp, map[string]interface{}{"mapID": mapID, "sessionID": sessionID, "req": req}, rsp, err)
+	}()
+
+	// 消息 -> Service 层参数转换
+	params := caster.CastCollabClientMessage2Params(sessionID, req)
+
+	// 调用服务层提交协同操作
+	result, err := h.MindMapService.CommitCollabOperations(ctx, mapID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 组装确认消息
+	rsp = caster.CastCollabCommitResult2AckMessage(req.ClientSeq, result)
+	return rsp, nil
+}
+
+// SubscribeMindMapCollabEvents 订阅协同事件并转换为广播消息，阻塞直到ctx结束
+func (h *Handler) SubscribeMindMapCollabEvents(ctx context.Context, fn func(mapID string, msg *def.CollabServerMessage)) {
+	h.MindMapService.SubscribeCollabEvents(ctx, func(event *types.CollabEvent) {
+		fn(event.MapID, caster.CastCollabEvent2Message(event))
+	})
+}
@@ -6,11 +6,26 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// allowOrigins 允许跨域访问的前端来源
+func allowOrigins() []string {
+	return []string{configs.Config().GetAppConfig().YourFrontendDomain}
+}
+
 func CorsMiddleware() gin.HandlerFunc {
 	return cors.New(cors.Config{
-		AllowOrigins:  []string{configs.Config().GetAppConfig().YourFrontendDomain},
+		AllowOrigins:  allowOrigins(),
 		AllowMethods:  []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:  []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
+		AllowHeaders:  []string{"Origin", "Content-Length", "Content-Type", "Authorization", SharePasswordHeader},
 		ExposeHeaders: []string{"Content-Length", "Authorization", "current-type"},
 	})
 }
+
+// IsAllowedOrigin 判断请求来源是否在跨域白名单中，WebSocket握手不经过CORS校验，需单独调用
+func IsAllowedOrigin(origin string) bool {
+	for _, allowed := range allowOrigins() {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
@@ -126,6 +126,59 @@ func RateLimiterByIP() gin.HandlerFunc {
 	}
 }
 
+// 分享链接密码校验的限流参数
+const (
+	SharePasswordHeader       = "X-Share-Password" // 分享密码通过请求头传递，避免被访问日志记录
+	sharePasswordWindow       = time.Minute
+	sharePasswordLimitPerIP   = 10  // 同一IP对同一分享每分钟最多尝试的次数
+	sharePasswordLimitPerLink = 100 // 同一分享每分钟最多尝试的次数，限制分布式暴力破解
+)
+
+// SharePasswordRateLimiter 分享链接密码尝试限流，防止暴力破解及大量密码校验耗尽CPU
+// 仅对携带密码的请求生效，不受全局限流开关影响
+func SharePasswordRateLimiter() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		if token == "" || c.GetHeader(SharePasswordHeader) == "" {
+			c.Next()
+			return
+		}
+
+		redisClient := cache.GetRedisClient()
+		if redisClient == nil {
+			zlog.Warnf("Redis 客户端未初始化，跳过分享密码限流")
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		ip := c.ClientIP()
+		for _, rule := range []struct {
+			key   string
+			limit int
+		}{
+			{key: fmt.Sprintf("rate_limit:share_password:%s:%s", token, ip), limit: sharePasswordLimitPerIP},
+			{key: fmt.Sprintf("rate_limit:share_password:%s", token), limit: sharePasswordLimitPerLink},
+		} {
+			allowed, err := checkRateLimit(ctx, redisClient, rule.key, rule.limit, sharePasswordWindow)
+			if err != nil {
+				zlog.Errorf("分享密码限流检查失败: %v", err)
+				c.Next()
+				return
+			}
+			if !allowed {
+				zlog.Warnf("IP %s 分享密码尝试被限流: %s", ip, c.Request.URL.Path)
+				resp := response.NewResponse(c)
+				resp.ErrorWithStatus(response.TOO_MANY_REQUESTS, http.StatusTooManyRequests)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
 // checkRateLimit 使用滑动窗口算法检查限流
 // 基于 Redis Sorted Set 实现滑动窗口
 func checkRateLimit(ctx context.Context, client *redis.Client, key string, limit int, window time.Duration) (bool, error) {
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocketTokenProtocol 浏览器通过子协议传递JWT时使用的协议名，
+// 客户端以 new WebSocket(url, ["access_token", token]) 建立连接，服务端握手时回应该协议名
+const WebSocketTokenProtocol = "access_token"
+
+// WebSocketToken 浏览器建立WebSocket连接时无法自定义请求头，允许通过 Sec-WebSocket-Protocol 传递JWT；
+// 不使用查询参数，避免令牌被写入访问日志。
+// 需放在 JWTAuth 之前，请求头中已携带 Authorization 时不做处理
+func WebSocketToken() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		if gCtx.GetHeader("Authorization") == "" {
+			if token := webSocketProtocolToken(gCtx.Request.Header.Values("Sec-WebSocket-Protocol")); token != "" {
+				gCtx.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		gCtx.Next()
+	}
+}
+
+// webSocketProtocolToken 取子协议列表中紧跟在 access_token 之后的令牌
+func webSocketProtocolToken(headers []string) string {
+	protocols := make([]string, 0, 2)
+	for _, header := range headers {
+		for _, protocol := range strings.Split(header, ",") {
+			protocols = append(protocols, strings.TrimSpace(protocol))
+		}
+	}
+	for i := 0; i+1 < len(protocols); i++ {
+		if protocols[i] == WebSocketTokenProtocol {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
@@ -3,7 +3,6 @@ package outputPort
 import (
 	"encoding/json"
 	"fmt"
-	"forge/biz/types"
 	"github.com/gin-gonic/gin"
 )
 
@@ -11,25 +10,17 @@ type GinSSEWriter struct {
 	Ctx *gin.Context
 }
 
-func (w *GinSSEWriter) WriteChunk(chunk types.StreamChunk) error {
-	data, err := json.Marshal(chunk.Content)
+// WriteEvent 写入一条带事件类型的SSE消息，data 序列化为JSON
+func (w *GinSSEWriter) WriteEvent(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("序列化事件数据失败: %w", err)
 	}
 
-	if data == nil {
-		return nil
-	}
-
-	_, err = fmt.Fprintf(w.Ctx.Writer, "data: %s\n\n", string(data))
-	w.Ctx.Writer.Flush()
-	if chunk.IsLast {
-		w.Ctx.Writer.WriteString("data: [END]\n\n")
+	_, err = fmt.Fprintf(w.Ctx.Writer, "event: %s\ndata: %s\n\n", event, string(payload))
+	if err != nil {
+		return fmt.Errorf("写入事件失败: %w", err)
 	}
 	w.Ctx.Writer.Flush()
 	return nil
 }
-
-//type StreamWriter interface {
-//	WriteChunk(chunk types.StreamChunk) error
-//}
@@ -40,11 +40,24 @@ func aiChatServiceErrorToMsgCode(err error) response.MsgCode {
 	if errors.Is(err, aichatservice.MIND_MAP_NOT_EXIST) {
 		return response.MIND_MAP_NOT_EXIST
 	}
-	if errors.Is(err, aichatservice.AI_CHAT_MESSAGE_MAX) {
-		return response.AI_CHAT_MESSAGE_MAX
+	if errors.Is(err, aichatservice.MAP_PROPOSAL_NOT_EXIST) {
+		return response.MAP_PROPOSAL_NOT_EXIST
+	}
+	if errors.Is(err, aichatservice.MAP_PROPOSAL_RESOLVED) {
+		return response.MAP_PROPOSAL_RESOLVED
+	}
+	if errors.Is(err, aichatservice.MESSAGE_CONTENT_NOT_NULL) {
+		return response.MESSAGE_CONTENT_NOT_NULL
+	}
+	if errors.Is(err, aichatservice.MESSAGE_NOT_EXIST) {
+		return response.MESSAGE_NOT_EXIST
+	}
+	if errors.Is(err, aichatservice.MESSAGE_NOT_EDITABLE) {
+		return response.MESSAGE_NOT_EDITABLE
 	}
 
-	return response.COMMON_FAIL
+	// 采纳修改建议时由导图服务返回的错误（如版本冲突）
+	return mapMindMapServiceErrorToMsgCode(err)
 }
 
 // SendMessage 基础ai对话
@@ -263,6 +276,41 @@ func GetConversation() gin.HandlerFunc {
 	}
 }
 
+// SwitchMessageBranch 切换会话的当前分支
+func SwitchMessageBranch() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		var req def.SwitchMessageBranchRequest
+		ctx := gCtx.Request.Context()
+
+		if err := gCtx.ShouldBindJSON(&req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_COMPLETE.Code,
+				Message: response.PARAM_NOT_COMPLETE.Msg,
+				Data:    def.SwitchMessageBranchResponse{Success: false},
+			})
+			return
+		}
+
+		resp, err := handler.GetHandler().SwitchMessageBranch(ctx, &req)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := aiChatServiceErrorToMsgCode(err)
+			if msgCode == response.COMMON_FAIL {
+				msgCode.Msg = err.Error()
+			}
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.SwitchMessageBranchResponse{Success: false},
+			})
+			return
+		} else {
+			r.Success(resp)
+		}
+	}
+}
+
 func UpdateConversationTitle() gin.HandlerFunc {
 	return func(gCtx *gin.Context) {
 		var req def.UpdateConversationTitleRequest
@@ -484,3 +532,113 @@ func TriggerQualityAssessment() gin.HandlerFunc {
 		}
 	}
 }
+
+// GetMapProposalList 获取某会话的导图修改建议
+func GetMapProposalList() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		var req def.GetMapProposalListRequest
+		ctx := gCtx.Request.Context()
+
+		req.ConversationID = gCtx.Query("conversation_id")
+
+		if req.ConversationID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_COMPLETE.Code,
+				Message: response.PARAM_NOT_COMPLETE.Msg,
+				Data:    def.GetMapProposalListResponse{Success: false},
+			})
+			return
+		}
+
+		resp, err := handler.GetHandler().GetMapProposalList(ctx, &req)
+		zlog.CtxAllInOne(ctx, "get_map_proposal_list", map[string]interface{}{"req": req}, resp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := aiChatServiceErrorToMsgCode(err)
+			if msgCode == response.COMMON_FAIL {
+				msgCode.Msg = err.Error()
+			}
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.GetMapProposalListResponse{Success: false},
+			})
+			return
+		} else {
+			r.Success(resp)
+		}
+	}
+}
+
+// AcceptMapProposal 采纳导图修改建议
+func AcceptMapProposal() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		var req def.ResolveMapProposalRequest
+		ctx := gCtx.Request.Context()
+
+		if err := gCtx.ShouldBindJSON(&req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_COMPLETE.Code,
+				Message: response.PARAM_NOT_COMPLETE.Msg,
+				Data:    def.ResolveMapProposalResponse{Success: false},
+			})
+			return
+		}
+
+		resp, err := handler.GetHandler().AcceptMapProposal(ctx, &req)
+		zlog.CtxAllInOne(ctx, "accept_map_proposal", map[string]interface{}{"req": req}, resp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := aiChatServiceErrorToMsgCode(err)
+			if msgCode == response.COMMON_FAIL {
+				msgCode.Msg = err.Error()
+			}
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ResolveMapProposalResponse{Success: false},
+			})
+			return
+		} else {
+			r.Success(resp)
+		}
+	}
+}
+
+// RejectMapProposal 拒绝导图修改建议
+func RejectMapProposal() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		var req def.ResolveMapProposalRequest
+		ctx := gCtx.Request.Context()
+
+		if err := gCtx.ShouldBindJSON(&req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_COMPLETE.Code,
+				Message: response.PARAM_NOT_COMPLETE.Msg,
+				Data:    def.ResolveMapProposalResponse{Success: false},
+			})
+			return
+		}
+
+		resp, err := handler.GetHandler().RejectMapProposal(ctx, &req)
+		zlog.CtxAllInOne(ctx, "reject_map_proposal", map[string]interface{}{"req": req}, resp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := aiChatServiceErrorToMsgCode(err)
+			if msgCode == response.COMMON_FAIL {
+				msgCode.Msg = err.Error()
+			}
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ResolveMapProposalResponse{Success: false},
+			})
+			return
+		} else {
+			r.Success(resp)
+		}
+	}
+}
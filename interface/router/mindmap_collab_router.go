@@ -0,0 +1,155 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"forge/interface/collab"
+	"forge/interface/def"
+	"forge/interface/handler"
+	"forge/interface/middleware"
+	"forge/pkg/log/zlog"
+	"forge/pkg/response"
+)
+
+// collabPresenceInterval 连接存活期间刷新在线状态的周期
+const collabPresenceInterval = 30 * time.Second
+
+var collabUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	// WebSocket握手不经过CORS校验，按同一白名单校验来源；非浏览器客户端不携带 Origin
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || middleware.IsAllowedOrigin(origin)
+	},
+	// 回应客户端用于传递JWT的子协议
+	Subprotocols: []string{middleware.WebSocketTokenProtocol},
+}
+
+// MindMapCollab
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/collab (WebSocket)
+//	@return gin.HandlerFunc
+func MindMapCollab() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.CollabServerMessage{},
+			})
+			return
+		}
+
+		// 升级前先加入会话完成权限校验，失败时以普通JSON响应返回
+		initMsg, err := handler.GetHandler().JoinMindMapCollab(ctx, mapID)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.CollabServerMessage{},
+			})
+			return
+		}
+		sessionID := initMsg.SessionID
+
+		// 连接断开后请求上下文可能已取消，离开会话使用独立的上下文
+		leave := func() {
+			if err := handler.GetHandler().LeaveMindMapCollab(context.WithoutCancel(ctx), mapID, sessionID); err != nil {
+				zlog.CtxWarnf(ctx, "leave collab session failed, mapID: %s, sessionID: %s, err: %v", mapID, sessionID, err)
+			}
+		}
+
+		ws, err := collabUpgrader.Upgrade(gCtx.Writer, gCtx.Request, nil)
+		if err != nil {
+			// Upgrade 失败时已写回HTTP错误响应
+			zlog.CtxWarnf(ctx, "collab websocket upgrade failed, mapID: %s, err: %v", mapID, err)
+			leave()
+			return
+		}
+
+		conn := collab.NewConn(ws, mapID, sessionID)
+		collab.GetHub().Register(conn)
+		go conn.WritePump()
+		go keepCollabPresence(ctx, conn, mapID, sessionID)
+		defer func() {
+			collab.GetHub().Unregister(conn)
+			conn.Close()
+			leave()
+		}()
+
+		conn.Send(initMsg)
+		serveCollabConn(ctx, conn, mapID, sessionID)
+	}
+}
+
+// serveCollabConn 循环读取并处理客户端消息，直到连接关闭
+func serveCollabConn(ctx context.Context, conn *collab.Conn, mapID, sessionID string) {
+	for {
+		msg := &def.CollabClientMessage{}
+		if err := conn.ReadMessage(msg); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				zlog.CtxWarnf(ctx, "collab connection closed unexpectedly, mapID: %s, sessionID: %s, err: %v", mapID, sessionID, err)
+			}
+			return
+		}
+
+		switch msg.Type {
+		case def.CollabMsgOp:
+			rsp, err := handler.GetHandler().CommitMindMapCollabOps(ctx, mapID, sessionID, msg)
+			if err != nil {
+				conn.Send(collabErrorMessage(msg.ClientSeq, mapMindMapServiceErrorToMsgCode(err)))
+				continue
+			}
+			conn.Send(rsp)
+		case def.CollabMsgSelect:
+			if msg.NodeID == nil {
+				conn.Send(collabErrorMessage(msg.ClientSeq, response.PARAM_NOT_VALID))
+				continue
+			}
+			if err := handler.GetHandler().UpdateMindMapCollabPresence(ctx, mapID, sessionID, msg.NodeID); err != nil {
+				conn.Send(collabErrorMessage(msg.ClientSeq, mapMindMapServiceErrorToMsgCode(err)))
+			}
+		case def.CollabMsgPing:
+			conn.Send(&def.CollabServerMessage{Type: def.CollabMsgPong, ClientSeq: msg.ClientSeq})
+		default:
+			conn.Send(collabErrorMessage(msg.ClientSeq, response.PARAM_NOT_VALID))
+		}
+	}
+}
+
+// keepCollabPresence 连接存活期间定期刷新在线状态，避免被判定为离线
+func keepCollabPresence(ctx context.Context, conn *collab.Conn, mapID, sessionID string) {
+	ticker := time.NewTicker(collabPresenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-conn.Done():
+			return
+		case <-ticker.C:
+			if err := handler.GetHandler().UpdateMindMapCollabPresence(ctx, mapID, sessionID, nil); err != nil {
+				zlog.CtxWarnf(ctx, "refresh collab presence failed, mapID: %s, sessionID: %s, err: %v", mapID, sessionID, err)
+			}
+		}
+	}
+}
+
+func collabErrorMessage(clientSeq int64, msgCode response.MsgCode) *def.CollabServerMessage {
+	return &def.CollabServerMessage{
+		Type:      def.CollabMsgError,
+		ClientSeq: clientSeq,
+		Code:      msgCode.Code,
+		Message:   msgCode.Msg,
+	}
+}
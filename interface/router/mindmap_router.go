@@ -2,7 +2,10 @@ package router
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -39,6 +42,114 @@ func mapMindMapServiceErrorToMsgCode(err error) response.MsgCode {
 		return response.MINDMAP_PERMISSION_DENIED
 	}
 
+	if errors.Is(err, mindmapservice.ErrVersionConflict) {
+		return response.MINDMAP_VERSION_CONFLICT
+	}
+
+	if errors.Is(err, mindmapservice.ErrRevisionNotFound) {
+		return response.MINDMAP_REVISION_NOT_FOUND
+	}
+
+	if errors.Is(err, mindmapservice.ErrNodeNotFound) {
+		return response.MINDMAP_NODE_NOT_FOUND
+	}
+
+	if errors.Is(err, mindmapservice.ErrInvalidNodeOperation) {
+		return response.MINDMAP_INVALID_NODE_OP
+	}
+
+	if errors.Is(err, mindmapservice.ErrUnsupportedExportFormat) || errors.Is(err, mindmapservice.ErrUnsupportedImportFormat) {
+		return response.MINDMAP_UNSUPPORTED_FORMAT
+	}
+
+	if errors.Is(err, mindmapservice.ErrImportParseFailed) {
+		return response.MINDMAP_IMPORT_FAILED
+	}
+
+	if errors.Is(err, mindmapservice.ErrShareNotFound) {
+		return response.MINDMAP_SHARE_NOT_FOUND
+	}
+
+	if errors.Is(err, mindmapservice.ErrShareExpired) {
+		return response.MINDMAP_SHARE_EXPIRED
+	}
+
+	if errors.Is(err, mindmapservice.ErrSharePasswordRequired) {
+		return response.MINDMAP_SHARE_NEED_PASSWD
+	}
+
+	if errors.Is(err, mindmapservice.ErrSharePasswordIncorrect) {
+		return response.MINDMAP_SHARE_WRONG_PASSWD
+	}
+
+	if errors.Is(err, mindmapservice.ErrCollaboratorUserNotFound) {
+		return response.MINDMAP_INVITEE_NOT_FOUND
+	}
+
+	if errors.Is(err, mindmapservice.ErrCollaboratorNotFound) {
+		return response.MINDMAP_COLLAB_NOT_FOUND
+	}
+
+	if errors.Is(err, mindmapservice.ErrCollabSessionNotFound) {
+		return response.MINDMAP_COLLAB_SESSION_BAD
+	}
+
+	if errors.Is(err, mindmapservice.ErrCollabBaseExpired) {
+		return response.MINDMAP_COLLAB_NEED_RESYNC
+	}
+
+	if errors.Is(err, mindmapservice.ErrFolderNotFound) {
+		return response.MINDMAP_FOLDER_NOT_FOUND
+	}
+
+	if errors.Is(err, mindmapservice.ErrFolderNameExists) {
+		return response.MINDMAP_FOLDER_NAME_EXISTS
+	}
+
+	if errors.Is(err, mindmapservice.ErrFolderInvalidParent) {
+		return response.MINDMAP_FOLDER_BAD_PARENT
+	}
+
+	if errors.Is(err, mindmapservice.ErrFolderTooDeep) {
+		return response.MINDMAP_FOLDER_TOO_DEEP
+	}
+
+	if errors.Is(err, mindmapservice.ErrTagNotFound) {
+		return response.MINDMAP_TAG_NOT_FOUND
+	}
+
+	if errors.Is(err, mindmapservice.ErrTagNameExists) {
+		return response.MINDMAP_TAG_NAME_EXISTS
+	}
+
+	if errors.Is(err, mindmapservice.ErrTemplateNotFound) {
+		return response.MINDMAP_TEMPLATE_NOT_FOUND
+	}
+
+	if errors.Is(err, mindmapservice.ErrTemplateLimitExceeded) {
+		return response.MINDMAP_TEMPLATE_LIMIT
+	}
+
+	if errors.Is(err, mindmapservice.ErrTemplateFillFailed) {
+		return response.MINDMAP_TEMPLATE_FILL_FAIL
+	}
+
+	if errors.Is(err, mindmapservice.ErrNodeLinkNotFound) {
+		return response.MINDMAP_LINK_NOT_FOUND
+	}
+
+	if errors.Is(err, mindmapservice.ErrNodeLinkExists) {
+		return response.MINDMAP_LINK_EXISTS
+	}
+
+	if errors.Is(err, mindmapservice.ErrUnsupportedLayout) {
+		return response.MINDMAP_UNSUPPORTED_LAYOUT
+	}
+
+	if errors.Is(err, mindmapservice.ErrExportFontNotConfigured) {
+		return response.MINDMAP_EXPORT_FONT_MISS
+	}
+
 	if errors.Is(err, mindmapservice.ErrInternalError) {
 		return response.INTERNAL_ERROR
 	}
@@ -218,6 +329,206 @@ func UpdateMindMap() gin.HandlerFunc {
 	}
 }
 
+// PatchMindMap
+//
+//	@Description:[PATCH] /api/biz/v1/mindmap/:id
+//	@return gin.HandlerFunc
+func PatchMindMap() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.PatchMindMapReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.PatchMindMapResp{Success: false},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.PatchMindMapResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().PatchMindMap(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "patch_mindmap", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.PatchMindMapResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ComputeMindMapLayout
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/layout?layout=mindMap
+//	@return gin.HandlerFunc
+func ComputeMindMapLayout() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.ComputeMindMapLayoutReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.ComputeMindMapLayoutResp{},
+			})
+			return
+		}
+
+		// 绑定查询参数
+		if err := gCtx.ShouldBindQuery(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.ComputeMindMapLayoutResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ComputeMindMapLayout(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "compute_mind_map_layout", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ComputeMindMapLayoutResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ExportMindMap
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/export?format=markdown
+//	@return gin.HandlerFunc
+func ExportMindMap() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.ExportMindMapReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.ExportMindMapResp{},
+			})
+			return
+		}
+
+		// 绑定查询参数
+		if err := gCtx.ShouldBindQuery(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.ExportMindMapResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ExportMindMap(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "export_mindmap", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ExportMindMapResp{},
+			})
+			return
+		}
+
+		// 成功时设置响应头并返回文件流
+		writeAttachment(gCtx, rsp.FileName, rsp.ContentType, rsp.Content)
+	}
+}
+
+// writeAttachment 以附件形式返回文件内容，文件名按 RFC 5987 编码以支持中文
+func writeAttachment(gCtx *gin.Context, fileName, contentType string, content []byte) {
+	gCtx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"; filename*=UTF-8''%s",
+		url.PathEscape(fileName), url.PathEscape(fileName)))
+	gCtx.Data(http.StatusOK, contentType, content)
+}
+
+// ImportMindMap
+//
+//	@Description:[POST] /api/biz/v1/mindmap/import
+//	@return gin.HandlerFunc
+func ImportMindMap() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.ImportMindMapReq{}
+		ctx := gCtx.Request.Context()
+
+		// 处理文件上传
+		file, err := gCtx.FormFile("file")
+		if err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_COMPLETE.Code,
+				Message: response.PARAM_NOT_COMPLETE.Msg,
+				Data:    def.ImportMindMapResp{},
+			})
+			return
+		}
+		req.File = file
+
+		// 绑定其他参数
+		if err := gCtx.ShouldBind(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.ImportMindMapResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ImportMindMap(ctx, req)
+		zlog.CtxAllInOne(ctx, "import_mindmap", map[string]interface{}{"filename": file.Filename, "size": file.Size}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ImportMindMapResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
 // DeleteMindMap
 //
 //	@Description:[DELETE] /api/biz/v1/mindmap/:id
@@ -297,3 +608,1699 @@ func BatchDeleteMindMap() gin.HandlerFunc {
 		}
 	}
 }
+
+// ListMindMapFolders
+//
+//	@Description:[GET] /api/biz/v1/mindmap/folders
+//	@return gin.HandlerFunc
+func ListMindMapFolders() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		ctx := gCtx.Request.Context()
+
+		rsp, err := handler.GetHandler().ListMindMapFolders(ctx)
+		zlog.CtxAllInOne(ctx, "list_mind_map_folders", nil, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ListMindMapFoldersResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// CreateMindMapFolder
+//
+//	@Description:[POST] /api/biz/v1/mindmap/folders
+//	@return gin.HandlerFunc
+func CreateMindMapFolder() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.CreateMindMapFolderReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.CreateMindMapFolderResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().CreateMindMapFolder(ctx, req)
+		zlog.CtxAllInOne(ctx, "create_mind_map_folder", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.CreateMindMapFolderResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// UpdateMindMapFolder
+//
+//	@Description:[PUT] /api/biz/v1/mindmap/folders/:folder_id
+//	@return gin.HandlerFunc
+func UpdateMindMapFolder() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		folderID := gCtx.Param("folder_id")
+		req := &def.UpdateMindMapFolderReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if folderID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.UpdateMindMapFolderResp{},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.UpdateMindMapFolderResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().UpdateMindMapFolder(ctx, folderID, req)
+		zlog.CtxAllInOne(ctx, "update_mind_map_folder", map[string]interface{}{"folderID": folderID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.UpdateMindMapFolderResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// DeleteMindMapFolder
+//
+//	@Description:[DELETE] /api/biz/v1/mindmap/folders/:folder_id
+//	@return gin.HandlerFunc
+func DeleteMindMapFolder() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		folderID := gCtx.Param("folder_id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if folderID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.DeleteMindMapFolderResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().DeleteMindMapFolder(ctx, folderID)
+		zlog.CtxAllInOne(ctx, "delete_mind_map_folder", folderID, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.DeleteMindMapFolderResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// MoveMindMaps
+//
+//	@Description:[POST] /api/biz/v1/mindmap/move
+//	@return gin.HandlerFunc
+func MoveMindMaps() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.MoveMindMapsReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.MoveMindMapsResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().MoveMindMaps(ctx, req)
+		zlog.CtxAllInOne(ctx, "move_mind_maps", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.MoveMindMapsResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ListMindMapTags
+//
+//	@Description:[GET] /api/biz/v1/mindmap/tags
+//	@return gin.HandlerFunc
+func ListMindMapTags() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		ctx := gCtx.Request.Context()
+
+		rsp, err := handler.GetHandler().ListMindMapTags(ctx)
+		zlog.CtxAllInOne(ctx, "list_mind_map_tags", nil, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ListMindMapTagsResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// CreateMindMapTag
+//
+//	@Description:[POST] /api/biz/v1/mindmap/tags
+//	@return gin.HandlerFunc
+func CreateMindMapTag() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.CreateMindMapTagReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.CreateMindMapTagResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().CreateMindMapTag(ctx, req)
+		zlog.CtxAllInOne(ctx, "create_mind_map_tag", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.CreateMindMapTagResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// UpdateMindMapTag
+//
+//	@Description:[PUT] /api/biz/v1/mindmap/tags/:tag_id
+//	@return gin.HandlerFunc
+func UpdateMindMapTag() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		tagID := gCtx.Param("tag_id")
+		req := &def.UpdateMindMapTagReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if tagID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.UpdateMindMapTagResp{},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.UpdateMindMapTagResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().UpdateMindMapTag(ctx, tagID, req)
+		zlog.CtxAllInOne(ctx, "update_mind_map_tag", map[string]interface{}{"tagID": tagID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.UpdateMindMapTagResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// DeleteMindMapTag
+//
+//	@Description:[DELETE] /api/biz/v1/mindmap/tags/:tag_id
+//	@return gin.HandlerFunc
+func DeleteMindMapTag() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		tagID := gCtx.Param("tag_id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if tagID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.DeleteMindMapTagResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().DeleteMindMapTag(ctx, tagID)
+		zlog.CtxAllInOne(ctx, "delete_mind_map_tag", tagID, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.DeleteMindMapTagResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// TagMindMaps
+//
+//	@Description:[POST] /api/biz/v1/mindmap/tags/batch_add
+//	@return gin.HandlerFunc
+func TagMindMaps() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.BatchTagMindMapsReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.BatchTagMindMapsResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().TagMindMaps(ctx, req)
+		zlog.CtxAllInOne(ctx, "tag_mind_maps", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.BatchTagMindMapsResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// UntagMindMaps
+//
+//	@Description:[POST] /api/biz/v1/mindmap/tags/batch_remove
+//	@return gin.HandlerFunc
+func UntagMindMaps() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.BatchTagMindMapsReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.BatchTagMindMapsResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().UntagMindMaps(ctx, req)
+		zlog.CtxAllInOne(ctx, "untag_mind_maps", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.BatchTagMindMapsResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ListMindMapTemplates
+//
+//	@Description:[GET] /api/biz/v1/mindmap/templates
+//	@return gin.HandlerFunc
+func ListMindMapTemplates() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		ctx := gCtx.Request.Context()
+
+		rsp, err := handler.GetHandler().ListMindMapTemplates(ctx)
+		zlog.CtxAllInOne(ctx, "list_mind_map_templates", nil, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ListMindMapTemplatesResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// GetMindMapTemplate
+//
+//	@Description:[GET] /api/biz/v1/mindmap/templates/:template_id
+//	@return gin.HandlerFunc
+func GetMindMapTemplate() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		templateID := gCtx.Param("template_id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if templateID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.GetMindMapTemplateResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().GetMindMapTemplate(ctx, templateID)
+		zlog.CtxAllInOne(ctx, "get_mind_map_template", templateID, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.GetMindMapTemplateResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// SaveMindMapAsTemplate
+//
+//	@Description:[POST] /api/biz/v1/mindmap/templates
+//	@return gin.HandlerFunc
+func SaveMindMapAsTemplate() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.SaveMindMapAsTemplateReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.SaveMindMapAsTemplateResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().SaveMindMapAsTemplate(ctx, req)
+		zlog.CtxAllInOne(ctx, "save_mind_map_as_template", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.SaveMindMapAsTemplateResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// DeleteMindMapTemplate
+//
+//	@Description:[DELETE] /api/biz/v1/mindmap/templates/:template_id
+//	@return gin.HandlerFunc
+func DeleteMindMapTemplate() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		templateID := gCtx.Param("template_id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if templateID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.DeleteMindMapTemplateResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().DeleteMindMapTemplate(ctx, templateID)
+		zlog.CtxAllInOne(ctx, "delete_mind_map_template", templateID, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.DeleteMindMapTemplateResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// CreateMindMapFromTemplate
+//
+//	@Description:[POST] /api/biz/v1/mindmap/templates/:template_id/create
+//	@return gin.HandlerFunc
+func CreateMindMapFromTemplate() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		templateID := gCtx.Param("template_id")
+		req := &def.CreateMindMapFromTemplateReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if templateID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.CreateMindMapFromTemplateResp{},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.CreateMindMapFromTemplateResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().CreateMindMapFromTemplate(ctx, templateID, req)
+		zlog.CtxAllInOne(ctx, "create_mind_map_from_template", map[string]interface{}{"templateID": templateID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.CreateMindMapFromTemplateResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// DuplicateMindMap
+//
+//	@Description:[POST] /api/biz/v1/mindmap/:id/duplicate
+//	@return gin.HandlerFunc
+func DuplicateMindMap() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.DuplicateMindMapReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.DuplicateMindMapResp{},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.DuplicateMindMapResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().DuplicateMindMap(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "duplicate_mind_map", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.DuplicateMindMapResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ExtractMindMapSubtree
+//
+//	@Description:[POST] /api/biz/v1/mindmap/:id/extract
+//	@return gin.HandlerFunc
+func ExtractMindMapSubtree() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.ExtractMindMapSubtreeReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.ExtractMindMapSubtreeResp{},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.ExtractMindMapSubtreeResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ExtractMindMapSubtree(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "extract_mind_map_subtree", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ExtractMindMapSubtreeResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// GraftMindMap
+//
+//	@Description:[POST] /api/biz/v1/mindmap/:id/graft
+//	@return gin.HandlerFunc
+func GraftMindMap() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.GraftMindMapReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.GraftMindMapResp{Success: false},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.GraftMindMapResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().GraftMindMap(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "graft_mind_map", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.GraftMindMapResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// CreateMindMapNodeLink
+//
+//	@Description:[POST] /api/biz/v1/mindmap/:id/links
+//	@return gin.HandlerFunc
+func CreateMindMapNodeLink() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.CreateMindMapNodeLinkReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.CreateMindMapNodeLinkResp{},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.CreateMindMapNodeLinkResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().CreateMindMapNodeLink(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "create_mind_map_node_link", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.CreateMindMapNodeLinkResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ListMindMapNodeLinks
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/links?node_id=xxx
+//	@return gin.HandlerFunc
+func ListMindMapNodeLinks() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.ListMindMapNodeLinksReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.ListMindMapNodeLinksResp{},
+			})
+			return
+		}
+
+		// 绑定查询参数
+		if err := gCtx.ShouldBindQuery(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.ListMindMapNodeLinksResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ListMindMapNodeLinks(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "list_mind_map_node_links", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ListMindMapNodeLinksResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// DeleteMindMapNodeLink
+//
+//	@Description:[DELETE] /api/biz/v1/mindmap/:id/links/:link_id
+//	@return gin.HandlerFunc
+func DeleteMindMapNodeLink() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		linkID := gCtx.Param("link_id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" || linkID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.DeleteMindMapNodeLinkResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().DeleteMindMapNodeLink(ctx, mapID, linkID)
+		zlog.CtxAllInOne(ctx, "delete_mind_map_node_link", map[string]interface{}{"mapID": mapID, "linkID": linkID}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.DeleteMindMapNodeLinkResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ListMindMapBacklinks
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/backlinks?node_id=xxx
+//	@return gin.HandlerFunc
+func ListMindMapBacklinks() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.ListMindMapNodeLinksReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.ListMindMapNodeLinksResp{},
+			})
+			return
+		}
+
+		// 绑定查询参数
+		if err := gCtx.ShouldBindQuery(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.ListMindMapNodeLinksResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ListMindMapBacklinks(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "list_mind_map_backlinks", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ListMindMapNodeLinksResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// LintMindMap
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/lint
+//	@return gin.HandlerFunc
+func LintMindMap() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.LintMindMapResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().LintMindMap(ctx, mapID)
+		zlog.CtxAllInOne(ctx, "lint_mind_map", map[string]interface{}{"mapID": mapID}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.LintMindMapResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// SearchMindMaps
+//
+//	@Description:[GET] /api/biz/v1/mindmap/search
+//	@return gin.HandlerFunc
+func SearchMindMaps() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.SearchMindMapsReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定查询参数
+		if err := gCtx.ShouldBindQuery(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.SearchMindMapsResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().SearchMindMaps(ctx, req)
+		zlog.CtxAllInOne(ctx, "search_mindmaps", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.SearchMindMapsResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ListTrashedMindMaps
+//
+//	@Description:[GET] /api/biz/v1/mindmap/trash
+//	@return gin.HandlerFunc
+func ListTrashedMindMaps() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.ListTrashedMindMapsReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定查询参数
+		if err := gCtx.ShouldBindQuery(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.ListTrashedMindMapsResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ListTrashedMindMaps(ctx, req)
+		zlog.CtxAllInOne(ctx, "list_trashed_mindmaps", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ListTrashedMindMapsResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// RestoreMindMap
+//
+//	@Description:[POST] /api/biz/v1/mindmap/trash/restore
+//	@return gin.HandlerFunc
+func RestoreMindMap() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.RestoreMindMapReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.RestoreMindMapResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().RestoreMindMap(ctx, req)
+		zlog.CtxAllInOne(ctx, "restore_mindmap", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.RestoreMindMapResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// PurgeMindMap
+//
+//	@Description:[POST] /api/biz/v1/mindmap/trash/purge
+//	@return gin.HandlerFunc
+func PurgeMindMap() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		req := &def.PurgeMindMapReq{}
+		ctx := gCtx.Request.Context()
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.PurgeMindMapResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().PurgeMindMap(ctx, req)
+		zlog.CtxAllInOne(ctx, "purge_mindmap", req, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.PurgeMindMapResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// parseRevisionVersion 解析路径中的历史版本号
+func parseRevisionVersion(gCtx *gin.Context) (int64, bool) {
+	version, err := strconv.ParseInt(gCtx.Param("version"), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// ListMindMapRevisions
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/revisions
+//	@return gin.HandlerFunc
+func ListMindMapRevisions() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.ListMindMapRevisionsReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.ListMindMapRevisionsResp{},
+			})
+			return
+		}
+
+		// 绑定查询参数
+		if err := gCtx.ShouldBindQuery(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.ListMindMapRevisionsResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ListMindMapRevisions(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "list_mindmap_revisions", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ListMindMapRevisionsResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// GetMindMapRevision
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/revisions/:version
+//	@return gin.HandlerFunc
+func GetMindMapRevision() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		version, ok := parseRevisionVersion(gCtx)
+		if mapID == "" || !ok {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.GetMindMapRevisionResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().GetMindMapRevision(ctx, mapID, version)
+		zlog.CtxAllInOne(ctx, "get_mindmap_revision", map[string]interface{}{"mapID": mapID, "version": version}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.GetMindMapRevisionResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// RestoreMindMapRevision
+//
+//	@Description:[POST] /api/biz/v1/mindmap/:id/revisions/:version/restore
+//	@return gin.HandlerFunc
+func RestoreMindMapRevision() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.RestoreMindMapRevisionReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		version, ok := parseRevisionVersion(gCtx)
+		if mapID == "" || !ok {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.RestoreMindMapRevisionResp{Success: false},
+			})
+			return
+		}
+
+		// 请求体可选，仅用于携带乐观锁版本号
+		if gCtx.Request.ContentLength > 0 {
+			if err := gCtx.ShouldBindJSON(req); err != nil {
+				gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+					Code:    response.INVALID_PARAMS.Code,
+					Message: response.INVALID_PARAMS.Msg,
+					Data:    def.RestoreMindMapRevisionResp{Success: false},
+				})
+				return
+			}
+		}
+
+		rsp, err := handler.GetHandler().RestoreMindMapRevision(ctx, mapID, version, req)
+		zlog.CtxAllInOne(ctx, "restore_mindmap_revision", map[string]interface{}{"mapID": mapID, "version": version, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.RestoreMindMapRevisionResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// DiffMindMapRevisions
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/diff?from=1&to=2
+//	@return gin.HandlerFunc
+func DiffMindMapRevisions() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.DiffMindMapRevisionsReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.DiffMindMapResp{},
+			})
+			return
+		}
+
+		// 绑定查询参数
+		if err := gCtx.ShouldBindQuery(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.DiffMindMapResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().DiffMindMapRevisions(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "diff_mind_map_revisions", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.DiffMindMapResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// diffRequestMaxBytes 与任意数据比较时请求体的最大字节数
+const diffRequestMaxBytes = 4 << 20
+
+// DiffMindMapWithData
+//
+//	@Description:[POST] /api/biz/v1/mindmap/:id/diff
+//	@return gin.HandlerFunc
+func DiffMindMapWithData() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.DiffMindMapWithDataReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.DiffMindMapResp{},
+			})
+			return
+		}
+
+		// 限制请求体大小，超出时绑定失败
+		gCtx.Request.Body = http.MaxBytesReader(gCtx.Writer, gCtx.Request.Body, diffRequestMaxBytes)
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.DiffMindMapResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().DiffMindMapWithData(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "diff_mind_map_with_data", map[string]interface{}{"mapID": mapID, "fromVersion": req.FromVersion}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.DiffMindMapResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// CreateMindMapShare
+//
+//	@Description:[POST] /api/biz/v1/mindmap/:id/shares
+//	@return gin.HandlerFunc
+func CreateMindMapShare() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.CreateMindMapShareReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.CreateMindMapShareResp{},
+			})
+			return
+		}
+
+		// 请求体可选，不传时创建永久有效且无密码的分享
+		if gCtx.Request.ContentLength > 0 {
+			if err := gCtx.ShouldBindJSON(req); err != nil {
+				gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+					Code:    response.INVALID_PARAMS.Code,
+					Message: response.INVALID_PARAMS.Msg,
+					Data:    def.CreateMindMapShareResp{},
+				})
+				return
+			}
+		}
+
+		rsp, err := handler.GetHandler().CreateMindMapShare(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "create_mindmap_share", map[string]interface{}{"mapID": mapID, "expiresAt": req.ExpiresAt}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.CreateMindMapShareResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ListMindMapShares
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/shares
+//	@return gin.HandlerFunc
+func ListMindMapShares() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.ListMindMapSharesResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ListMindMapShares(ctx, mapID)
+		zlog.CtxAllInOne(ctx, "list_mindmap_shares", mapID, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ListMindMapSharesResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// RevokeMindMapShare
+//
+//	@Description:[DELETE] /api/biz/v1/mindmap/:id/shares/:share_id
+//	@return gin.HandlerFunc
+func RevokeMindMapShare() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		shareID := gCtx.Param("share_id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" || shareID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.RevokeMindMapShareResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().RevokeMindMapShare(ctx, mapID, shareID)
+		zlog.CtxAllInOne(ctx, "revoke_mindmap_share", map[string]interface{}{"mapID": mapID, "shareID": shareID}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.RevokeMindMapShareResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// GetSharedMindMap
+//
+//	@Description:[GET] /api/biz/v1/share/:token
+//	@return gin.HandlerFunc
+func GetSharedMindMap() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		token := gCtx.Param("token")
+		req := &def.GetSharedMindMapReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if token == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.GetSharedMindMapResp{},
+			})
+			return
+		}
+
+		// 绑定请求头
+		if err := gCtx.ShouldBindHeader(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.GetSharedMindMapResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().GetSharedMindMap(ctx, token, req)
+		zlog.CtxAllInOne(ctx, "get_shared_mindmap", token, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.GetSharedMindMapResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// ListMindMapCollaborators
+//
+//	@Description:[GET] /api/biz/v1/mindmap/:id/collaborators
+//	@return gin.HandlerFunc
+func ListMindMapCollaborators() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.ListMindMapCollaboratorsResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().ListMindMapCollaborators(ctx, mapID)
+		zlog.CtxAllInOne(ctx, "list_mindmap_collaborators", mapID, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.ListMindMapCollaboratorsResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// AddMindMapCollaborator
+//
+//	@Description:[POST] /api/biz/v1/mindmap/:id/collaborators
+//	@return gin.HandlerFunc
+func AddMindMapCollaborator() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		req := &def.AddMindMapCollaboratorReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.AddMindMapCollaboratorResp{},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.AddMindMapCollaboratorResp{},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().AddMindMapCollaborator(ctx, mapID, req)
+		zlog.CtxAllInOne(ctx, "add_mindmap_collaborator", map[string]interface{}{"mapID": mapID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.AddMindMapCollaboratorResp{},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// UpdateMindMapCollaborator
+//
+//	@Description:[PUT] /api/biz/v1/mindmap/:id/collaborators/:user_id
+//	@return gin.HandlerFunc
+func UpdateMindMapCollaborator() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		userID := gCtx.Param("user_id")
+		req := &def.UpdateMindMapCollaboratorReq{}
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" || userID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.UpdateMindMapCollaboratorResp{Success: false},
+			})
+			return
+		}
+
+		// 绑定JSON请求体
+		if err := gCtx.ShouldBindJSON(req); err != nil {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.INVALID_PARAMS.Code,
+				Message: response.INVALID_PARAMS.Msg,
+				Data:    def.UpdateMindMapCollaboratorResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().UpdateMindMapCollaborator(ctx, mapID, userID, req)
+		zlog.CtxAllInOne(ctx, "update_mindmap_collaborator", map[string]interface{}{"mapID": mapID, "userID": userID, "req": req}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.UpdateMindMapCollaboratorResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
+
+// RemoveMindMapCollaborator
+//
+//	@Description:[DELETE] /api/biz/v1/mindmap/:id/collaborators/:user_id
+//	@return gin.HandlerFunc
+func RemoveMindMapCollaborator() gin.HandlerFunc {
+	return func(gCtx *gin.Context) {
+		mapID := gCtx.Param("id")
+		userID := gCtx.Param("user_id")
+		ctx := gCtx.Request.Context()
+
+		// 参数校验
+		if mapID == "" || userID == "" {
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    response.PARAM_NOT_VALID.Code,
+				Message: response.PARAM_NOT_VALID.Msg,
+				Data:    def.RemoveMindMapCollaboratorResp{Success: false},
+			})
+			return
+		}
+
+		rsp, err := handler.GetHandler().RemoveMindMapCollaborator(ctx, mapID, userID)
+		zlog.CtxAllInOne(ctx, "remove_mindmap_collaborator", map[string]interface{}{"mapID": mapID, "userID": userID}, rsp, err)
+
+		r := response.NewResponse(gCtx)
+		if err != nil {
+			msgCode := mapMindMapServiceErrorToMsgCode(err)
+			gCtx.JSON(http.StatusOK, response.JsonMsgResult{
+				Code:    msgCode.Code,
+				Message: msgCode.Msg,
+				Data:    def.RemoveMindMapCollaboratorResp{Success: false},
+			})
+			return
+		} else {
+			r.Success(rsp)
+		}
+	}
+}
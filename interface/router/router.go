@@ -55,11 +55,19 @@ func register() (router *gin.Engine) {
 	userAuthGroup := r.Group("user", jwtAuthMiddleware)
 	loadUserAuthService(userAuthGroup)
 
+	// 思维导图公开分享路由（不需要JWT，通过分享令牌访问）
+	shareGroup := r.Group("share")
+	loadShareService(shareGroup)
+
 	// mindmap路由组需要JWT鉴权
 	mindMapGroup := r.Group("mindmap", jwtAuthMiddleware)
 	loadMindMapService(mindMapGroup)
 	loadGenerationService(mindMapGroup)
 
+	// 思维导图实时协同（WebSocket），浏览器无法设置请求头，允许通过查询参数传递token
+	collabGroup := r.Group("mindmap", middleware.WebSocketToken(), jwtAuthMiddleware)
+	loadMindMapCollabService(collabGroup)
+
 	// cos路由组需要JWT鉴权
 	cosGroup := r.Group("cos", jwtAuthMiddleware)
 	loadCOSService(cosGroup)
@@ -84,6 +92,7 @@ const (
 	POST   = "POST"
 	GET    = "GET"
 	PUT    = "PUT"
+	PATCH  = "PATCH"
 	DELETE = "DELETE"
 )
 
@@ -138,7 +147,7 @@ func loadMindMapService(r *gin.RouterGroup) {
 	// [GET] /api/biz/v1/mindmap/:id
 	r.Handle(GET, ":id", GetMindMap())
 
-	// 获取思维导图列表
+	// 获取思维导图列表（scope=owned/shared/all）
 	// [GET] /api/biz/v1/mindmap/list
 	r.Handle(GET, "list", ListMindMaps())
 
@@ -146,9 +155,197 @@ func loadMindMapService(r *gin.RouterGroup) {
 	// [PUT] /api/biz/v1/mindmap/:id
 	r.Handle(PUT, ":id", UpdateMindMap())
 
+	// 节点级增量更新思维导图
+	// [PATCH] /api/biz/v1/mindmap/:id
+	r.Handle(PATCH, ":id", PatchMindMap())
+
+	// 计算思维导图排版（节点坐标、连线与画布范围）
+	// [GET] /api/biz/v1/mindmap/:id/layout?layout=mindMap
+	r.Handle(GET, ":id/layout", ComputeMindMapLayout())
+
+	// 导出思维导图
+	// [GET] /api/biz/v1/mindmap/:id/export?format=markdown
+	r.Handle(GET, ":id/export", ExportMindMap())
+
+	// 从外部文件导入思维导图
+	// [POST] /api/biz/v1/mindmap/import
+	r.Handle(POST, "import", ImportMindMap())
+
 	// 批量删除思维导图
 	// [POST] /api/biz/v1/mindmap/batch_delete
 	r.Handle(POST, "batch_delete", BatchDeleteMindMap())
+
+	// 复制思维导图
+	// [POST] /api/biz/v1/mindmap/:id/duplicate
+	r.Handle(POST, ":id/duplicate", DuplicateMindMap())
+
+	// 将节点子树提取为新导图（可选替换为链接节点）
+	// [POST] /api/biz/v1/mindmap/:id/extract
+	r.Handle(POST, ":id/extract", ExtractMindMapSubtree())
+
+	// 将另一导图作为子树插入当前导图
+	// [POST] /api/biz/v1/mindmap/:id/graft
+	r.Handle(POST, ":id/graft", GraftMindMap())
+
+	// 获取导图（或节点）发出的跨导图链接
+	// [GET] /api/biz/v1/mindmap/:id/links?node_id=xxx
+	r.Handle(GET, ":id/links", ListMindMapNodeLinks())
+
+	// 在节点上创建指向其他导图或节点的链接
+	// [POST] /api/biz/v1/mindmap/:id/links
+	r.Handle(POST, ":id/links", CreateMindMapNodeLink())
+
+	// 删除节点链接
+	// [DELETE] /api/biz/v1/mindmap/:id/links/:link_id
+	r.Handle(DELETE, ":id/links/:link_id", DeleteMindMapNodeLink())
+
+	// 获取指向导图（或节点）的反向链接
+	// [GET] /api/biz/v1/mindmap/:id/backlinks?node_id=xxx
+	r.Handle(GET, ":id/backlinks", ListMindMapBacklinks())
+
+	// 检查思维导图的结构与文本问题
+	// [GET] /api/biz/v1/mindmap/:id/lint
+	r.Handle(GET, ":id/lint", LintMindMap())
+
+	// 获取文件夹列表
+	// [GET] /api/biz/v1/mindmap/folders
+	r.Handle(GET, "folders", ListMindMapFolders())
+
+	// 创建文件夹
+	// [POST] /api/biz/v1/mindmap/folders
+	r.Handle(POST, "folders", CreateMindMapFolder())
+
+	// 重命名或移动文件夹
+	// [PUT] /api/biz/v1/mindmap/folders/:folder_id
+	r.Handle(PUT, "folders/:folder_id", UpdateMindMapFolder())
+
+	// 删除文件夹（含子文件夹，其中的导图移出为未归档）
+	// [DELETE] /api/biz/v1/mindmap/folders/:folder_id
+	r.Handle(DELETE, "folders/:folder_id", DeleteMindMapFolder())
+
+	// 批量移动思维导图到文件夹
+	// [POST] /api/biz/v1/mindmap/move
+	r.Handle(POST, "move", MoveMindMaps())
+
+	// 获取标签列表
+	// [GET] /api/biz/v1/mindmap/tags
+	r.Handle(GET, "tags", ListMindMapTags())
+
+	// 创建标签
+	// [POST] /api/biz/v1/mindmap/tags
+	r.Handle(POST, "tags", CreateMindMapTag())
+
+	// 修改标签
+	// [PUT] /api/biz/v1/mindmap/tags/:tag_id
+	r.Handle(PUT, "tags/:tag_id", UpdateMindMapTag())
+
+	// 删除标签
+	// [DELETE] /api/biz/v1/mindmap/tags/:tag_id
+	r.Handle(DELETE, "tags/:tag_id", DeleteMindMapTag())
+
+	// 批量为思维导图添加标签
+	// [POST] /api/biz/v1/mindmap/tags/batch_add
+	r.Handle(POST, "tags/batch_add", TagMindMaps())
+
+	// 批量移除思维导图的标签
+	// [POST] /api/biz/v1/mindmap/tags/batch_remove
+	r.Handle(POST, "tags/batch_remove", UntagMindMaps())
+
+	// 获取模板列表（内置模板及自己保存的模板）
+	// [GET] /api/biz/v1/mindmap/templates
+	r.Handle(GET, "templates", ListMindMapTemplates())
+
+	// 获取模板详情
+	// [GET] /api/biz/v1/mindmap/templates/:template_id
+	r.Handle(GET, "templates/:template_id", GetMindMapTemplate())
+
+	// 将导图保存为模板
+	// [POST] /api/biz/v1/mindmap/templates
+	r.Handle(POST, "templates", SaveMindMapAsTemplate())
+
+	// 删除自己保存的模板
+	// [DELETE] /api/biz/v1/mindmap/templates/:template_id
+	r.Handle(DELETE, "templates/:template_id", DeleteMindMapTemplate())
+
+	// 基于模板创建导图（可由AI填充占位节点）
+	// [POST] /api/biz/v1/mindmap/templates/:template_id/create
+	r.Handle(POST, "templates/:template_id/create", CreateMindMapFromTemplate())
+
+	// 全文检索思维导图标题及节点文本
+	// [GET] /api/biz/v1/mindmap/search?q=
+	r.Handle(GET, "search", SearchMindMaps())
+
+	// 获取回收站中的思维导图列表
+	// [GET] /api/biz/v1/mindmap/trash
+	r.Handle(GET, "trash", ListTrashedMindMaps())
+
+	// 从回收站恢复思维导图
+	// [POST] /api/biz/v1/mindmap/trash/restore
+	r.Handle(POST, "trash/restore", RestoreMindMap())
+
+	// 彻底删除回收站中的思维导图（同时清除其AI会话）
+	// [POST] /api/biz/v1/mindmap/trash/purge
+	r.Handle(POST, "trash/purge", PurgeMindMap())
+
+	// 获取思维导图历史版本列表
+	// [GET] /api/biz/v1/mindmap/:id/revisions
+	r.Handle(GET, ":id/revisions", ListMindMapRevisions())
+
+	// 获取思维导图指定历史版本
+	// [GET] /api/biz/v1/mindmap/:id/revisions/:version
+	r.Handle(GET, ":id/revisions/:version", GetMindMapRevision())
+
+	// 恢复思维导图到指定历史版本
+	// [POST] /api/biz/v1/mindmap/:id/revisions/:version/restore
+	r.Handle(POST, ":id/revisions/:version/restore", RestoreMindMapRevision())
+
+	// 比较思维导图两个版本之间的结构差异
+	// [GET] /api/biz/v1/mindmap/:id/diff?from=1&to=2
+	r.Handle(GET, ":id/diff", DiffMindMapRevisions())
+
+	// 比较思维导图指定版本与任意节点树之间的结构差异
+	// [POST] /api/biz/v1/mindmap/:id/diff
+	r.Handle(POST, ":id/diff", DiffMindMapWithData())
+
+	// 创建只读分享链接
+	// [POST] /api/biz/v1/mindmap/:id/shares
+	r.Handle(POST, ":id/shares", CreateMindMapShare())
+
+	// 获取分享链接列表
+	// [GET] /api/biz/v1/mindmap/:id/shares
+	r.Handle(GET, ":id/shares", ListMindMapShares())
+
+	// 撤销分享链接
+	// [DELETE] /api/biz/v1/mindmap/:id/shares/:share_id
+	r.Handle(DELETE, ":id/shares/:share_id", RevokeMindMapShare())
+
+	// 获取协作者列表
+	// [GET] /api/biz/v1/mindmap/:id/collaborators
+	r.Handle(GET, ":id/collaborators", ListMindMapCollaborators())
+
+	// 通过邮箱/手机号邀请协作者
+	// [POST] /api/biz/v1/mindmap/:id/collaborators
+	r.Handle(POST, ":id/collaborators", AddMindMapCollaborator())
+
+	// 修改协作者角色
+	// [PUT] /api/biz/v1/mindmap/:id/collaborators/:user_id
+	r.Handle(PUT, ":id/collaborators/:user_id", UpdateMindMapCollaborator())
+
+	// 移除协作者（协作者本人可退出）
+	// [DELETE] /api/biz/v1/mindmap/:id/collaborators/:user_id
+	r.Handle(DELETE, ":id/collaborators/:user_id", RemoveMindMapCollaborator())
+}
+
+func loadMindMapCollabService(r *gin.RouterGroup) {
+	// 加入思维导图实时协同编辑（升级为WebSocket）
+	// [GET] /api/biz/v1/mindmap/:id/collab
+	r.Handle(GET, ":id/collab", MindMapCollab())
+}
+
+func loadShareService(r *gin.RouterGroup) {
+	// 通过分享令牌只读访问思维导图，密码通过 X-Share-Password 请求头传递
+	// [GET] /api/biz/v1/share/:token
+	r.Handle(GET, ":token", middleware.SharePasswordRateLimiter(), GetSharedMindMap())
 }
 
 func loadGenerationService(r *gin.RouterGroup) {
@@ -210,6 +407,10 @@ func loadAiChat(r *gin.RouterGroup) {
 	// [GET] /api/biz/v1/aichat/get_conversation?conversation_id=
 	r.Handle(GET, "get_conversation", GetConversation())
 
+	//切换会话的当前分支（编辑、重新生成消息会产生分支）
+	// [POST] /api/biz/v1/aichat/switch_message_branch
+	r.Handle(POST, "switch_message_branch", SwitchMessageBranch())
+
 	//更新某个会话的标题
 	// [POST] /api/biz/v1/aichat/update_conversation_title
 	r.Handle(POST, "update_conversation_title", UpdateConversationTitle())
@@ -230,4 +431,16 @@ func loadAiChat(r *gin.RouterGroup) {
 	// 手动触发质量评估
 	// [POST] /api/biz/v1/aichat/trigger_quality_assessment
 	r.Handle(POST, "trigger_quality_assessment", TriggerQualityAssessment())
+
+	// 获取某会话的导图修改建议
+	// [GET] /api/biz/v1/aichat/get_map_proposal_list?conversation_id=
+	r.Handle(GET, "get_map_proposal_list", GetMapProposalList())
+
+	// 采纳导图修改建议（写入导图）
+	// [POST] /api/biz/v1/aichat/accept_map_proposal
+	r.Handle(POST, "accept_map_proposal", AcceptMapProposal())
+
+	// 拒绝导图修改建议
+	// [POST] /api/biz/v1/aichat/reject_map_proposal
+	r.Handle(POST, "reject_map_proposal", RejectMapProposal())
 }
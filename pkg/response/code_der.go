@@ -48,9 +48,36 @@ var (
 	INSUFFICENT_PERMISSIONS = MsgCode{Code: 2200, Msg: "权限不足"}
 
 	/* 思维导图错误 3000 ~ 3999 */
-	MINDMAP_NOT_FOUND         = MsgCode{Code: 3001, Msg: "思维导图不存在"}
-	MINDMAP_ALREADY_EXISTS    = MsgCode{Code: 3002, Msg: "思维导图已存在"}
-	MINDMAP_PERMISSION_DENIED = MsgCode{Code: 3003, Msg: "思维导图权限不足"}
+	MINDMAP_NOT_FOUND          = MsgCode{Code: 3001, Msg: "思维导图不存在"}
+	MINDMAP_ALREADY_EXISTS     = MsgCode{Code: 3002, Msg: "思维导图已存在"}
+	MINDMAP_PERMISSION_DENIED  = MsgCode{Code: 3003, Msg: "思维导图权限不足"}
+	MINDMAP_VERSION_CONFLICT   = MsgCode{Code: 3004, Msg: "思维导图已被修改，请刷新后重试"}
+	MINDMAP_REVISION_NOT_FOUND = MsgCode{Code: 3005, Msg: "思维导图历史版本不存在"}
+	MINDMAP_NODE_NOT_FOUND     = MsgCode{Code: 3006, Msg: "思维导图节点不存在"}
+	MINDMAP_INVALID_NODE_OP    = MsgCode{Code: 3007, Msg: "思维导图节点操作无效"}
+	MINDMAP_UNSUPPORTED_FORMAT = MsgCode{Code: 3008, Msg: "不支持的文件格式"}
+	MINDMAP_IMPORT_FAILED      = MsgCode{Code: 3009, Msg: "思维导图文件解析失败"}
+	MINDMAP_SHARE_NOT_FOUND    = MsgCode{Code: 3010, Msg: "分享链接不存在或已失效"}
+	MINDMAP_SHARE_EXPIRED      = MsgCode{Code: 3011, Msg: "分享链接已过期"}
+	MINDMAP_SHARE_NEED_PASSWD  = MsgCode{Code: 3012, Msg: "该分享需要访问密码"}
+	MINDMAP_SHARE_WRONG_PASSWD = MsgCode{Code: 3013, Msg: "分享访问密码错误"}
+	MINDMAP_INVITEE_NOT_FOUND  = MsgCode{Code: 3014, Msg: "被邀请的用户不存在"}
+	MINDMAP_COLLAB_NOT_FOUND   = MsgCode{Code: 3015, Msg: "协作者不存在"}
+	MINDMAP_COLLAB_SESSION_BAD = MsgCode{Code: 3016, Msg: "协同会话不存在或已过期"}
+	MINDMAP_COLLAB_NEED_RESYNC = MsgCode{Code: 3017, Msg: "协同基线版本已失效，请重新同步导图"}
+	MINDMAP_FOLDER_NOT_FOUND   = MsgCode{Code: 3018, Msg: "文件夹不存在"}
+	MINDMAP_FOLDER_NAME_EXISTS = MsgCode{Code: 3019, Msg: "同一目录下已存在同名文件夹"}
+	MINDMAP_FOLDER_BAD_PARENT  = MsgCode{Code: 3020, Msg: "不能将文件夹移动到自身或其子文件夹下"}
+	MINDMAP_FOLDER_TOO_DEEP    = MsgCode{Code: 3021, Msg: "文件夹层级过深"}
+	MINDMAP_TAG_NOT_FOUND      = MsgCode{Code: 3022, Msg: "标签不存在"}
+	MINDMAP_TAG_NAME_EXISTS    = MsgCode{Code: 3023, Msg: "标签名称已存在"}
+	MINDMAP_TEMPLATE_NOT_FOUND = MsgCode{Code: 3024, Msg: "模板不存在"}
+	MINDMAP_TEMPLATE_LIMIT     = MsgCode{Code: 3025, Msg: "模板数量已达上限"}
+	MINDMAP_TEMPLATE_FILL_FAIL = MsgCode{Code: 3026, Msg: "模板智能填充失败，请稍后重试"}
+	MINDMAP_LINK_NOT_FOUND     = MsgCode{Code: 3027, Msg: "链接不存在"}
+	MINDMAP_LINK_EXISTS        = MsgCode{Code: 3028, Msg: "该节点已存在指向同一目标的链接"}
+	MINDMAP_UNSUPPORTED_LAYOUT = MsgCode{Code: 3029, Msg: "不支持的布局类型"}
+	MINDMAP_EXPORT_FONT_MISS   = MsgCode{Code: 3030, Msg: "服务端未配置中文字体，暂不支持导出PDF"}
 
 	/* COS错误 4000 ~ 4999 */
 	COS_INVALID_RESOURCE_PATH  = MsgCode{Code: 4001, Msg: "无效的资源路径"}
@@ -61,7 +88,6 @@ var (
 	/* ai对话错误 5000~5999 */
 
 	INVALID_CONTENT_TYPE        = MsgCode{Code: 5000, Msg: "只接受 application/json 或 multipart/form-data"}
-	AI_CHAT_MESSAGE_MAX         = MsgCode{Code: 5001, Msg: "会话长度已达上限，请开启新的会话"}
 	CONVERSATION_ID_NOT_NULL    = MsgCode{Code: 5200, Msg: "会话ID不能为空"}
 	USER_ID_NOT_NULL            = MsgCode{Code: 5201, Msg: "用户ID不能为空"}
 	MAP_ID_NOT_NULL             = MsgCode{Code: 5202, Msg: "导图ID不能为空"}
@@ -69,6 +95,11 @@ var (
 	CONVERSATION_NOT_EXIST      = MsgCode{Code: 5204, Msg: "该会话不存在"}
 	AI_CHAT_PERMISSION_DENIED   = MsgCode{Code: 5205, Msg: "会话权限不足"}
 	MIND_MAP_NOT_EXIST          = MsgCode{Code: 5206, Msg: "该导图不存在"}
+	MAP_PROPOSAL_NOT_EXIST      = MsgCode{Code: 5207, Msg: "该修改建议不存在"}
+	MAP_PROPOSAL_RESOLVED       = MsgCode{Code: 5208, Msg: "该修改建议已处理"}
+	MESSAGE_CONTENT_NOT_NULL    = MsgCode{Code: 5209, Msg: "消息内容不能为空"}
+	MESSAGE_NOT_EXIST           = MsgCode{Code: 5210, Msg: "该消息不存在"}
+	MESSAGE_NOT_EDITABLE        = MsgCode{Code: 5211, Msg: "只能编辑用户消息"}
 
 	/* 限流错误 */
 	TOO_MANY_REQUESTS = MsgCode{Code: 429, Msg: "请求过于频繁，请稍后再试"}
@@ -0,0 +1,161 @@
+package tokenizer
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTokenBytes 单个词元的最大字节数，超出部分截断（与索引表 token 列长度一致）
+const MaxTokenBytes = 64
+
+// Normalize 文本归一化：全角转半角、转小写；逐字符转换，归一化前后字符数一致，便于回溯原文位置
+func Normalize(text string) []rune {
+	runes := []rune(text)
+	for i, r := range runes {
+		runes[i] = normalizeRune(r)
+	}
+	return runes
+}
+
+// Tokenize 索引分词：中日韩文本同时切分为单字和二元组（保证单字查询也能命中），其余文字按字母数字连续串切分
+// 返回结果保留重复词元，可用于统计词频
+func Tokenize(text string) []string {
+	return tokenize(Normalize(text), true)
+}
+
+// QueryTokens 查询分词：中日韩文本只切分为二元组（单字时保留单字），结果去重并保持出现顺序
+func QueryTokens(text string) []string {
+	tokens := tokenize(Normalize(text), false)
+	seen := make(map[string]struct{}, len(tokens))
+	unique := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		unique = append(unique, token)
+	}
+	return unique
+}
+
+// Span 词元在文本中的位置（按字符计，左闭右开）
+type Span struct {
+	Start int
+	End   int
+}
+
+// MatchSpans 查找 tokens 在 text 中的全部出现位置，重叠或相邻的位置合并后按起始位置升序返回
+func MatchSpans(text string, tokens []string) []Span {
+	runes := Normalize(text)
+	spans := make([]Span, 0)
+	for _, token := range tokens {
+		pattern := []rune(token)
+		if len(pattern) == 0 {
+			continue
+		}
+		for i := 0; i+len(pattern) <= len(runes); i++ {
+			if runesHasPrefix(runes[i:], pattern) {
+				spans = append(spans, Span{Start: i, End: i + len(pattern)})
+			}
+		}
+	}
+	return mergeSpans(spans)
+}
+
+func tokenize(runes []rune, withUnigrams bool) []string {
+	tokens := make([]string, 0, len(runes))
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case isCJK(r):
+			j := i
+			for j < len(runes) && isCJK(runes[j]) {
+				j++
+			}
+			tokens = appendCJKTokens(tokens, runes[i:j], withUnigrams)
+			i = j
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && !isCJK(runes[j]) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, truncate(string(runes[i:j])))
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+func appendCJKTokens(tokens []string, run []rune, withUnigrams bool) []string {
+	if len(run) == 1 {
+		return append(tokens, string(run))
+	}
+	for i := range run {
+		if withUnigrams {
+			tokens = append(tokens, string(run[i]))
+		}
+		if i+1 < len(run) {
+			tokens = append(tokens, string(run[i:i+2]))
+		}
+	}
+	return tokens
+}
+
+func normalizeRune(r rune) rune {
+	switch {
+	case r == '　':
+		r = ' '
+	case r >= '！' && r <= '～':
+		r -= 0xFEE0
+	}
+	return unicode.ToLower(r)
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func truncate(token string) string {
+	if len(token) <= MaxTokenBytes {
+		return token
+	}
+	end := MaxTokenBytes
+	for end > 0 && !utf8.RuneStart(token[end]) {
+		end--
+	}
+	return token[:end]
+}
+
+func runesHasPrefix(runes, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if runes[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeSpans(spans []Span) []Span {
+	if len(spans) <= 1 {
+		return spans
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	merged := []Span{spans[0]}
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span.Start <= last.End {
+			if span.End > last.End {
+				last.End = span.End
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}